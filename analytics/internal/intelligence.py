@@ -57,16 +57,18 @@ class IntelligenceEngine:
 
     def get_unit_performance(self) -> List[Dict[str, Any]]:
         """
-        Calculates performance scores for competitive unit leaderboards.
+        Ranks districts and upazilas for competitive unit leaderboards by this month's score,
+        materialised by the API from verified activities only.
         """
         query = text("""
-            SELECT j.name, count(a.id) * 10 + count(u.id) as score
-            FROM jurisdictions j
-            LEFT JOIN activities a ON a.jurisdiction_id = j.id
-            LEFT JOIN users u ON u.jurisdiction_id = j.id
-            WHERE j.level IN ('district', 'upazila')
-            GROUP BY j.name
-            ORDER BY score DESC
+            SELECT j.name, js.total_score::float as score
+            FROM jurisdiction_monthly_scores js
+            JOIN jurisdictions j ON js.jurisdiction_id = j.id
+            JOIN jurisdiction_levels jl ON j.level_id = jl.id
+            WHERE js.month = date_trunc('month', NOW())::date
+              AND jl.name IN ('District', 'Upazila')
+              AND j.deleted_at IS NULL
+            ORDER BY js.total_score DESC
             LIMIT 10
         """)
         
@@ -74,3 +76,37 @@ class IntelligenceEngine:
             df = pd.read_sql(query, conn)
             
         return df.to_dict(orient="records")
+
+    def get_overdue_tasks(self) -> List[Dict[str, Any]]:
+        """
+        Counts open overdue tasks per district, including all jurisdictions below it.
+        """
+        query = text("""
+            WITH RECURSIVE district_tree AS (
+                SELECT j.id, j.id as district_id
+                FROM jurisdictions j
+                JOIN jurisdiction_levels jl ON j.level_id = jl.id
+                WHERE jl.name = 'District' AND j.deleted_at IS NULL
+                UNION ALL
+                SELECT c.id, t.district_id
+                FROM jurisdictions c
+                JOIN district_tree t ON c.parent_id = t.id
+                WHERE c.deleted_at IS NULL
+            )
+            SELECT j.name as district_name,
+                   count(tk.id) as overdue_count,
+                   count(tk.id) FILTER (WHERE tk.escalation_level >= 1) as escalated_count
+            FROM district_tree dt
+            JOIN jurisdictions j ON dt.district_id = j.id
+            JOIN tasks tk ON tk.jurisdiction_id = dt.id
+            WHERE tk.status IN ('pending', 'in_progress')
+              AND tk.due_date < NOW()
+              AND tk.deleted_at IS NULL
+            GROUP BY j.name
+            ORDER BY overdue_count DESC
+        """)
+
+        with self.engine.connect() as conn:
+            df = pd.read_sql(query, conn)
+
+        return df.to_dict(orient="records")
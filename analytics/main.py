@@ -40,13 +40,15 @@ async def get_organizational_pulse():
         growth = engine.get_growth_velocity()
         heatmap = engine.get_heatmap_data()
         performance = engine.get_unit_performance()
+        overdue = engine.get_overdue_tasks()
         
         return {
             "status": "success",
             "data": {
                 "growth_velocity": growth,
                 "heatmap": heatmap,
-                "top_performing_units": performance
+                "top_performing_units": performance,
+                "overdue_tasks": overdue
             }
         }
     except Exception as e:
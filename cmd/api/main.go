@@ -10,16 +10,23 @@ import (
 	"time"
 
 	"github.com/bjdms/api/config"
+	"github.com/bjdms/api/internal/abuse"
 	"github.com/bjdms/api/internal/activity"
 	"github.com/bjdms/api/internal/analytics"
 	"github.com/bjdms/api/internal/auth"
+	"github.com/bjdms/api/internal/calendar"
 	"github.com/bjdms/api/internal/notification"
+	"github.com/bjdms/api/internal/comment"
 	"github.com/bjdms/api/internal/committee"
+	"github.com/bjdms/api/internal/election"
 	"github.com/bjdms/api/internal/complaint"
 	"github.com/bjdms/api/internal/finance"
 	"github.com/bjdms/api/internal/join"
+	"github.com/bjdms/api/internal/media"
 	"github.com/bjdms/api/internal/search"
 	"github.com/bjdms/api/internal/database"
+	"github.com/bjdms/api/pkg/pdf"
+	"github.com/bjdms/api/pkg/storage"
 	internalMiddleware "github.com/bjdms/api/internal/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -53,25 +60,73 @@ func main() {
 		cfg.JWTRefreshExpiry,
 	)
 
+	// File storage for proofs, evidence and receipts
+	fileStore, err := storage.New(storage.Config{
+		Backend:          cfg.StorageBackend,
+		LocalPath:        cfg.StorageLocalPath,
+		PublicURL:        cfg.StoragePublicURL,
+		SigningKey:       cfg.StorageSigningKey,
+		S3Endpoint:       cfg.StorageS3Endpoint,
+		S3PublicEndpoint: cfg.StorageS3PublicURL,
+		S3Region:         cfg.StorageS3Region,
+		S3Bucket:         cfg.StorageS3Bucket,
+		S3AccessKey:      cfg.StorageS3AccessKey,
+		S3SecretKey:      cfg.StorageS3SecretKey,
+		S3PathStyle:      cfg.StorageS3PathStyle,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize file storage: %v", err)
+	}
+	uploader := storage.NewUploader(fileStore, cfg.UploadMaxBytes, cfg.SignedURLExpiry)
+
+	// Background media pipeline (metadata stripping, thumbnails)
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go media.NewWorker(media.NewRepository(db.Pool), fileStore).Run(workerCtx)
+
 	// Initialize repositories and services
 	authRepo := auth.NewRepository(db.Pool)
 	authService := auth.NewService(authRepo, redisMgr, jwtMgr, cfg)
 	authHandler := auth.NewHandler(authService, redisMgr, jwtMgr)
 
 	committeeRepo := committee.NewRepository(db.Pool)
-	committeeService := committee.NewService(committeeRepo)
-	committeeHandler := committee.NewHandler(committeeService)
+	committeeService := committee.NewService(committeeRepo, authRepo, redisMgr.Client())
+	committeeHandler := committee.NewHandler(committeeService, pdf.NewRenderer(cfg.PDFRendererBin))
+
+	electionRepo := election.NewRepository(db.Pool)
+	electionService := election.NewService(electionRepo, committeeService, authRepo)
+	electionHandler := election.NewHandler(electionService)
 
 	notificationService := notification.NewService(db.Pool, redisMgr.Client())
 	notificationHandler := notification.NewHandler(notificationService)
 
 	activityRepo := activity.NewRepository(db.Pool)
-	activityService := activity.NewService(activityRepo, notificationService)
-	activityHandler := activity.NewHandler(activityService)
+	activityService := activity.NewService(activityRepo, notificationService, uploader, authRepo, committeeService, activity.NewCheckInSigner(cfg.EventCheckInSecret))
+	activityHandler := activity.NewHandler(activityService, uploader)
+
+	// Task deadline reminders, overdue escalation and recurring task generation
+	escalationPolicy, err := activity.ParseEscalationPolicy(cfg.TaskEscalationPolicy)
+	if err != nil {
+		log.Fatalf("Invalid TASK_ESCALATION_POLICY: %v", err)
+	}
+	go activity.NewEscalator(activityRepo, notificationService, escalationPolicy, cfg.TaskEscalationInterval).Run(workerCtx)
+	go activity.NewScheduler(activityService).Run(workerCtx)
+
+	// Screening of public complaint and join submissions
+	guard := abuse.NewGuard(abuse.NewRepository(db.Pool), cfg.PublicFingerprintSecret, cfg.PublicFingerprintRotation, cfg.PublicPowDifficulty)
+	go guard.Run(workerCtx)
+	challengeHandler := abuse.NewHandler(guard)
 
 	complaintRepo := complaint.NewRepository(db.Pool)
-	complaintService := complaint.NewService(complaintRepo, notificationService)
-	complaintHandler := complaint.NewHandler(complaintService)
+	complaintSLA, err := complaint.ParseSLAPolicy(cfg.ComplaintSLAPolicy)
+	if err != nil {
+		log.Fatalf("Invalid COMPLAINT_SLA_POLICY: %v", err)
+	}
+	complaintService := complaint.NewService(complaintRepo, notificationService, uploader, authRepo, committeeService, complaintSLA, guard)
+	complaintHandler := complaint.NewHandler(complaintService, uploader)
+
+	// Complaint SLA escalation to the parent jurisdiction
+	go complaint.NewSLAMonitor(complaintService, cfg.ComplaintSLAInterval).Run(workerCtx)
 
 	searchClient, err := search.NewClient(cfg.OpenSearchURL)
 	if err == nil {
@@ -81,23 +136,37 @@ func main() {
 	searchHandler := search.NewHandler(searchService)
 
 	financeRepo := finance.NewRepository(db.Pool)
-	financeService := finance.NewService(financeRepo)
-	financeHandler := finance.NewHandler(financeService)
+	financeService := finance.NewService(financeRepo, uploader)
+	financeHandler := finance.NewHandler(financeService, uploader)
 
 
 	analyticsClient := analytics.NewClient()
 	analyticsHandler := analytics.NewHandler(analyticsClient)
 
 	joinRepo := join.NewRepository(db.Pool)
-	joinService := join.NewService(joinRepo, authRepo, notificationService)
+	joinService := join.NewService(joinRepo, authRepo, notificationService, guard, committeeService)
 	joinHandler := join.NewHandler(joinService)
 
+	commentRepo := comment.NewRepository(db.Pool)
+	commentService := comment.NewService(commentRepo, notificationService, authRepo, complaintService)
+	commentHandler := comment.NewHandler(commentService)
+
+	calendarRepo := calendar.NewRepository(db.Pool)
+	calendarService := calendar.NewService(calendarRepo, authRepo, committeeService)
+	calendarHandler := calendar.NewHandler(calendarService)
+
 	// Setup router
 	r := chi.NewRouter()
 
+	// Forwarding headers are only believed from the configured proxies
+	trustedProxies, err := internalMiddleware.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Middleware
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(trustedProxies.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
@@ -134,13 +203,28 @@ func main() {
 			// Committees & Jurisdictions
 			r.Mount("/org", committeeHandler.Routes())
 
+			// Organisation administration (Super Admin only)
+			r.Group(func(r chi.Router) {
+				r.Use(internalMiddleware.SuperAdminMiddleware(authRepo))
+				r.Mount("/admin/org", committeeHandler.AdminRoutes())
+			})
+
+			// Committee elections
+			r.Mount("/elections", electionHandler.Routes())
+
 			// Activities & Tasks
 			r.Mount("/activities", activityHandler.Routes())
 
+			// Discussion threads on tasks, activities, complaints and join requests
+			r.Mount("/comments", commentHandler.Routes())
+
+			// Calendar feed subscriptions
+			r.Mount("/calendar", calendarHandler.Routes())
+
 			// Complaints Management (Internal)
 			r.Group(func(r chi.Router) {
 				r.Use(internalMiddleware.ABACJurisdictionMiddleware(committeeService, authRepo))
-				r.Mount("/complaints", complaintHandler.Routes())
+				r.Mount("/complaints", complaintHandler.InternalRoutes())
 			})
 
 			// Search (Global)
@@ -158,9 +242,18 @@ func main() {
 			// r.Mount("/users", userHandler.Routes())
 		})
 
-		// Public Anonymous Complaints
-		r.Mount("/public/complaints", complaintHandler.Routes())
+		// Public Anonymous Complaints (rate limited by the handler)
+		r.Mount("/public/complaints", complaintHandler.PublicRoutes())
 		r.Mount("/public/join", joinHandler.PublicRoutes())
+		r.Mount("/public/calendar", calendarHandler.PublicRoutes())
+		r.Mount("/public/challenge", challengeHandler.PublicRoutes())
+
+		// Signed file downloads (local storage; S3 links point at the bucket directly)
+		if local, ok := fileStore.(*storage.LocalStore); ok {
+			r.Get("/files/*", local.DownloadHandler(func(r *http.Request) string {
+				return chi.URLParam(r, "*")
+			}))
+		}
 	})
 
 	// Start server
@@ -188,6 +281,7 @@ func main() {
 
 	case <-shutdown:
 		log.Println("Shutting down server...")
+		stopWorkers()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
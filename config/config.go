@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -43,6 +44,43 @@ type Config struct {
 	// Logging
 	LogLevel  string
 	LogFormat string
+
+	// Exports
+	PDFRendererBin string
+
+	// File storage
+	StorageBackend     string
+	StorageLocalPath   string
+	StoragePublicURL   string
+	StorageSigningKey  string
+	StorageS3Endpoint  string
+	StorageS3PublicURL string
+	StorageS3Region    string
+	StorageS3Bucket    string
+	StorageS3AccessKey string
+	StorageS3SecretKey string
+	StorageS3PathStyle bool
+	UploadMaxBytes     int64
+	SignedURLExpiry    time.Duration
+
+	// Task deadlines
+	TaskEscalationPolicy   string
+	TaskEscalationInterval time.Duration
+
+	// Complaint SLAs
+	ComplaintSLAPolicy   string
+	ComplaintSLAInterval time.Duration
+
+	// Event check-in
+	EventCheckInSecret string
+
+	// Public submission screening
+	PublicFingerprintSecret   string
+	PublicFingerprintRotation time.Duration
+	PublicPowDifficulty       int
+
+	// Proxies whose X-Forwarded-For and X-Real-IP headers are believed
+	TrustedProxies string
 }
 
 // Load loads configuration from environment variables
@@ -70,6 +108,33 @@ func Load() *Config {
 		MaxConcurrentSessions: 3,
 		LogLevel:             getEnv("LOG_LEVEL", "info"),
 		LogFormat:            getEnv("LOG_FORMAT", "json"),
+		PDFRendererBin:       getEnv("PDF_RENDERER_BIN", "chromium-browser"),
+		StorageBackend:       getEnv("STORAGE_BACKEND", "local"),
+		StorageLocalPath:     getEnv("STORAGE_LOCAL_PATH", "./data/uploads"),
+		StoragePublicURL:     getEnv("STORAGE_PUBLIC_URL", "/api/v1/files"),
+		StorageSigningKey:    getEnv("STORAGE_SIGNING_KEY", ""),
+		StorageS3Endpoint:    getEnv("S3_ENDPOINT", ""),
+		StorageS3PublicURL:   getEnv("S3_PUBLIC_ENDPOINT", ""),
+		StorageS3Region:      getEnv("S3_REGION", "us-east-1"),
+		StorageS3Bucket:      getEnv("S3_BUCKET", "bjdms-files"),
+		StorageS3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+		StorageS3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+		StorageS3PathStyle:   getEnv("S3_PATH_STYLE", "true") == "true",
+		UploadMaxBytes:       getInt64("UPLOAD_MAX_BYTES", 20<<20),
+		SignedURLExpiry:      getDuration("SIGNED_URL_EXPIRY", "15m"),
+		// Per priority: reminder lead, delay before the committee leadership and before the parent jurisdiction
+		TaskEscalationPolicy:   getEnv("TASK_ESCALATION_POLICY", "1=6h,0s,24h;2=24h,24h,72h;3=48h,72h,168h;4=72h,168h,336h"),
+		TaskEscalationInterval: getDuration("TASK_ESCALATION_INTERVAL", "15m"),
+		// Time allowed in each open status, and from submission to closing or rejecting
+		ComplaintSLAPolicy:     getEnv("COMPLAINT_SLA_POLICY", "received=72h;under_review=336h;action_taken=336h;resolution=720h"),
+		ComplaintSLAInterval:   getDuration("COMPLAINT_SLA_INTERVAL", "15m"),
+		EventCheckInSecret:     getEnv("EVENT_CHECKIN_SECRET", ""),
+		PublicFingerprintSecret:   getEnv("PUBLIC_FINGERPRINT_SECRET", ""),
+		PublicFingerprintRotation: getDuration("PUBLIC_FINGERPRINT_ROTATION", "24h"),
+		// Leading zero bits a public form's proof of work needs (0 disables it)
+		PublicPowDifficulty: int(getInt64("PUBLIC_POW_DIFFICULTY", 18)),
+		// Comma-separated addresses and CIDRs; empty trusts no proxy
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
 	}
 }
 
@@ -84,6 +149,26 @@ func (c *Config) Validate() error {
 	if c.JWTRefreshSecret == "" {
 		log.Fatal("JWT_REFRESH_SECRET is required")
 	}
+	if c.StorageBackend == "local" && c.StorageSigningKey == "" {
+		log.Fatal("STORAGE_SIGNING_KEY is required for local file storage")
+	}
+	if c.EventCheckInSecret == "" {
+		log.Fatal("EVENT_CHECKIN_SECRET is required")
+	}
+	if c.PublicFingerprintSecret == "" {
+		log.Fatal("PUBLIC_FINGERPRINT_SECRET is required")
+	}
+	if c.PublicFingerprintRotation < time.Hour {
+		log.Fatal("PUBLIC_FINGERPRINT_ROTATION must be at least 1h")
+	}
+	if c.PublicPowDifficulty < 0 || c.PublicPowDifficulty > 32 {
+		log.Fatal("PUBLIC_POW_DIFFICULTY must be between 0 and 32")
+	}
+	// Production runs behind a reverse proxy; without trusting it every public client shares
+	// the proxy's address, and with it one fingerprint and one submission quota
+	if c.Environment == "production" && c.TrustedProxies == "" {
+		log.Fatal("TRUSTED_PROXIES is required in production")
+	}
 	return nil
 }
 
@@ -102,3 +187,15 @@ func getDuration(key, defaultValue string) time.Duration {
 	}
 	return duration
 }
+
+func getInt64(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid integer for %s: %v", key, err)
+	}
+	return n
+}
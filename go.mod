@@ -5,13 +5,13 @@ go 1.25
 require (
 	github.com/go-chi/chi/v5 v5.0.11
 	github.com/golang-jwt/jwt/v5 v5.2.0
-	github.com/jackc/pgx/v5 v5.5.1
-	github.com/redis/go-redis/v9 v9.4.0
-	golang.org/x/crypto v0.18.0
 	github.com/google/uuid v1.5.0
-	github.com/joho/godotenv v1.5.1
 	github.com/gorilla/websocket v1.5.1
+	github.com/jackc/pgx/v5 v5.5.1
+	github.com/joho/godotenv v1.5.1
 	github.com/opensearch-project/opensearch-go/v2 v2.3.0
+	github.com/redis/go-redis/v9 v9.4.0
+	golang.org/x/crypto v0.18.0
 )
 
 require (
@@ -20,6 +20,7 @@ require (
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20231201235250-de7065d80cb9 // indirect
 	github.com/jackc/puddle/v2 v2.2.1 // indirect
+	golang.org/x/net v0.17.0 // indirect
 	golang.org/x/sync v0.5.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 )
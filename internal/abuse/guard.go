@@ -0,0 +1,205 @@
+package abuse
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"log"
+	"math/bits"
+	"net/http"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+)
+
+// Kinds of public submission, each with its own quota
+const (
+	KindComplaint   = "complaint"
+	KindJoinRequest = "join_request"
+)
+
+// Screening settings
+const (
+	ChallengeTTL  = 10 * time.Minute // How long a proof-of-work challenge can be solved
+	pruneInterval = time.Hour
+)
+
+// Screening errors
+var (
+	ErrProofRequired = errors.New("a solved proof-of-work challenge is required, request a new one")
+	ErrQuotaExceeded = errors.New("too many submissions from this network, try again later")
+)
+
+// Quota limits the submissions of one kind from a fingerprint in one rotation period. From Flag
+// on they are held for moderation; beyond Limit they are refused.
+type Quota struct {
+	Flag  int
+	Limit int
+}
+
+// Form holds the screening fields a public form sends along with its content
+type Form struct {
+	Website   string `json:"website"` // Honeypot: hidden from people, filled in by bots
+	Challenge string `json:"pow_challenge"`
+	Nonce     string `json:"pow_nonce"`
+}
+
+// Verdict is the outcome of screening an accepted submission
+type Verdict struct {
+	Fingerprint string
+	Flagged     bool
+	Reason      string // Why it was flagged
+}
+
+// Guard screens public submissions without any third-party service: it fingerprints the sender,
+// enforces quotas per fingerprint, checks the proof of work and the honeypot field
+type Guard struct {
+	repo       *Repository
+	key        []byte
+	rotation   time.Duration
+	difficulty int // Leading zero bits a proof of work needs; 0 disables it
+}
+
+// NewGuard creates a submission guard. Fingerprints change every rotation period.
+func NewGuard(repo *Repository, secret string, rotation time.Duration, difficulty int) *Guard {
+	return &Guard{repo: repo, key: []byte(secret), rotation: rotation, difficulty: difficulty}
+}
+
+// Screen checks a submission of the given kind from ip. It returns ErrProofRequired or
+// ErrQuotaExceeded when the submission must be refused, and otherwise says whether it should
+// be held for moderation.
+func (g *Guard) Screen(ctx context.Context, kind string, q Quota, ip string, f Form) (*Verdict, error) {
+	now := time.Now()
+	if err := g.verifyProof(ctx, f.Challenge, f.Nonce, now); err != nil {
+		return nil, err
+	}
+
+	v := &Verdict{Fingerprint: g.Fingerprint(ip, now)}
+	count, err := g.repo.RecordSubmission(ctx, kind, v.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case count > q.Limit:
+		return nil, ErrQuotaExceeded
+	case f.Website != "":
+		v.Flagged, v.Reason = true, "honeypot"
+	case count > q.Flag:
+		v.Flagged, v.Reason = true, "volume"
+	}
+	return v, nil
+}
+
+// Fingerprint returns a keyed hash of ip for the rotation period containing now. Without the
+// key it cannot be reversed, and it does not link submissions across periods.
+func (g *Guard) Fingerprint(ip string, now time.Time) string {
+	mac := hmac.New(sha256.New, g.key)
+	mac.Write([]byte("ip"))
+	binary.Write(mac, binary.BigEndian, now.Unix()/int64(g.rotation/time.Second))
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Challenge issues a proof-of-work challenge. It is signed rather than stored, so any API
+// instance can verify it.
+func (g *Guard) Challenge(now time.Time) (*models.PowChallenge, error) {
+	expires := now.Add(ChallengeTTL)
+
+	buf := make([]byte, 24, 40)
+	binary.BigEndian.PutUint64(buf, uint64(expires.Unix()))
+	if _, err := rand.Read(buf[8:24]); err != nil {
+		return nil, err
+	}
+	buf = append(buf, g.sign(buf)...)
+
+	return &models.PowChallenge{
+		Challenge:  base64.RawURLEncoding.EncodeToString(buf),
+		Difficulty: g.difficulty,
+		ExpiresAt:  time.Unix(expires.Unix(), 0),
+	}, nil
+}
+
+// verifyProof checks that a challenge is ours, unexpired and solved, and claims it so the
+// solution cannot be reused
+func (g *Guard) verifyProof(ctx context.Context, challenge, nonce string, now time.Time) error {
+	if g.difficulty == 0 {
+		return nil
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(challenge)
+	if err != nil || len(buf) != 40 || nonce == "" || len(nonce) > 64 {
+		return ErrProofRequired
+	}
+	if !hmac.Equal(buf[24:], g.sign(buf[:24])) {
+		return ErrProofRequired
+	}
+	expires := time.Unix(int64(binary.BigEndian.Uint64(buf[:8])), 0)
+	if now.After(expires) {
+		return ErrProofRequired
+	}
+	if leadingZeroBits(sha256.Sum256([]byte(challenge+":"+nonce))) < g.difficulty {
+		return ErrProofRequired
+	}
+
+	sum := sha256.Sum256([]byte(challenge))
+	ok, err := g.repo.ClaimChallenge(ctx, hex.EncodeToString(sum[:]), expires)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrProofRequired
+	}
+	return nil
+}
+
+func (g *Guard) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, g.key)
+	mac.Write([]byte("pow"))
+	mac.Write(data)
+	return mac.Sum(nil)[:16]
+}
+
+// Run prunes submissions from past rotation periods and expired challenges until ctx is cancelled
+func (g *Guard) Run(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := g.repo.Prune(ctx, time.Now().Add(-g.rotation)); err != nil && ctx.Err() == nil {
+			log.Printf("abuse: pruning failed: %v", err)
+		}
+	}
+}
+
+// StatusError maps screening errors to an HTTP status code, or 0 for other errors
+func StatusError(err error) int {
+	switch {
+	case errors.Is(err, ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrProofRequired):
+		return http.StatusBadRequest
+	default:
+		return 0
+	}
+}
+
+func leadingZeroBits(sum [32]byte) int {
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
@@ -0,0 +1,44 @@
+package abuse
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bjdms/api/internal/middleware"
+	"github.com/bjdms/api/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// Challenges a client may request per challengeWindow, counted per IP
+const (
+	challengeLimit  = 30
+	challengeWindow = 15 * time.Minute
+)
+
+// Handler serves proof-of-work challenges to public forms
+type Handler struct {
+	guard   *Guard
+	limiter *middleware.RateLimiter
+}
+
+// NewHandler creates a new challenge handler
+func NewHandler(guard *Guard) *Handler {
+	return &Handler{guard: guard, limiter: middleware.NewRateLimiter(challengeLimit, challengeWindow)}
+}
+
+// PublicRoutes returns the challenge endpoint; it is rate limited here and needs no login
+func (h *Handler) PublicRoutes() chi.Router {
+	r := chi.NewRouter()
+	r.With(h.limiter.Limit).Get("/", h.Challenge)
+	return r
+}
+
+// Challenge handles GET /api/v1/public/challenge
+func (h *Handler) Challenge(w http.ResponseWriter, r *http.Request) {
+	c, err := h.guard.Challenge(time.Now())
+	if err != nil {
+		response.InternalError(w, "Failed to issue challenge", "")
+		return
+	}
+	response.Success(w, c, "")
+}
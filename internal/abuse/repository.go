@@ -0,0 +1,52 @@
+package abuse
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository handles database operations for submission screening
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new screening repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// RecordSubmission counts a submission against a fingerprint and returns how many of that kind
+// it has made, this one included. Fingerprints rotate, so only the current period is counted.
+func (r *Repository) RecordSubmission(ctx context.Context, kind, fingerprint string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		WITH added AS (
+			INSERT INTO public_submissions (kind, fingerprint) VALUES ($1, $2)
+		)
+		SELECT COUNT(*) + 1 FROM public_submissions WHERE kind = $1 AND fingerprint = $2
+	`, kind, fingerprint).Scan(&count)
+	return count, err
+}
+
+// ClaimChallenge marks a solved challenge as used. It returns false if it already was.
+func (r *Repository) ClaimChallenge(ctx context.Context, challengeHash string, expiresAt time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO pow_challenges_used (challenge_hash, expires_at) VALUES ($1, $2)
+		ON CONFLICT (challenge_hash) DO NOTHING
+	`, challengeHash, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Prune deletes submissions recorded before cutoff and challenges that have expired
+func (r *Repository) Prune(ctx context.Context, cutoff time.Time) error {
+	if _, err := r.db.Exec(ctx, "DELETE FROM public_submissions WHERE created_at < $1", cutoff); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(ctx, "DELETE FROM pow_challenges_used WHERE expires_at < NOW()")
+	return err
+}
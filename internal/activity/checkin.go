@@ -0,0 +1,344 @@
+package activity
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Check-in settings
+const (
+	CheckInTokenPeriod  = 30 * time.Second
+	MaxOfflineCheckIns  = 1000
+	checkInOpensBefore  = time.Hour     // Check-in opens this long before the start
+	checkInDefaultSpan  = 6 * time.Hour // Window length when the event has no end time
+	maxLocationAccuracy = 100.0         // Reported GPS accuracy credited towards the geofence, in meters
+	maxClockSkew        = 5 * time.Minute
+	earthRadiusMeters   = 6371000.0
+)
+
+// Check-in errors
+var (
+	ErrInvalidCheckInCode = errors.New("invalid check-in code")
+	ErrCheckInOtherEvent  = errors.New("check-in code belongs to another event")
+	ErrCheckInCodeExpired = errors.New("check-in code has expired, scan it again")
+	ErrCheckInNotOpen     = errors.New("check-in has not opened yet")
+	ErrCheckInClosed      = errors.New("check-in has closed")
+	ErrLocationRequired   = errors.New("location is required to check in to this event")
+	ErrOutsideGeofence    = errors.New("you are too far from the venue to check in")
+	ErrAlreadyCheckedIn   = errors.New("already checked in to this event")
+	ErrMemberNotFound     = errors.New("member not found")
+	ErrNoCheckIns         = errors.New("no check-ins to upload")
+	ErrTooManyCheckIns    = fmt.Errorf("at most %d check-ins can be uploaded at once", MaxOfflineCheckIns)
+)
+
+// Venue errors
+var (
+	ErrPartialCoordinates = errors.New("latitude and longitude must be given together")
+	ErrInvalidCoordinates = errors.New("invalid venue coordinates")
+	ErrInvalidGeofence    = errors.New("geofence_radius_m must be positive")
+	ErrGeofenceNeedsVenue = errors.New("a geofence needs the venue's latitude and longitude")
+)
+
+// CheckInSigner issues and verifies the rotating QR tokens used for self check-in. A token names
+// the event and a 30-second time slot, signed with a server secret, so it cannot be forged for
+// another event and a photo of the code stops working within a minute.
+type CheckInSigner struct {
+	key []byte
+}
+
+// NewCheckInSigner creates a token signer
+func NewCheckInSigner(secret string) *CheckInSigner {
+	return &CheckInSigner{key: []byte(secret)}
+}
+
+// Token returns the event's token for the slot containing now
+func (cs *CheckInSigner) Token(eventID uuid.UUID, now time.Time) *models.CheckInToken {
+	slot := now.Unix() / int64(CheckInTokenPeriod/time.Second)
+
+	buf := make([]byte, 0, 40)
+	buf = append(buf, eventID[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(slot))
+	buf = append(buf, cs.sign(eventID, slot)...)
+
+	return &models.CheckInToken{
+		Token:     base64.RawURLEncoding.EncodeToString(buf),
+		ExpiresAt: time.Unix((slot+1)*int64(CheckInTokenPeriod/time.Second), 0),
+		Period:    int(CheckInTokenPeriod / time.Second),
+	}
+}
+
+// Verify checks that a token belongs to the event and to the current or previous slot;
+// the previous one is accepted so a code scanned just before it rotates still works
+func (cs *CheckInSigner) Verify(token string, eventID uuid.UUID, now time.Time) error {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) != 40 {
+		return ErrInvalidCheckInCode
+	}
+	if !bytes.Equal(buf[:16], eventID[:]) {
+		return ErrCheckInOtherEvent
+	}
+	slot := int64(binary.BigEndian.Uint64(buf[16:24]))
+	if !hmac.Equal(buf[24:], cs.sign(eventID, slot)) {
+		return ErrInvalidCheckInCode
+	}
+	current := now.Unix() / int64(CheckInTokenPeriod/time.Second)
+	if slot != current && slot != current-1 {
+		return ErrCheckInCodeExpired
+	}
+	return nil
+}
+
+func (cs *CheckInSigner) sign(eventID uuid.UUID, slot int64) []byte {
+	mac := hmac.New(sha256.New, cs.key)
+	mac.Write(eventID[:])
+	binary.Write(mac, binary.BigEndian, slot)
+	return mac.Sum(nil)[:16]
+}
+
+// GetCheckInToken returns the current QR token for an organizer to display
+func (s *Service) GetCheckInToken(ctx context.Context, eventID, userID uuid.UUID) (*models.CheckInToken, error) {
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEventAccess(ctx, userID, e); err != nil {
+		return nil, err
+	}
+	if err := checkInWindow(e, time.Now(), e.EnforceTimeWindow); err != nil {
+		return nil, err
+	}
+	return s.checkIn.Token(eventID, time.Now()), nil
+}
+
+// UpdateCheckInSettings changes an event's venue location, geofence and time window rule
+func (s *Service) UpdateCheckInSettings(ctx context.Context, eventID, userID uuid.UUID, in *models.Event) (*models.Event, error) {
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEventAccess(ctx, userID, e); err != nil {
+		return nil, err
+	}
+
+	e.Latitude, e.Longitude = in.Latitude, in.Longitude
+	e.GeofenceRadius, e.EnforceTimeWindow = in.GeofenceRadius, in.EnforceTimeWindow
+	if err := validateCheckInSettings(e); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateEventCheckIn(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// CheckIn records a member's self check-in from a scanned QR token. The location is required
+// when the event has a geofence; accuracy is the device's reported GPS accuracy in meters.
+func (s *Service) CheckIn(ctx context.Context, eventID, userID uuid.UUID, token string, lat, lng *float64, accuracy float64) (*models.EventAttendance, error) {
+	now := time.Now()
+	if err := s.checkIn.Verify(token, eventID, now); err != nil {
+		return nil, err
+	}
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInWindow(e, now, e.EnforceTimeWindow); err != nil {
+		return nil, err
+	}
+
+	a := &models.EventAttendance{
+		EventID:    eventID,
+		UserID:     userID,
+		AttendedAt: now,
+		Method:     models.CheckInQR,
+		Latitude:   lat,
+		Longitude:  lng,
+	}
+	if e.GeofenceRadius != nil {
+		if lat == nil || lng == nil {
+			return nil, ErrLocationRequired
+		}
+		distance := distanceMeters(*e.Latitude, *e.Longitude, *lat, *lng)
+		if distance-math.Min(math.Max(accuracy, 0), maxLocationAccuracy) > float64(*e.GeofenceRadius) {
+			return nil, fmt.Errorf("%w: you are %d m away, the limit is %d m", ErrOutsideGeofence, int(distance), *e.GeofenceRadius)
+		}
+		d := int(distance)
+		a.DistanceM = &d
+	}
+
+	created, err := s.repo.RecordAttendance(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyCheckedIn
+	}
+	return a, nil
+}
+
+// MarkAttendance lets an organizer check a member in by hand
+func (s *Service) MarkAttendance(ctx context.Context, eventID, memberID, userID uuid.UUID) error {
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkEventAccess(ctx, userID, e); err != nil {
+		return err
+	}
+	found, err := s.repo.ListActiveUserIDs(ctx, []uuid.UUID{memberID})
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return ErrMemberNotFound
+	}
+
+	_, err = s.repo.RecordAttendance(ctx, &models.EventAttendance{
+		EventID:    eventID,
+		UserID:     memberID,
+		AttendedAt: time.Now(),
+		Method:     models.CheckInManual,
+		RecordedBy: &userID,
+	})
+	return err
+}
+
+// SyncOfflineCheckIns stores check-ins an organizer collected without connectivity. Entries are
+// vouched for by the organizer, so the geofence is not applied, but the time window is when the
+// event enforces it. Each entry is reported separately; a member already checked in is a duplicate.
+func (s *Service) SyncOfflineCheckIns(ctx context.Context, eventID, userID uuid.UUID, entries []*models.OfflineCheckIn) ([]*models.CheckInResult, error) {
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEventAccess(ctx, userID, e); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrNoCheckIns
+	}
+	if len(entries) > MaxOfflineCheckIns {
+		return nil, ErrTooManyCheckIns
+	}
+
+	// 1. Look up the members in one query
+	ids := make([]uuid.UUID, 0, len(entries))
+	for _, in := range entries {
+		ids = append(ids, in.UserID)
+	}
+	activeIDs, err := s.repo.ListActiveUserIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[uuid.UUID]bool, len(activeIDs))
+	for _, id := range activeIDs {
+		active[id] = true
+	}
+
+	// 2. Record each entry
+	now := time.Now()
+	results := make([]*models.CheckInResult, 0, len(entries))
+	for _, in := range entries {
+		res := &models.CheckInResult{UserID: in.UserID, Status: models.CheckInRejected}
+		results = append(results, res)
+
+		switch {
+		case !active[in.UserID]:
+			res.Reason = "member not found"
+			continue
+		case in.CheckedInAt.IsZero():
+			res.Reason = "checked_in_at is required"
+			continue
+		case in.CheckedInAt.After(now.Add(maxClockSkew)):
+			res.Reason = "checked_in_at is in the future"
+			continue
+		}
+		if err := checkInWindow(e, in.CheckedInAt, e.EnforceTimeWindow); err != nil {
+			res.Reason = err.Error()
+			continue
+		}
+
+		a := &models.EventAttendance{
+			EventID:    eventID,
+			UserID:     in.UserID,
+			AttendedAt: in.CheckedInAt,
+			Method:     models.CheckInOffline,
+			RecordedBy: &userID,
+			Latitude:   in.Latitude,
+			Longitude:  in.Longitude,
+			SyncedAt:   &now,
+		}
+		if e.Latitude != nil && e.Longitude != nil && in.Latitude != nil && in.Longitude != nil {
+			d := int(distanceMeters(*e.Latitude, *e.Longitude, *in.Latitude, *in.Longitude))
+			a.DistanceM = &d
+		}
+
+		created, err := s.repo.RecordAttendance(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		res.Status = models.CheckInRecorded
+		if !created {
+			res.Status = models.CheckInDuplicate
+		}
+	}
+	return results, nil
+}
+
+// checkInWindow rejects check-ins outside the event's window when enforce is set
+func checkInWindow(e *models.Event, at time.Time, enforce bool) error {
+	if !enforce {
+		return nil
+	}
+	opens := e.StartTime.Add(-checkInOpensBefore)
+	closes := e.StartTime.Add(checkInDefaultSpan)
+	if e.EndTime != nil {
+		closes = *e.EndTime
+	}
+	if at.Before(opens) {
+		return fmt.Errorf("check-in opens at %s: %w", opens.Format("02 Jan 2006 15:04"), ErrCheckInNotOpen)
+	}
+	if at.After(closes) {
+		return fmt.Errorf("check-in closed at %s: %w", closes.Format("02 Jan 2006 15:04"), ErrCheckInClosed)
+	}
+	return nil
+}
+
+func validateCheckInSettings(e *models.Event) error {
+	if (e.Latitude == nil) != (e.Longitude == nil) {
+		return ErrPartialCoordinates
+	}
+	if e.Latitude != nil && (*e.Latitude < -90 || *e.Latitude > 90 || *e.Longitude < -180 || *e.Longitude > 180) {
+		return ErrInvalidCoordinates
+	}
+	if e.GeofenceRadius != nil {
+		if *e.GeofenceRadius <= 0 {
+			return ErrInvalidGeofence
+		}
+		if e.Latitude == nil {
+			return ErrGeofenceNeedsVenue
+		}
+	}
+	return nil
+}
+
+// distanceMeters returns the great-circle distance between two WGS84 points
+func distanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
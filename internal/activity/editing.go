@@ -0,0 +1,334 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/google/uuid"
+)
+
+// EditWindow is how long after creating an activity, task or event its owner may change or
+// delete it alone; afterwards only a superior can
+const EditWindow = 48 * time.Hour
+
+// Editing errors
+var (
+	ErrVerifiedActivityEdit   = errors.New("only a superior can change an activity once it is verified")
+	ErrVerifiedActivityDelete = errors.New("only a superior can delete an activity once it is verified")
+	ErrTaskLocked             = errors.New("task cannot be changed")
+	ErrNotOwner               = errors.New("only the owner or a superior can change this record")
+	ErrEditWindowClosed       = fmt.Errorf("the %d-hour edit window has passed, only a superior can make changes", int(EditWindow.Hours()))
+)
+
+// ACTIVITIES
+
+// EditActivity changes an activity. A verified activity is locked for its owner; a disputed one
+// corrected by its owner goes back to the review queue.
+func (s *Service) EditActivity(ctx context.Context, id, userID uuid.UUID, p *models.ActivityPatch) (*models.Activity, error) {
+	old, err := s.repo.GetActivity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	superior, err := s.checkEditAccess(ctx, userID, old.UserID, old.JurisdictionID, old.CreatedAt, "activity")
+	if err != nil {
+		return nil, err
+	}
+	if !superior && old.ReviewStatus == models.ReviewVerified {
+		return nil, ErrVerifiedActivityEdit
+	}
+
+	a := *old
+	if p.Title != nil {
+		a.Title = strings.TrimSpace(*p.Title)
+	}
+	if p.Description != nil {
+		a.Description = *p.Description
+	}
+	if p.Category != nil {
+		a.Category = *p.Category
+	}
+	if p.ActivityDate != nil {
+		a.ActivityDate = *p.ActivityDate
+	}
+	if a.Title == "" {
+		return nil, ErrTitleRequired
+	}
+	switch a.Category {
+	case models.CategoryPolitical, models.CategorySocial, models.CategoryOrganizational, models.CategoryProtest, models.CategoryOther:
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownCategory, a.Category)
+	}
+	if a.ActivityDate.After(time.Now()) {
+		return nil, ErrFutureActivity
+	}
+
+	resubmit := !superior && old.ReviewStatus == models.ReviewDisputed
+	if err := s.repo.UpdateActivity(ctx, old, &a, userID, resubmit); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// DeleteActivity soft-deletes an activity; it stops counting towards scores
+func (s *Service) DeleteActivity(ctx context.Context, id, userID uuid.UUID) error {
+	a, err := s.repo.GetActivity(ctx, id)
+	if err != nil {
+		return err
+	}
+	superior, err := s.checkEditAccess(ctx, userID, a.UserID, a.JurisdictionID, a.CreatedAt, "activity")
+	if err != nil {
+		return err
+	}
+	if !superior && a.ReviewStatus == models.ReviewVerified {
+		return ErrVerifiedActivityDelete
+	}
+	return s.repo.SoftDelete(ctx, "activity", id, userID, a)
+}
+
+// TASKS
+
+// EditTask changes a task's description, priority, due date or completion requirements.
+// Finished tasks cannot be changed.
+func (s *Service) EditTask(ctx context.Context, id, userID uuid.UUID, p *models.TaskPatch) (*models.Task, error) {
+	old, err := s.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.checkEditAccess(ctx, userID, old.CreatorID, old.JurisdictionID, old.CreatedAt, "task"); err != nil {
+		return nil, err
+	}
+	if old.Status == models.TaskStatusVerified || old.Status == models.TaskStatusCancelled {
+		return nil, fmt.Errorf("a %s %w", old.Status, ErrTaskLocked)
+	}
+
+	t := *old
+	var changed []string
+	if p.Title != nil && strings.TrimSpace(*p.Title) != t.Title {
+		t.Title = strings.TrimSpace(*p.Title)
+		changed = append(changed, "title")
+	}
+	if p.Description != nil && *p.Description != t.Description {
+		t.Description = *p.Description
+		changed = append(changed, "description")
+	}
+	if p.Priority != nil && *p.Priority != t.Priority {
+		t.Priority = *p.Priority
+		changed = append(changed, "priority")
+	}
+	dueChanged := p.DueDate != nil && !sameTime(p.DueDate, t.DueDate)
+	if dueChanged {
+		t.DueDate = p.DueDate
+		changed = append(changed, "due date")
+	}
+	if p.RequiresCompletionNote != nil && *p.RequiresCompletionNote != t.RequiresCompletionNote {
+		t.RequiresCompletionNote = *p.RequiresCompletionNote
+		changed = append(changed, "completion note requirement")
+	}
+	if p.RequiresProof != nil && *p.RequiresProof != t.RequiresProof {
+		t.RequiresProof = *p.RequiresProof
+		changed = append(changed, "proof requirement")
+	}
+	if len(changed) == 0 {
+		return old, nil
+	}
+	if t.Title == "" {
+		return nil, ErrTitleRequired
+	}
+	if t.Priority < 1 || t.Priority > 4 {
+		return nil, ErrInvalidPriority
+	}
+
+	if err := s.repo.UpdateTask(ctx, old, &t, userID, "Changed "+strings.Join(changed, ", ")); err != nil {
+		return nil, err
+	}
+
+	if dueChanged {
+		msg := fmt.Sprintf("The due date of \"%s\" has changed to %s", t.Title, t.DueDate.Format("02 Jan 2006 15:04"))
+		s.notifyTaskMembers(ctx, &t, userID, notification.Notification{
+			Type:           notification.TypeTaskUpdated,
+			Title:          "Task Updated",
+			Message:        msg,
+			JurisdictionID: t.JurisdictionID,
+		})
+	}
+	return &t, nil
+}
+
+// DeleteTask soft-deletes a task
+func (s *Service) DeleteTask(ctx context.Context, id, userID uuid.UUID) error {
+	t, err := s.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.checkEditAccess(ctx, userID, t.CreatorID, t.JurisdictionID, t.CreatedAt, "task"); err != nil {
+		return err
+	}
+	return s.repo.SoftDelete(ctx, "task", id, userID, t)
+}
+
+// EVENTS
+
+// EditEvent changes an event's details. Members who are going are told when the time or place
+// changes; subscribed calendars pick up the change through its sequence number.
+func (s *Service) EditEvent(ctx context.Context, id, userID uuid.UUID, p *models.EventPatch) (*models.Event, error) {
+	old, err := s.repo.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.checkEditAccess(ctx, userID, old.OrganizerID, old.JurisdictionID, old.CreatedAt, "event"); err != nil {
+		return nil, err
+	}
+
+	e := *old
+	if p.Title != nil {
+		e.Title = strings.TrimSpace(*p.Title)
+	}
+	if p.Description != nil {
+		e.Description = *p.Description
+	}
+	if p.Location != nil {
+		e.Location = *p.Location
+	}
+	if p.StartTime != nil {
+		e.StartTime = *p.StartTime
+	}
+	if p.EndTime != nil {
+		e.EndTime = p.EndTime
+	}
+	if p.IsPublic != nil {
+		e.IsPublic = *p.IsPublic
+	}
+	if p.RSVPDeadline != nil {
+		e.RSVPDeadline = p.RSVPDeadline
+	}
+	if e.Title == "" {
+		return nil, ErrTitleRequired
+	}
+	if e.EndTime != nil && !e.EndTime.After(e.StartTime) {
+		return nil, ErrEndBeforeStart
+	}
+	if e.RSVPDeadline != nil && e.RSVPDeadline.After(e.StartTime) {
+		return nil, ErrDeadlineAfterStart
+	}
+
+	if err := s.repo.UpdateEvent(ctx, old, &e, userID); err != nil {
+		return nil, err
+	}
+
+	if !e.StartTime.Equal(old.StartTime) || !sameTime(e.EndTime, old.EndTime) || e.Location != old.Location {
+		msg := fmt.Sprintf("\"%s\" has changed: it now starts %s", e.Title, e.StartTime.Format("02 Jan 2006 15:04"))
+		if e.Location != "" {
+			msg += " at " + e.Location
+		}
+		s.notifyEventInvitees(ctx, &e, "Event Updated", msg+".")
+	}
+	return &e, nil
+}
+
+// DeleteEvent soft-deletes an event, which cancels it for its invitees and in their calendars
+func (s *Service) DeleteEvent(ctx context.Context, id, userID uuid.UUID) error {
+	e, err := s.repo.GetEvent(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.checkEditAccess(ctx, userID, e.OrganizerID, e.JurisdictionID, e.CreatedAt, "event"); err != nil {
+		return err
+	}
+	if err := s.repo.SoftDelete(ctx, "event", id, userID, e); err != nil {
+		return err
+	}
+
+	if e.StartTime.After(time.Now()) {
+		msg := fmt.Sprintf("\"%s\" on %s has been cancelled.", e.Title, e.StartTime.Format("02 Jan 2006 15:04"))
+		s.notifyEventInvitees(ctx, e, "Event Cancelled", msg)
+	}
+	return nil
+}
+
+// RESTORE
+
+// Restore brings back a deleted activity, task or event. Only the Super Admin can restore.
+func (s *Service) Restore(ctx context.Context, entity string, id, userID uuid.UUID) error {
+	if err := s.checkSuperAdmin(ctx, userID, "restore deleted records"); err != nil {
+		return err
+	}
+	return s.repo.Restore(ctx, entity, id, userID)
+}
+
+// checkEditAccess allows the owner within the edit window, and a superior at any time.
+// Reports whether the user acts as a superior.
+func (s *Service) checkEditAccess(ctx context.Context, userID, ownerID, jurisdictionID uuid.UUID, createdAt time.Time, label string) (bool, error) {
+	superior, err := s.isSuperior(ctx, userID, ownerID, jurisdictionID)
+	if err != nil {
+		return false, err
+	}
+	if superior {
+		return true, nil
+	}
+	if userID != ownerID {
+		return false, fmt.Errorf("cannot change this %s: %w", label, ErrNotOwner)
+	}
+	if time.Since(createdAt) > EditWindow {
+		return false, fmt.Errorf("cannot change this %s: %w", label, ErrEditWindowClosed)
+	}
+	return false, nil
+}
+
+// isSuperior reports whether userID is the Super Admin, or outranks ownerID from the record's
+// jurisdiction or one above it
+func (s *Service) isSuperior(ctx context.Context, userID, ownerID, jurisdictionID uuid.UUID) (bool, error) {
+	user, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user.SuperAdmin {
+		return true, nil
+	}
+	if user.JurisdictionID == nil || userID == ownerID {
+		return false, nil
+	}
+
+	owner, err := s.authRepo.GetUserAuthority(ctx, ownerID)
+	if err != nil {
+		return false, err
+	}
+	if user.Rank >= owner.Rank {
+		return false, nil
+	}
+	return s.org.IsChildJurisdiction(ctx, *user.JurisdictionID, jurisdictionID)
+}
+
+// notifyTaskMembers tells a task's assignee, or its committee, about a change
+func (s *Service) notifyTaskMembers(ctx context.Context, t *models.Task, actorID uuid.UUID, n notification.Notification) {
+	if t.AssigneeID != nil {
+		if *t.AssigneeID == actorID {
+			return
+		}
+		n.UserID = *t.AssigneeID
+		s.notification.Create(ctx, &n)
+	} else if t.CommitteeID != nil {
+		s.notification.NotifyCommittee(ctx, *t.CommitteeID, n, &actorID)
+	}
+}
+
+// notifyEventInvitees tells members who are going, might go or are waiting for a seat
+func (s *Service) notifyEventInvitees(ctx context.Context, e *models.Event, title, msg string) {
+	ids, err := s.repo.ListEventInviteeIDs(ctx, e.ID, []string{models.RSVPYes, models.RSVPMaybe, models.RSVPWaitlisted})
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         id,
+			Type:           notification.TypeEventUpdated,
+			Title:          title,
+			Message:        msg,
+			JurisdictionID: e.JurisdictionID,
+		})
+	}
+}
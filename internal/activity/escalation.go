@@ -0,0 +1,309 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/google/uuid"
+)
+
+// EscalationRule holds the deadline thresholds for one task priority
+type EscalationRule struct {
+	ReminderLead time.Duration // Remind the assignees this long before the due date
+	LeaderAfter  time.Duration // Alert the committee leadership this long after it
+	ParentAfter  time.Duration // Alert the parent jurisdiction's leadership this long after it
+}
+
+// EscalationPolicy maps task priority (1: Critical .. 4: Low) to its thresholds
+type EscalationPolicy map[int]EscalationRule
+
+// ParseEscalationPolicy reads a policy of the form "1=6h,0s,24h;2=24h,24h,72h", giving the
+// reminder lead, leader delay and parent delay for each priority
+func ParseEscalationPolicy(s string) (EscalationPolicy, error) {
+	policy := make(EscalationPolicy)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid escalation rule %q", entry)
+		}
+		priority, err := strconv.Atoi(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority in escalation rule %q", entry)
+		}
+
+		parts := strings.Split(value, ",")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("escalation rule %q needs a reminder lead, leader delay and parent delay", entry)
+		}
+		var d [3]time.Duration
+		for i, p := range parts {
+			d[i], err = time.ParseDuration(strings.TrimSpace(p))
+			if err != nil || d[i] < 0 {
+				return nil, fmt.Errorf("invalid duration %q in escalation rule %q", p, entry)
+			}
+		}
+		if d[2] < d[1] {
+			return nil, fmt.Errorf("escalation rule %q escalates to the parent before the committee", entry)
+		}
+		policy[priority] = EscalationRule{ReminderLead: d[0], LeaderAfter: d[1], ParentAfter: d[2]}
+	}
+	if len(policy) == 0 {
+		return nil, fmt.Errorf("escalation policy is empty")
+	}
+	return policy, nil
+}
+
+// Rule returns the thresholds for a priority. Priorities without a rule use the least urgent one.
+func (p EscalationPolicy) Rule(priority int) EscalationRule {
+	if r, ok := p[priority]; ok {
+		return r
+	}
+	keys := make([]int, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return p[keys[len(keys)-1]]
+}
+
+// maxReminderLead is how far ahead of the due date any task needs looking at
+func (p EscalationPolicy) maxReminderLead() time.Duration {
+	var max time.Duration
+	for _, r := range p {
+		if r.ReminderLead > max {
+			max = r.ReminderLead
+		}
+	}
+	return max
+}
+
+// Escalator is the background job that reminds, flags and escalates tasks nearing or past their due date
+type Escalator struct {
+	repo         *Repository
+	notification *notification.Service
+	policy       EscalationPolicy
+	interval     time.Duration
+}
+
+// NewEscalator creates the task escalation job
+func NewEscalator(repo *Repository, ns *notification.Service, policy EscalationPolicy, interval time.Duration) *Escalator {
+	return &Escalator{repo: repo, notification: ns, policy: policy, interval: interval}
+}
+
+// Run checks tasks every interval until ctx is cancelled. Every step is claimed with a guarded
+// update first, so several API instances can run the job without sending duplicates.
+func (e *Escalator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.scan(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("tasks: escalation run failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Escalator) scan(ctx context.Context) error {
+	now := time.Now()
+	tasks, err := e.repo.ListOpenTasksDueBefore(ctx, now.Add(e.policy.maxReminderLead()))
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := e.check(ctx, t, now); err != nil {
+			log.Printf("tasks: escalation of task %s failed: %v", t.ID, err)
+		}
+	}
+	return nil
+}
+
+// check moves one task through reminder, overdue and the two escalation levels as its
+// thresholds pass. A task that is already far overdue takes several steps in one run.
+func (e *Escalator) check(ctx context.Context, t *models.Task, now time.Time) error {
+	rule := e.policy.Rule(t.Priority)
+	due := *t.DueDate
+
+	// 1. Reminder ahead of the due date
+	if now.Before(due) {
+		if t.RemindedAt != nil || now.Before(due.Add(-rule.ReminderLead)) {
+			return nil
+		}
+		note := fmt.Sprintf("Due in %s", formatSpan(due.Sub(now)))
+		ok, err := e.repo.MarkTaskReminded(ctx, t.ID, note)
+		if err != nil || !ok {
+			return err
+		}
+		return e.notifyWorkers(ctx, t, notification.TypeTaskReminder, "Task Due Soon",
+			fmt.Sprintf("\"%s\" is due in %s.", t.Title, formatSpan(due.Sub(now))))
+	}
+	late := now.Sub(due)
+
+	// 2. Flag as overdue
+	if t.OverdueAt == nil {
+		ok, err := e.repo.MarkTaskOverdue(ctx, t.ID, "")
+		if err != nil {
+			return err
+		}
+		if ok {
+			message := fmt.Sprintf("\"%s\" is past its due date.", t.Title)
+			if err := e.notifyWorkers(ctx, t, notification.TypeTaskOverdue, "Task Overdue", message); err != nil {
+				return err
+			}
+			e.notifyUsers(ctx, t, []uuid.UUID{t.CreatorID}, notification.TypeTaskOverdue, "Task Overdue", message)
+		}
+	}
+
+	// 3. Committee leadership
+	if t.EscalationLevel < models.TaskEscalationCommittee {
+		if late < rule.LeaderAfter {
+			return nil
+		}
+		if err := e.escalateToCommittee(ctx, t, late); err != nil {
+			return err
+		}
+		t.EscalationLevel = models.TaskEscalationCommittee
+	}
+
+	// 4. Parent jurisdiction leadership
+	if t.EscalationLevel < models.TaskEscalationParent && late >= rule.ParentAfter {
+		return e.escalateToParent(ctx, t, late)
+	}
+	return nil
+}
+
+func (e *Escalator) escalateToCommittee(ctx context.Context, t *models.Task, late time.Duration) error {
+	var leaders []uuid.UUID
+	committeeID, err := e.repo.FindAssigneeCommittee(ctx, t)
+	if err != nil {
+		return err
+	}
+	if committeeID != nil {
+		if leaders, err = e.repo.ListCommitteeLeaders(ctx, *committeeID); err != nil {
+			return err
+		}
+	}
+	leaders = excludeUser(leaders, t.AssigneeID)
+
+	note := fmt.Sprintf("Escalated to committee leadership (%d notified), %s overdue", len(leaders), formatSpan(late))
+	if len(leaders) == 0 {
+		note = fmt.Sprintf("No committee leadership to escalate to, %s overdue", formatSpan(late))
+	}
+	ok, err := e.repo.EscalateTask(ctx, t.ID, models.TaskEscalationNone, models.TaskEscalationCommittee, note)
+	if err != nil || !ok {
+		return err
+	}
+
+	e.notifyUsers(ctx, t, leaders, notification.TypeTaskEscalated, "Overdue Task Escalated",
+		fmt.Sprintf("\"%s\" in your committee is %s overdue.", t.Title, formatSpan(late)))
+	return nil
+}
+
+func (e *Escalator) escalateToParent(ctx context.Context, t *models.Task, late time.Duration) error {
+	var leaders []uuid.UUID
+	committeeID, parentName, err := e.repo.FindParentCommittee(ctx, t.JurisdictionID)
+	if err != nil {
+		return err
+	}
+	if committeeID != nil {
+		if leaders, err = e.repo.ListCommitteeLeaders(ctx, *committeeID); err != nil {
+			return err
+		}
+	}
+	leaders = excludeUser(leaders, t.AssigneeID)
+
+	var note string
+	switch {
+	case parentName == "":
+		note = fmt.Sprintf("No parent jurisdiction to escalate to, %s overdue", formatSpan(late))
+	case len(leaders) == 0:
+		note = fmt.Sprintf("No active leadership in %s to escalate to, %s overdue", parentName, formatSpan(late))
+	default:
+		note = fmt.Sprintf("Escalated to %s leadership (%d notified), %s overdue", parentName, len(leaders), formatSpan(late))
+	}
+	ok, err := e.repo.EscalateTask(ctx, t.ID, models.TaskEscalationCommittee, models.TaskEscalationParent, note)
+	if err != nil || !ok {
+		return err
+	}
+
+	e.notifyUsers(ctx, t, leaders, notification.TypeTaskEscalated, "Overdue Task Escalated",
+		fmt.Sprintf("\"%s\" in a subordinate jurisdiction is %s overdue.", t.Title, formatSpan(late)))
+	return nil
+}
+
+// notifyWorkers alerts everyone still expected to work on the task
+func (e *Escalator) notifyWorkers(ctx context.Context, t *models.Task, typ notification.NotificationType, title, message string) error {
+	workers, err := e.repo.ListTaskWorkers(ctx, t)
+	if err != nil {
+		return err
+	}
+	e.notifyUsers(ctx, t, workers, typ, title, message)
+	return nil
+}
+
+func (e *Escalator) notifyUsers(ctx context.Context, t *models.Task, users []uuid.UUID, typ notification.NotificationType, title, message string) {
+	for _, id := range users {
+		err := e.notification.Create(ctx, &notification.Notification{
+			UserID:         id,
+			Type:           typ,
+			Title:          title,
+			Message:        message,
+			JurisdictionID: t.JurisdictionID,
+		})
+		if err != nil {
+			log.Printf("tasks: failed to notify %s about task %s: %v", id, t.ID, err)
+		}
+	}
+}
+
+func excludeUser(ids []uuid.UUID, exclude *uuid.UUID) []uuid.UUID {
+	if exclude == nil {
+		return ids
+	}
+	out := ids[:0]
+	for _, id := range ids {
+		if id != *exclude {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// formatSpan renders a duration in whole days, or hours below a day
+func formatSpan(d time.Duration) string {
+	if d >= 24*time.Hour {
+		days := int(d / (24 * time.Hour))
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	hours := int(d / time.Hour)
+	switch hours {
+	case 0:
+		return "less than an hour"
+	case 1:
+		return "1 hour"
+	}
+	return fmt.Sprintf("%d hours", hours)
+}
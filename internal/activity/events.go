@@ -0,0 +1,210 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/google/uuid"
+)
+
+// rsvpReminderLead is how long before the RSVP deadline unanswered invitees are reminded
+const rsvpReminderLead = 48 * time.Hour
+
+// Invitation and RSVP errors
+var (
+	ErrNoAudience         = errors.New("choose a jurisdiction, committee, position or members to invite")
+	ErrPositionNeedsScope = errors.New("a position must be combined with a jurisdiction or committee")
+	ErrEventStarted       = errors.New("event has already started")
+	ErrUnknownRSVP        = fmt.Errorf("rsvp must be %q, %q or %q", models.RSVPYes, models.RSVPNo, models.RSVPMaybe)
+	ErrRSVPClosed         = errors.New("rsvps for this event are closed")
+	ErrNotEventManager    = errors.New("only the organizer or committee leaders at or above the event's jurisdiction can manage it")
+)
+
+// GetEvent returns an event with its seat counts
+func (s *Service) GetEvent(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	return s.repo.GetEvent(ctx, id)
+}
+
+// InviteToEvent invites an audience to an event and notifies the newly invited members.
+// Returns how many members were invited.
+func (s *Service) InviteToEvent(ctx context.Context, eventID, userID uuid.UUID, aud *models.EventAudience) (int, error) {
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.checkEventAccess(ctx, userID, e); err != nil {
+		return 0, err
+	}
+	if aud.JurisdictionID == nil && aud.CommitteeID == nil && aud.PositionID == nil && len(aud.UserIDs) == 0 {
+		return 0, ErrNoAudience
+	}
+	if aud.PositionID != nil && aud.JurisdictionID == nil && aud.CommitteeID == nil {
+		return 0, ErrPositionNeedsScope
+	}
+	if !time.Now().Before(e.StartTime) {
+		return 0, ErrEventStarted
+	}
+
+	invited, err := s.repo.InviteToEvent(ctx, eventID, userID, aud)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range invited {
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         id,
+			Type:           notification.TypeEventInvitation,
+			Title:          "Event Invitation",
+			Message:        fmt.Sprintf("You are invited to \"%s\" on %s. Please RSVP.", e.Title, e.StartTime.Format("02 Jan 2006 15:04")),
+			JurisdictionID: e.JurisdictionID,
+		})
+	}
+	return len(invited), nil
+}
+
+// RespondToEvent records a member's yes/no/maybe. Members not invited may answer public events.
+func (s *Service) RespondToEvent(ctx context.Context, eventID, userID uuid.UUID, answer string) (*models.EventInvitation, error) {
+	switch answer {
+	case models.RSVPYes, models.RSVPNo, models.RSVPMaybe:
+	default:
+		return nil, ErrUnknownRSVP
+	}
+
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	deadline := e.StartTime
+	if e.RSVPDeadline != nil {
+		deadline = *e.RSVPDeadline
+	}
+	if !time.Now().Before(deadline) {
+		return nil, ErrRSVPClosed
+	}
+
+	_, promoted, err := s.repo.RespondToEvent(ctx, eventID, userID, answer, e.IsPublic)
+	if err != nil {
+		return nil, err
+	}
+	s.notifySeatConfirmed(ctx, e, promoted)
+
+	return s.repo.GetEventInvitation(ctx, eventID, userID)
+}
+
+// SetEventCapacity changes an event's capacity (nil for unlimited); freed seats go to the waitlist
+func (s *Service) SetEventCapacity(ctx context.Context, eventID, userID uuid.UUID, capacity *int) (*models.Event, error) {
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEventAccess(ctx, userID, e); err != nil {
+		return nil, err
+	}
+	if capacity != nil && *capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+
+	promoted, err := s.repo.SetEventCapacity(ctx, eventID, capacity)
+	if err != nil {
+		return nil, err
+	}
+	s.notifySeatConfirmed(ctx, e, promoted)
+
+	return s.repo.GetEvent(ctx, eventID)
+}
+
+// ListEventInvitations returns an event's invitations for its organizers
+func (s *Service) ListEventInvitations(ctx context.Context, eventID, userID uuid.UUID, rsvp string) ([]*models.EventInvitation, error) {
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEventAccess(ctx, userID, e); err != nil {
+		return nil, err
+	}
+	return s.repo.ListEventInvitations(ctx, eventID, rsvp)
+}
+
+// GetEventTurnout compares expected turnout (yes answers) with recorded attendance
+func (s *Service) GetEventTurnout(ctx context.Context, eventID, userID uuid.UUID) (*models.EventTurnout, error) {
+	e, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEventAccess(ctx, userID, e); err != nil {
+		return nil, err
+	}
+
+	t, err := s.repo.GetEventTurnout(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	t.Capacity = e.Capacity
+	t.Expected = t.Yes
+	t.NoShows = t.Yes - t.AttendedYes
+	t.WalkIns = t.Attended - t.AttendedYes - t.AttendedMaybe
+	if t.Expected > 0 {
+		t.TurnoutPercent = float64(t.AttendedYes) * 100 / float64(t.Expected)
+	}
+	return t, nil
+}
+
+// SendRSVPReminders reminds unanswered invitees once their event's RSVP deadline is near
+func (s *Service) SendRSVPReminders(ctx context.Context, now time.Time) error {
+	reminders, err := s.repo.ClaimRSVPReminders(ctx, now, now.Add(rsvpReminderLead))
+	if err != nil {
+		return err
+	}
+
+	for _, rm := range reminders {
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         rm.UserID,
+			Type:           notification.TypeEventReminder,
+			Title:          "RSVP Reminder",
+			Message:        fmt.Sprintf("Please RSVP to \"%s\" by %s.", rm.Title, rm.Deadline.Format("02 Jan 2006 15:04")),
+			JurisdictionID: rm.JurisdictionID,
+		})
+	}
+	return nil
+}
+
+// checkEventAccess allows the organizer, the Super Admin, and committee leaders (positions up to
+// auth.LeaderRank) whose jurisdiction is the event's or above it
+func (s *Service) checkEventAccess(ctx context.Context, userID uuid.UUID, e *models.Event) error {
+	if e.OrganizerID == userID {
+		return nil
+	}
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if authority.SuperAdmin {
+		return nil
+	}
+	if authority.JurisdictionID != nil && authority.IsLeader() {
+		ok, err := s.org.IsChildJurisdiction(ctx, *authority.JurisdictionID, e.JurisdictionID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return ErrNotEventManager
+}
+
+func (s *Service) notifySeatConfirmed(ctx context.Context, e *models.Event, userIDs []uuid.UUID) {
+	for _, id := range userIDs {
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         id,
+			Type:           notification.TypeEventSeat,
+			Title:          "Seat Confirmed",
+			Message:        fmt.Sprintf("A seat has opened up at \"%s\" and is now yours.", e.Title),
+			JurisdictionID: e.JurisdictionID,
+		})
+	}
+}
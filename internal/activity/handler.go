@@ -1,25 +1,31 @@
 package activity
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/bjdms/api/internal/middleware"
 	"github.com/bjdms/api/internal/models"
 	"github.com/bjdms/api/pkg/response"
+	"github.com/bjdms/api/pkg/storage"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 // Handler handles HTTP requests for activities and tasks
 type Handler struct {
-	service *Service
+	service  *Service
+	uploader *storage.Uploader
 }
 
 // NewHandler creates a new activity handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, uploader *storage.Uploader) *Handler {
+	return &Handler{service: service, uploader: uploader}
 }
 
 // Routes defines routes for activities and tasks
@@ -30,16 +36,62 @@ func (h *Handler) Routes() chi.Router {
 	r.Post("/", h.LogActivity)
 	r.Get("/", h.ListActivities)
 	r.Get("/{id}", h.GetActivity)
+	r.Patch("/{id}", h.EditActivity)
+	r.Delete("/{id}", h.DeleteActivity)
+	r.Post("/{id}/restore", h.RestoreActivity)
+	r.Post("/{id}/proofs", h.UploadProofs)
+	r.Get("/{id}/proofs", h.ListProofs)
+	r.Patch("/{id}/review", h.ReviewActivity)
+	r.Get("/{id}/reviews", h.ListActivityReviews)
+
+	// Scoring
+	r.Get("/scoring/weights", h.ListScoreWeights)
+	r.Put("/scoring/weights/{category}", h.UpdateScoreWeight)
+	r.Post("/scoring/refresh", h.RefreshScores)
+	r.Get("/leaderboard/members", h.GetMemberLeaderboard)
+	r.Get("/leaderboard/jurisdictions", h.GetJurisdictionLeaderboard)
 
 	// Tasks
 	r.Post("/tasks", h.CreateTask)
 	r.Get("/tasks", h.ListTasks)
+	r.Get("/tasks/overdue", h.GetOverdueReport)
+	r.Post("/tasks/templates", h.CreateTemplate)
+	r.Get("/tasks/templates", h.ListTemplates)
+	r.Post("/tasks/templates/preview", h.PreviewDraftTemplate)
+	r.Get("/tasks/templates/{id}", h.GetTemplate)
+	r.Put("/tasks/templates/{id}", h.UpdateTemplate)
+	r.Delete("/tasks/templates/{id}", h.DeleteTemplate)
+	r.Post("/tasks/templates/{id}/pause", h.PauseTemplate)
+	r.Post("/tasks/templates/{id}/resume", h.ResumeTemplate)
+	r.Get("/tasks/templates/{id}/preview", h.PreviewTemplate)
+	r.Get("/tasks/{id}", h.GetTask)
+	r.Patch("/tasks/{id}", h.EditTask)
+	r.Delete("/tasks/{id}", h.DeleteTask)
+	r.Post("/tasks/{id}/restore", h.RestoreTask)
 	r.Patch("/tasks/{id}/status", h.UpdateTaskStatus)
+	r.Get("/tasks/{id}/assignments", h.ListTaskAssignments)
+	r.Patch("/tasks/{id}/assignments/{user_id}", h.UpdateAssignmentStatus)
+	r.Post("/tasks/{id}/proofs", h.UploadTaskProofs)
+	r.Get("/tasks/{id}/proofs", h.ListTaskProofs)
+	r.Get("/tasks/{id}/history", h.GetTaskHistory)
 
 	// Events
 	r.Post("/events", h.CreateEvent)
 	r.Get("/events", h.ListEvents)
+	r.Get("/events/{id}", h.GetEvent)
+	r.Patch("/events/{id}", h.EditEvent)
+	r.Delete("/events/{id}", h.DeleteEvent)
+	r.Post("/events/{id}/restore", h.RestoreEvent)
+	r.Patch("/events/{id}/capacity", h.SetEventCapacity)
+	r.Post("/events/{id}/invitations", h.InviteToEvent)
+	r.Get("/events/{id}/invitations", h.ListEventInvitations)
+	r.Put("/events/{id}/rsvp", h.RespondToEvent)
+	r.Get("/events/{id}/turnout", h.GetEventTurnout)
+	r.Patch("/events/{id}/checkin-settings", h.UpdateCheckInSettings)
+	r.Get("/events/{id}/checkin-token", h.GetCheckInToken)
+	r.Post("/events/{id}/checkin", h.CheckIn)
 	r.Post("/events/{id}/attendance", h.MarkAttendance)
+	r.Post("/events/{id}/attendance/offline", h.SyncOfflineCheckIns)
 
 	return r
 }
@@ -61,7 +113,7 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	e.OrganizerID = creatorID
 
 	if err := h.service.CreateEvent(r.Context(), &e); err != nil {
-		response.InternalError(w, "Failed to create event", "")
+		writeError(w, err)
 		return
 	}
 
@@ -85,6 +137,139 @@ func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, list, "")
 }
 
+// GetEvent handles GET /api/v1/activities/events/{id}
+func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	e, err := h.service.GetEvent(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, e, "")
+}
+
+// SetEventCapacity handles PATCH /api/v1/activities/events/{id}/capacity
+func (h *Handler) SetEventCapacity(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	var req struct {
+		Capacity *int `json:"capacity"` // null removes the limit
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	e, err := h.service.SetEventCapacity(r.Context(), id, userID, req.Capacity)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, e, "Event capacity updated")
+}
+
+// InviteToEvent handles POST /api/v1/activities/events/{id}/invitations
+func (h *Handler) InviteToEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	var aud models.EventAudience
+	if err := json.NewDecoder(r.Body).Decode(&aud); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	invited, err := h.service.InviteToEvent(r.Context(), id, userID, &aud)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, map[string]int{"invited": invited}, "Invitations sent")
+}
+
+// ListEventInvitations handles GET /api/v1/activities/events/{id}/invitations?rsvp=..
+func (h *Handler) ListEventInvitations(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	list, err := h.service.ListEventInvitations(r.Context(), id, userID, r.URL.Query().Get("rsvp"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// RespondToEvent handles PUT /api/v1/activities/events/{id}/rsvp
+func (h *Handler) RespondToEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	var req struct {
+		RSVP string `json:"rsvp"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	inv, err := h.service.RespondToEvent(r.Context(), id, userID, req.RSVP)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	msg := "RSVP recorded"
+	if inv.RSVP == models.RSVPWaitlisted {
+		msg = fmt.Sprintf("The event is full; you are number %d on the waitlist", inv.WaitlistPosition)
+	}
+	response.Success(w, inv, msg)
+}
+
+// GetEventTurnout handles GET /api/v1/activities/events/{id}/turnout
+func (h *Handler) GetEventTurnout(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	t, err := h.service.GetEventTurnout(r.Context(), id, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, t, "")
+}
+
 // MarkAttendance handles POST /api/v1/activities/events/{id}/attendance
 func (h *Handler) MarkAttendance(w http.ResponseWriter, r *http.Request) {
 	eventIDStr := chi.URLParam(r, "id")
@@ -94,17 +279,122 @@ func (h *Handler) MarkAttendance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var req struct {
+		UserID uuid.UUID `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == uuid.Nil {
+		response.BadRequest(w, "user_id is required")
+		return
+	}
+
 	userIDStr := middleware.GetUserID(r.Context())
 	userID, _ := uuid.Parse(userIDStr)
 
-	if err := h.service.MarkAttendance(r.Context(), eventID, userID); err != nil {
-		response.InternalError(w, "Failed to mark attendance", "")
+	if err := h.service.MarkAttendance(r.Context(), eventID, req.UserID, userID); err != nil {
+		writeError(w, err)
 		return
 	}
 
 	response.Created(w, nil, "Attendance marked")
 }
 
+// SyncOfflineCheckIns handles POST /api/v1/activities/events/{id}/attendance/offline
+func (h *Handler) SyncOfflineCheckIns(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	var req struct {
+		CheckIns []*models.OfflineCheckIn `json:"check_ins"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	results, err := h.service.SyncOfflineCheckIns(r.Context(), eventID, userID, req.CheckIns)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, results, "Check-ins synced")
+}
+
+// GetCheckInToken handles GET /api/v1/activities/events/{id}/checkin-token
+func (h *Handler) GetCheckInToken(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	token, err := h.service.GetCheckInToken(r.Context(), eventID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, token, "")
+}
+
+// UpdateCheckInSettings handles PATCH /api/v1/activities/events/{id}/checkin-settings
+func (h *Handler) UpdateCheckInSettings(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	var in models.Event
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	e, err := h.service.UpdateCheckInSettings(r.Context(), eventID, userID, &in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, e, "Check-in settings updated")
+}
+
+// CheckIn handles POST /api/v1/activities/events/{id}/checkin
+func (h *Handler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	var req struct {
+		Token     string   `json:"token"`
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+		Accuracy  float64  `json:"accuracy_m"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	a, err := h.service.CheckIn(r.Context(), eventID, userID, req.Token, req.Latitude, req.Longitude, req.Accuracy)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, a, "Checked in")
+}
+
 // ACTIVITIES
 
 // LogActivity handles POST /api/v1/activities
@@ -138,7 +428,15 @@ func (h *Handler) ListActivities(w http.ResponseWriter, r *http.Request) {
 		jurisID = &id
 	}
 
-	list, err := h.service.ListActivities(r.Context(), jurisID, nil, page, pageSize)
+	reviewStatus := r.URL.Query().Get("review_status")
+	switch reviewStatus {
+	case "", models.ReviewSubmitted, models.ReviewVerified, models.ReviewDisputed:
+	default:
+		response.BadRequest(w, "Invalid review status")
+		return
+	}
+
+	list, err := h.service.ListActivities(r.Context(), jurisID, nil, reviewStatus, page, pageSize)
 	if err != nil {
 		response.InternalError(w, "Failed to fetch activities", "")
 		return
@@ -165,66 +463,784 @@ func (h *Handler) GetActivity(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, a, "")
 }
 
-// TASKS
+// ReviewActivity handles PATCH /api/v1/activities/{id}/review
+func (h *Handler) ReviewActivity(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid activity ID")
+		return
+	}
 
-// CreateTask handles POST /api/v1/activities/tasks
-func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
-	creatorIDStr := middleware.GetUserID(r.Context())
-	creatorID, _ := uuid.Parse(creatorIDStr)
+	var req struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
 
-	var t models.Task
-	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	a, err := h.service.ReviewActivity(r.Context(), id, userID, req.Status, req.Note)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, a, "Activity reviewed")
+}
+
+// ListActivityReviews handles GET /api/v1/activities/{id}/reviews
+func (h *Handler) ListActivityReviews(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid activity ID")
+		return
+	}
+
+	list, err := h.service.ListActivityReviews(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// SCORING
+
+// ListScoreWeights handles GET /api/v1/activities/scoring/weights
+func (h *Handler) ListScoreWeights(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListScoreWeights(r.Context())
+	if err != nil {
+		response.InternalError(w, "Failed to fetch scoring weights", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// UpdateScoreWeight handles PUT /api/v1/activities/scoring/weights/{category}
+func (h *Handler) UpdateScoreWeight(w http.ResponseWriter, r *http.Request) {
+	var sw models.ScoreWeight
+	if err := json.NewDecoder(r.Body).Decode(&sw); err != nil {
 		response.BadRequest(w, "Invalid request body")
 		return
 	}
-	t.CreatorID = creatorID
+	sw.Category = chi.URLParam(r, "category")
 
-	if err := h.service.CreateTask(r.Context(), &t); err != nil {
-		response.InternalError(w, "Failed to create task", "")
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.UpdateScoreWeight(r.Context(), userID, &sw); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	response.Created(w, t, "Task created successfully")
+	response.Success(w, sw, "Scoring weights updated")
 }
 
-// ListTasks handles GET /api/v1/activities/tasks
-func (h *Handler) ListTasks(w http.ResponseWriter, r *http.Request) {
-	jurisIDStr := r.URL.Query().Get("jurisdiction_id")
+// RefreshScores handles POST /api/v1/activities/scoring/refresh?month=YYYY-MM
+func (h *Handler) RefreshScores(w http.ResponseWriter, r *http.Request) {
+	month, ok := parseMonth(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.RefreshScores(r.Context(), userID, month); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Scores recomputed")
+}
+
+// GetMemberLeaderboard handles GET /api/v1/activities/leaderboard/members?month=YYYY-MM&jurisdiction_id=
+func (h *Handler) GetMemberLeaderboard(w http.ResponseWriter, r *http.Request) {
+	month, ok := parseMonth(w, r)
+	if !ok {
+		return
+	}
 	var jurisID *uuid.UUID
-	if id, err := uuid.Parse(jurisIDStr); err == nil {
+	if id, err := uuid.Parse(r.URL.Query().Get("jurisdiction_id")); err == nil {
 		jurisID = &id
 	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 
-	list, err := h.service.ListTasks(r.Context(), jurisID, nil, nil)
+	lb, err := h.service.GetMemberLeaderboard(r.Context(), month, jurisID, limit)
 	if err != nil {
-		response.InternalError(w, "Failed to fetch tasks", "")
+		response.InternalError(w, "Failed to fetch leaderboard", "")
 		return
 	}
 
-	response.Success(w, list, "")
+	response.Success(w, lb, "")
 }
 
-// UpdateTaskStatus handles PATCH /api/v1/activities/tasks/{id}/status
-func (h *Handler) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := uuid.Parse(idStr)
+// GetJurisdictionLeaderboard handles GET /api/v1/activities/leaderboard/jurisdictions?month=YYYY-MM&parent_id=&level=
+func (h *Handler) GetJurisdictionLeaderboard(w http.ResponseWriter, r *http.Request) {
+	month, ok := parseMonth(w, r)
+	if !ok {
+		return
+	}
+	var parentID *uuid.UUID
+	if id, err := uuid.Parse(r.URL.Query().Get("parent_id")); err == nil {
+		parentID = &id
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	lb, err := h.service.GetJurisdictionLeaderboard(r.Context(), month, parentID, r.URL.Query().Get("level"), limit)
 	if err != nil {
-		response.BadRequest(w, "Invalid task ID")
+		response.InternalError(w, "Failed to fetch leaderboard", "")
 		return
 	}
 
-	var req struct {
-		Status string `json:"status"`
+	response.Success(w, lb, "")
+}
+
+// parseMonth reads the month query parameter (YYYY-MM, default the current month), writing the
+// error response if it is invalid
+func parseMonth(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	v := r.URL.Query().Get("month")
+	if v == "" {
+		return startOfMonth(time.Now()), true
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, "Invalid request body")
+	month, err := time.Parse("2006-01", v)
+	if err != nil {
+		response.BadRequest(w, "Invalid month, use YYYY-MM")
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+// UploadProofs handles POST /api/v1/activities/{id}/proofs (multipart, field "files")
+func (h *Handler) UploadProofs(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid activity ID")
 		return
 	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
 
-	if err := h.service.UpdateTaskStatus(r.Context(), id, req.Status); err != nil {
-		response.BadRequest(w, err.Error())
+	// Check permission before accepting any bytes
+	if err := h.service.CanAttachProofs(r.Context(), id, userID); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	response.Success(w, nil, "Task status updated")
+	files, err := h.uploader.FromRequest(w, r, "files")
+	if err != nil {
+		if status := storage.UploadError(err); status != http.StatusInternalServerError {
+			response.Error(w, status, "upload_rejected", err.Error(), "")
+			return
+		}
+		response.InternalError(w, "Failed to store upload", "")
+		return
+	}
+
+	proofs, err := h.service.AttachProofs(r.Context(), id, userID, files)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, proofs, "Proofs uploaded successfully")
+}
+
+// ListProofs handles GET /api/v1/activities/{id}/proofs
+func (h *Handler) ListProofs(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid activity ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	proofs, err := h.service.ListProofs(r.Context(), id, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, proofs, "")
+}
+
+// TASKS
+
+// CreateTask handles POST /api/v1/activities/tasks
+func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
+	creatorIDStr := middleware.GetUserID(r.Context())
+	creatorID, _ := uuid.Parse(creatorIDStr)
+
+	var t models.Task
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	t.CreatorID = creatorID
+
+	if err := h.service.CreateTask(r.Context(), &t); err != nil {
+		response.InternalError(w, "Failed to create task", "")
+		return
+	}
+
+	response.Created(w, t, "Task created successfully")
+}
+
+// ListTasks handles GET /api/v1/activities/tasks
+func (h *Handler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	jurisIDStr := r.URL.Query().Get("jurisdiction_id")
+	var jurisID *uuid.UUID
+	if id, err := uuid.Parse(jurisIDStr); err == nil {
+		jurisID = &id
+	}
+
+	list, err := h.service.ListTasks(r.Context(), jurisID, nil, nil)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch tasks", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// GetOverdueReport handles GET /api/v1/activities/tasks/overdue?jurisdiction_id=
+func (h *Handler) GetOverdueReport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.URL.Query().Get("jurisdiction_id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	report, err := h.service.GetOverdueReport(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrJurisdictionNotFound) {
+			response.NotFound(w, err.Error())
+			return
+		}
+		response.InternalError(w, "Failed to build overdue report", "")
+		return
+	}
+
+	response.Success(w, report, "")
+}
+
+// GetTask handles GET /api/v1/activities/tasks/{id}
+func (h *Handler) GetTask(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid task ID")
+		return
+	}
+
+	t, err := h.service.GetTask(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, t, "")
+}
+
+// UpdateTaskStatus handles PATCH /api/v1/activities/tasks/{id}/status
+func (h *Handler) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(w, "Invalid task ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	var req struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateTaskStatus(r.Context(), id, userID, req.Status, req.Note); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Task status updated")
+}
+
+// ListTaskAssignments handles GET /api/v1/activities/tasks/{id}/assignments
+func (h *Handler) ListTaskAssignments(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid task ID")
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	list, err := h.service.ListTaskAssignments(r.Context(), id, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// UpdateAssignmentStatus handles PATCH /api/v1/activities/tasks/{id}/assignments/{user_id}
+func (h *Handler) UpdateAssignmentStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid task ID")
+		return
+	}
+	memberID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	var req struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateAssignmentStatus(r.Context(), id, memberID, userID, req.Status, req.Note); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Assignment status updated")
+}
+
+// UploadTaskProofs handles POST /api/v1/activities/tasks/{id}/proofs (multipart, field "files")
+func (h *Handler) UploadTaskProofs(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid task ID")
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	// Check permission before accepting any bytes
+	if _, err := h.service.CanAttachTaskProofs(r.Context(), id, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	files, err := h.uploader.FromRequest(w, r, "files")
+	if err != nil {
+		if status := storage.UploadError(err); status != http.StatusInternalServerError {
+			response.Error(w, status, "upload_rejected", err.Error(), "")
+			return
+		}
+		response.InternalError(w, "Failed to store upload", "")
+		return
+	}
+
+	proofs, err := h.service.AttachTaskProofs(r.Context(), id, userID, files)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, proofs, "Proofs uploaded successfully")
+}
+
+// ListTaskProofs handles GET /api/v1/activities/tasks/{id}/proofs
+func (h *Handler) ListTaskProofs(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid task ID")
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	proofs, err := h.service.ListTaskProofs(r.Context(), id, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, proofs, "")
+}
+
+// GetTaskHistory handles GET /api/v1/activities/tasks/{id}/history
+func (h *Handler) GetTaskHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid task ID")
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	logs, err := h.service.GetTaskHistory(r.Context(), id, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, logs, "")
+}
+
+// TEMPLATES
+
+// CreateTemplate handles POST /api/v1/activities/tasks/templates
+func (h *Handler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	var tpl models.TaskTemplate
+	if err := json.NewDecoder(r.Body).Decode(&tpl); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.service.CreateTemplate(r.Context(), &tpl, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, tpl, "Task template created successfully")
+}
+
+// ListTemplates handles GET /api/v1/activities/tasks/templates
+func (h *Handler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	var jurisID *uuid.UUID
+	if id, err := uuid.Parse(r.URL.Query().Get("jurisdiction_id")); err == nil {
+		jurisID = &id
+	}
+
+	list, err := h.service.ListTemplates(r.Context(), jurisID)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch task templates", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// GetTemplate handles GET /api/v1/activities/tasks/templates/{id}
+func (h *Handler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	tpl, err := h.service.GetTemplate(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, tpl, "")
+}
+
+// UpdateTemplate handles PUT /api/v1/activities/tasks/templates/{id}
+func (h *Handler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	var in models.TaskTemplate
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	tpl, err := h.service.UpdateTemplate(r.Context(), id, &in, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, tpl, "Task template updated")
+}
+
+// DeleteTemplate handles DELETE /api/v1/activities/tasks/templates/{id}
+func (h *Handler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	if err := h.service.DeleteTemplate(r.Context(), id, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Task template deleted")
+}
+
+// PauseTemplate handles POST /api/v1/activities/tasks/templates/{id}/pause
+func (h *Handler) PauseTemplate(w http.ResponseWriter, r *http.Request) {
+	h.setTemplatePaused(w, r, true, "Task template paused")
+}
+
+// ResumeTemplate handles POST /api/v1/activities/tasks/templates/{id}/resume
+func (h *Handler) ResumeTemplate(w http.ResponseWriter, r *http.Request) {
+	h.setTemplatePaused(w, r, false, "Task template resumed")
+}
+
+func (h *Handler) setTemplatePaused(w http.ResponseWriter, r *http.Request, paused bool, msg string) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	tpl, err := h.service.SetTemplatePaused(r.Context(), id, paused, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, tpl, msg)
+}
+
+// PreviewTemplate handles GET /api/v1/activities/tasks/templates/{id}/preview?count=
+func (h *Handler) PreviewTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+	count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+
+	tpl, err := h.service.GetTemplate(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	preview, err := h.service.PreviewTemplate(r.Context(), tpl, count)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, preview, "")
+}
+
+// PreviewDraftTemplate handles POST /api/v1/activities/tasks/templates/preview?count=
+// for a template that has not been saved yet
+func (h *Handler) PreviewDraftTemplate(w http.ResponseWriter, r *http.Request) {
+	count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+
+	var tpl models.TaskTemplate
+	if err := json.NewDecoder(r.Body).Decode(&tpl); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	tpl.IsPaused, tpl.NextDueAt = false, nil
+
+	preview, err := h.service.PreviewTemplate(r.Context(), &tpl, count)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, preview, "")
+}
+
+// EDITING
+
+// EditActivity handles PATCH /api/v1/activities/{id}
+func (h *Handler) EditActivity(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid activity ID")
+		return
+	}
+
+	var p models.ActivityPatch
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	a, err := h.service.EditActivity(r.Context(), id, userID, &p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, a, "Activity updated")
+}
+
+// DeleteActivity handles DELETE /api/v1/activities/{id}
+func (h *Handler) DeleteActivity(w http.ResponseWriter, r *http.Request) {
+	h.deleteRecord(w, r, "activity", h.service.DeleteActivity, "Activity deleted")
+}
+
+// RestoreActivity handles POST /api/v1/activities/{id}/restore
+func (h *Handler) RestoreActivity(w http.ResponseWriter, r *http.Request) {
+	h.restoreRecord(w, r, "activity", "Activity restored")
+}
+
+// EditTask handles PATCH /api/v1/activities/tasks/{id}
+func (h *Handler) EditTask(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid task ID")
+		return
+	}
+
+	var p models.TaskPatch
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	t, err := h.service.EditTask(r.Context(), id, userID, &p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, t, "Task updated")
+}
+
+// DeleteTask handles DELETE /api/v1/activities/tasks/{id}
+func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	h.deleteRecord(w, r, "task", h.service.DeleteTask, "Task deleted")
+}
+
+// RestoreTask handles POST /api/v1/activities/tasks/{id}/restore
+func (h *Handler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	h.restoreRecord(w, r, "task", "Task restored")
+}
+
+// EditEvent handles PATCH /api/v1/activities/events/{id}
+func (h *Handler) EditEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid event ID")
+		return
+	}
+
+	var p models.EventPatch
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	e, err := h.service.EditEvent(r.Context(), id, userID, &p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, e, "Event updated")
+}
+
+// DeleteEvent handles DELETE /api/v1/activities/events/{id}
+func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	h.deleteRecord(w, r, "event", h.service.DeleteEvent, "Event deleted")
+}
+
+// RestoreEvent handles POST /api/v1/activities/events/{id}/restore
+func (h *Handler) RestoreEvent(w http.ResponseWriter, r *http.Request) {
+	h.restoreRecord(w, r, "event", "Event restored")
+}
+
+func (h *Handler) deleteRecord(w http.ResponseWriter, r *http.Request, entity string, del func(ctx context.Context, id, userID uuid.UUID) error, msg string) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, fmt.Sprintf("Invalid %s ID", entity))
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := del(r.Context(), id, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, msg)
+}
+
+func (h *Handler) restoreRecord(w http.ResponseWriter, r *http.Request, entity, msg string) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, fmt.Sprintf("Invalid %s ID", entity))
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.Restore(r.Context(), entity, id, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, msg)
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case matchesAny(err, notFoundErrors):
+		response.NotFound(w, err.Error())
+	case matchesAny(err, forbiddenErrors):
+		response.Forbidden(w, err.Error())
+	case matchesAny(err, conflictErrors):
+		response.Conflict(w, err.Error())
+	case matchesAny(err, invalidErrors):
+		response.BadRequest(w, err.Error())
+	default:
+		// Database and other internal failures are not shown to the client
+		response.InternalError(w, "Failed to process activity request", "")
+	}
+}
+
+// Service errors by response status; anything else is an internal error
+var (
+	notFoundErrors = []error{
+		ErrActivityNotFound, ErrTaskNotFound, ErrTemplateNotFound, ErrEventNotFound, ErrInvitationNotFound,
+		ErrAssignmentNotFound, ErrJurisdictionNotFound, ErrMemberNotFound,
+	}
+	forbiddenErrors = []error{
+		ErrNotActivityOwner, ErrNotProofViewer, ErrVerifiedActivityEdit, ErrVerifiedActivityDelete, ErrNotOwner,
+		ErrEditWindowClosed, ErrNotEventManager, ErrNotInvited, ErrNotTemplateManager, ErrOwnActivityReview,
+		ErrNotReviewer, ErrNotSuperAdmin, ErrNotAssignee, ErrNotSupervisor, ErrNotTaskViewer, ErrNotOwnAssignment,
+	}
+	conflictErrors = []error{
+		ErrActivityChanged, ErrReviewChanged, ErrTaskChanged, ErrEventChanged, ErrTaskStatusChanged,
+		ErrAssignmentStatusChanged, ErrOccurrenceGenerated, ErrAlreadyCheckedIn, ErrAlreadyReviewed,
+		ErrTaskAlready, ErrAssignmentAlready, ErrTemplatePaused, ErrTemplateNotPaused,
+	}
+	invalidErrors = []error{
+		ErrTooManyProofs, ErrTitleRequired, ErrUnknownCategory, ErrFutureActivity, ErrInvalidPriority,
+		ErrStartRequired, ErrEndBeforeStart, ErrInvalidCapacity, ErrDeadlineAfterStart, ErrTaskLocked,
+		ErrNoAudience, ErrPositionNeedsScope, ErrEventStarted, ErrUnknownRSVP, ErrRSVPClosed,
+		ErrJurisdictionRequired, ErrLevelRequired, ErrUnknownAssignTo, ErrInvalidLeadDays, ErrStartsAtRequired,
+		ErrInvalidRRule, ErrUnknownReview, ErrDisputeNoteRequired, ErrReviewNoteTooLong, ErrNegativeWeight,
+		ErrFutureMonth, ErrInvalidCheckInCode, ErrCheckInOtherEvent, ErrCheckInCodeExpired, ErrCheckInNotOpen,
+		ErrCheckInClosed, ErrLocationRequired, ErrOutsideGeofence, ErrNoCheckIns, ErrTooManyCheckIns,
+		ErrPartialCoordinates, ErrInvalidCoordinates, ErrInvalidGeofence, ErrGeofenceNeedsVenue,
+		ErrTaskTransition, ErrCommitteeTaskProgress, ErrCommitteeTaskRework, ErrCompletionNoteRequired,
+		ErrTaskProofRequired, ErrReworkNoteRequired, ErrProofClosed, ErrAssignmentDone, ErrTooManyTaskProofs,
+		ErrNotCommitteeTask, ErrAssignmentTransition, ErrReopenNoteRequired,
+	}
+)
+
+func matchesAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,49 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"task not found", ErrTaskNotFound, http.StatusNotFound, ErrTaskNotFound.Error()},
+		{"restore of a record that is not deleted", fmt.Errorf("no deleted record to restore: %w", ErrEventNotFound), http.StatusNotFound, "no deleted record to restore: event not found"},
+		{"assignee action", fmt.Errorf("cannot mark this task as completed: %w", ErrNotAssignee), http.StatusForbidden, "cannot mark this task as completed: only the assignee can update this task"},
+		{"edit window", fmt.Errorf("cannot change this task: %w", ErrEditWindowClosed), http.StatusForbidden, "cannot change this task: the 48-hour edit window has passed"},
+		{"concurrent transition", ErrTaskStatusChanged, http.StatusConflict, ErrTaskStatusChanged.Error()},
+		{"same status", fmt.Errorf("task is already %s: %w", "completed", ErrTaskAlready), http.StatusConflict, "task is already completed"},
+		{"transition", fmt.Errorf("task cannot move from %s to %s: %w", "pending", "verified", ErrTaskTransition), http.StatusBadRequest, "from pending to verified"},
+		{"validation", ErrCompletionNoteRequired, http.StatusBadRequest, ErrCompletionNoteRequired.Error()},
+		{"database error", errors.New(`ERROR: relation "tasks" does not exist (SQLSTATE 42P01)`), http.StatusInternalServerError, ""},
+		{"message ending in not found", errors.New("row not found"), http.StatusInternalServerError, ""},
+		{"message starting with only", errors.New("only one connection allowed"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := rec.Body.String()
+			if tt.wantBody != "" && !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body %s does not contain %q", body, tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(body, tt.err.Error()) {
+				t.Errorf("internal error leaked to the client: %s", body)
+			}
+		})
+	}
+}
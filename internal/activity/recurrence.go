@@ -0,0 +1,355 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/rrule"
+	"github.com/google/uuid"
+)
+
+// Template limits
+const (
+	MaxPreviewOccurrences = 24
+	schedulerInterval     = 15 * time.Minute
+)
+
+// Template errors
+var (
+	ErrTemplatePaused       = errors.New("template is already paused")
+	ErrTemplateNotPaused    = errors.New("template is not paused")
+	ErrNotTemplateManager   = errors.New("only committee leaders at or above this jurisdiction can manage its task templates")
+	ErrJurisdictionRequired = errors.New("jurisdiction_id is required")
+	ErrLevelRequired        = errors.New("level_id is required")
+	ErrUnknownAssignTo      = fmt.Errorf("assign_to must be %q or %q", models.TemplateAssignLeader, models.TemplateAssignCommittee)
+	ErrInvalidLeadDays      = errors.New("lead_days must be between 0 and 365")
+	ErrStartsAtRequired     = errors.New("starts_at is required")
+	ErrInvalidRRule         = errors.New("invalid recurrence rule")
+)
+
+// CreateTemplate validates and stores a recurring task template
+func (s *Service) CreateTemplate(ctx context.Context, tpl *models.TaskTemplate, userID uuid.UUID) error {
+	if err := s.checkTemplateAccess(ctx, userID, tpl.JurisdictionID); err != nil {
+		return err
+	}
+	rule, err := validateTemplate(tpl)
+	if err != nil {
+		return err
+	}
+
+	tpl.CreatedBy = userID
+	tpl.NextDueAt = nextDue(rule, time.Now())
+	return s.repo.CreateTemplate(ctx, tpl)
+}
+
+// GetTemplate returns a template
+func (s *Service) GetTemplate(ctx context.Context, id uuid.UUID) (*models.TaskTemplate, error) {
+	return s.repo.GetTemplate(ctx, id)
+}
+
+// ListTemplates returns templates, optionally those rooted at a jurisdiction
+func (s *Service) ListTemplates(ctx context.Context, jurisdictionID *uuid.UUID) ([]*models.TaskTemplate, error) {
+	return s.repo.ListTemplates(ctx, jurisdictionID)
+}
+
+// UpdateTemplate replaces a template's settings. The schedule restarts from now, so occurrences
+// that were already generated are not repeated and missed ones are not back-filled.
+func (s *Service) UpdateTemplate(ctx context.Context, id uuid.UUID, in *models.TaskTemplate, userID uuid.UUID) (*models.TaskTemplate, error) {
+	tpl, err := s.repo.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTemplateAccess(ctx, userID, tpl.JurisdictionID); err != nil {
+		return nil, err
+	}
+
+	// The root jurisdiction and pause state are kept; everything else comes from the request
+	tpl.Title, tpl.Description, tpl.Priority = in.Title, in.Description, in.Priority
+	tpl.RequiresCompletionNote, tpl.RequiresProof = in.RequiresCompletionNote, in.RequiresProof
+	tpl.LevelID, tpl.AssignTo = in.LevelID, in.AssignTo
+	tpl.RRule, tpl.StartsAt, tpl.LeadDays = in.RRule, in.StartsAt, in.LeadDays
+
+	rule, err := validateTemplate(tpl)
+	if err != nil {
+		return nil, err
+	}
+	tpl.NextDueAt = nextDue(rule, time.Now())
+	if err := s.repo.UpdateTemplate(ctx, tpl); err != nil {
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// SetTemplatePaused pauses or resumes a template. Occurrences that fall due while paused are skipped.
+func (s *Service) SetTemplatePaused(ctx context.Context, id uuid.UUID, paused bool, userID uuid.UUID) (*models.TaskTemplate, error) {
+	tpl, err := s.repo.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTemplateAccess(ctx, userID, tpl.JurisdictionID); err != nil {
+		return nil, err
+	}
+	if tpl.IsPaused == paused {
+		if paused {
+			return nil, ErrTemplatePaused
+		}
+		return nil, ErrTemplateNotPaused
+	}
+
+	rule, err := rrule.Parse(tpl.RRule, tpl.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+	tpl.IsPaused = paused
+	tpl.NextDueAt = nextDue(rule, time.Now())
+	if err := s.repo.UpdateTemplate(ctx, tpl); err != nil {
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// DeleteTemplate stops a template for good; tasks it generated are kept
+func (s *Service) DeleteTemplate(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	tpl, err := s.repo.GetTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkTemplateAccess(ctx, userID, tpl.JurisdictionID); err != nil {
+		return err
+	}
+	return s.repo.DeleteTemplate(ctx, id)
+}
+
+// PreviewTemplate lists the next occurrences of a (possibly unsaved) template and the
+// jurisdictions that would receive tasks
+func (s *Service) PreviewTemplate(ctx context.Context, tpl *models.TaskTemplate, count int) (*models.TemplatePreview, error) {
+	rule, err := validateTemplate(tpl)
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 || count > MaxPreviewOccurrences {
+		count = MaxPreviewOccurrences
+	}
+
+	preview := &models.TemplatePreview{RRule: tpl.RRule}
+	// A paused template shows what it would do if resumed now
+	after := time.Now()
+	if !tpl.IsPaused && tpl.NextDueAt != nil {
+		after = tpl.NextDueAt.Add(-time.Second)
+	}
+	for _, due := range rule.Next(after, count) {
+		preview.Occurrences = append(preview.Occurrences, &models.TemplateOccurrence{
+			DueAt:    due,
+			CreateAt: due.AddDate(0, 0, -tpl.LeadDays),
+		})
+	}
+
+	preview.Targets, err = s.repo.ListTemplateTargets(ctx, tpl.JurisdictionID, tpl.LevelID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range preview.Targets {
+		if t.CommitteeID == nil {
+			preview.Skipped++
+		}
+	}
+	return preview, nil
+}
+
+// checkTemplateAccess allows the Super Admin, and committee leaders (positions up to
+// auth.LeaderRank) whose jurisdiction is the template's root or above it
+func (s *Service) checkTemplateAccess(ctx context.Context, userID, jurisdictionID uuid.UUID) error {
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if authority.SuperAdmin {
+		return nil
+	}
+	if authority.JurisdictionID != nil && authority.IsLeader() {
+		ok, err := s.org.IsChildJurisdiction(ctx, *authority.JurisdictionID, jurisdictionID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return ErrNotTemplateManager
+}
+
+// validateTemplate applies defaults, checks fields and normalizes the recurrence rule
+func validateTemplate(tpl *models.TaskTemplate) (*rrule.Rule, error) {
+	tpl.Title = strings.TrimSpace(tpl.Title)
+	if tpl.Title == "" {
+		return nil, ErrTitleRequired
+	}
+	if tpl.JurisdictionID == uuid.Nil {
+		return nil, ErrJurisdictionRequired
+	}
+	if tpl.LevelID <= 0 {
+		return nil, ErrLevelRequired
+	}
+	if tpl.Priority == 0 {
+		tpl.Priority = 3 // Medium default
+	}
+	if tpl.Priority < 1 || tpl.Priority > 4 {
+		return nil, ErrInvalidPriority
+	}
+	if tpl.AssignTo == "" {
+		tpl.AssignTo = models.TemplateAssignLeader
+	}
+	if tpl.AssignTo != models.TemplateAssignLeader && tpl.AssignTo != models.TemplateAssignCommittee {
+		return nil, ErrUnknownAssignTo
+	}
+	if tpl.LeadDays < 0 || tpl.LeadDays > 365 {
+		return nil, ErrInvalidLeadDays
+	}
+	if tpl.StartsAt.IsZero() {
+		return nil, ErrStartsAtRequired
+	}
+
+	rule, err := rrule.Parse(tpl.RRule, tpl.StartsAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+	}
+	tpl.RRule = rule.String()
+	return rule, nil
+}
+
+// nextDue returns the first occurrence after now, or nil when the rule has ended
+func nextDue(rule *rrule.Rule, now time.Time) *time.Time {
+	if next, ok := rule.After(now); ok {
+		return &next
+	}
+	return nil
+}
+
+// GenerateDueTasks creates tasks for every template occurrence that has entered its lead time.
+// Occurrences that are already past due (e.g. after downtime) are skipped rather than created overdue.
+func (s *Service) GenerateDueTasks(ctx context.Context, now time.Time) error {
+	templates, err := s.repo.ListDueTemplates(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, tpl := range templates {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := s.generateTemplate(ctx, tpl, now); err != nil {
+			log.Printf("tasks: template %s failed: %v", tpl.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) generateTemplate(ctx context.Context, tpl *models.TaskTemplate, now time.Time) error {
+	rule, err := rrule.Parse(tpl.RRule, tpl.StartsAt)
+	if err != nil {
+		return err
+	}
+
+	horizon := now.AddDate(0, 0, tpl.LeadDays)
+	for due := tpl.NextDueAt; due != nil && !due.After(horizon); {
+		// 1. Create this round; repeats after a crash are absorbed by the unique occurrence index
+		if due.After(now) {
+			created, err := s.generateOccurrence(ctx, tpl, *due)
+			if err != nil {
+				return err
+			}
+			log.Printf("tasks: template %s generated %d task(s) due %s", tpl.ID, created, due.Format(time.RFC3339))
+		}
+
+		// 2. Move on; stop if the template changed meanwhile
+		next := nextDue(rule, *due)
+		ok, err := s.repo.AdvanceTemplate(ctx, tpl.ID, *due, next)
+		if err != nil || !ok {
+			return err
+		}
+		due = next
+	}
+	return nil
+}
+
+// generateOccurrence creates one round of tasks and returns how many were new
+func (s *Service) generateOccurrence(ctx context.Context, tpl *models.TaskTemplate, due time.Time) (int, error) {
+	targets, err := s.repo.ListTemplateTargets(ctx, tpl.JurisdictionID, tpl.LevelID)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, target := range targets {
+		if target.CommitteeID == nil {
+			continue
+		}
+		dueDate := due
+		t := &models.Task{
+			CreatorID:              tpl.CreatedBy,
+			JurisdictionID:         target.JurisdictionID,
+			Title:                  tpl.Title,
+			Description:            tpl.Description,
+			Priority:               tpl.Priority,
+			DueDate:                &dueDate,
+			RequiresCompletionNote: tpl.RequiresCompletionNote,
+			RequiresProof:          tpl.RequiresProof,
+			TemplateID:             &tpl.ID,
+		}
+		// Leader mode falls back to the whole committee while the head posts are vacant
+		if tpl.AssignTo == models.TemplateAssignLeader && target.LeaderID != nil {
+			t.AssigneeID = target.LeaderID
+		} else {
+			t.CommitteeID = target.CommitteeID
+		}
+
+		if err := s.CreateTask(ctx, t); err != nil {
+			if errors.Is(err, ErrOccurrenceGenerated) {
+				continue
+			}
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// Scheduler is the background job that generates tasks from recurring templates, sends event
+// RSVP reminders and refreshes the monthly activity scores
+type Scheduler struct {
+	service *Service
+}
+
+// NewScheduler creates the recurring task scheduler
+func NewScheduler(service *Service) *Scheduler {
+	return &Scheduler{service: service}
+}
+
+// Run generates due tasks, sends reminders and refreshes scores periodically until ctx is cancelled
+func (sc *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := sc.service.GenerateDueTasks(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("tasks: template scheduling failed: %v", err)
+		}
+		if err := sc.service.SendRSVPReminders(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("events: rsvp reminders failed: %v", err)
+		}
+		if err := sc.service.RefreshCurrentScores(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("activities: score refresh failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,9 +2,11 @@ package activity
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/bjdms/api/internal/auth"
 	"github.com/bjdms/api/internal/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -21,6 +23,32 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
+// Lookup errors
+var (
+	ErrActivityNotFound   = errors.New("activity not found")
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrTemplateNotFound   = errors.New("template not found")
+	ErrEventNotFound      = errors.New("event not found")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrNotInvited         = errors.New("only invited members can respond to this event")
+)
+
+// Concurrent edit errors
+var (
+	ErrActivityChanged = errors.New("activity changed concurrently, reload and try again")
+	ErrReviewChanged   = errors.New("activity review changed concurrently, reload and try again")
+	ErrTaskChanged     = errors.New("task changed concurrently, reload and try again")
+	ErrEventChanged    = errors.New("event changed concurrently, reload and try again")
+)
+
+// Task workflow errors
+var (
+	ErrAssignmentNotFound      = errors.New("assignment not found")
+	ErrTaskStatusChanged       = errors.New("task status changed concurrently, reload and try again")
+	ErrAssignmentStatusChanged = errors.New("assignment status changed concurrently, reload and try again")
+	ErrOccurrenceGenerated     = errors.New("task already generated for this occurrence")
+)
+
 // ACTIVITIES
 
 // CreateActivity inserts a new activity record
@@ -28,42 +56,51 @@ func (r *Repository) CreateActivity(ctx context.Context, a *models.Activity) err
 	query := `
 		INSERT INTO activities (user_id, jurisdiction_id, committee_id, title, description, category, activity_date)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at, updated_at
+		RETURNING id, created_at, updated_at, review_status
 	`
 	if a.ActivityDate.IsZero() {
 		a.ActivityDate = time.Now()
 	}
 	return r.db.QueryRow(ctx, query,
 		a.UserID, a.JurisdictionID, a.CommitteeID, a.Title, a.Description, a.Category, a.ActivityDate,
-	).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
+	).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt, &a.ReviewStatus)
+}
+
+const activityColumns = `
+	a.id, a.user_id, a.jurisdiction_id, a.committee_id, a.title, a.description, a.category, a.activity_date, a.created_at, a.updated_at,
+	a.review_status, a.reviewed_by, a.reviewed_at, a.review_note,
+	u.full_name as user_name, j.name as jurisdiction_name
+`
+
+func scanActivity(row pgx.Row, a *models.Activity) error {
+	return row.Scan(
+		&a.ID, &a.UserID, &a.JurisdictionID, &a.CommitteeID, &a.Title, &a.Description, &a.Category, &a.ActivityDate, &a.CreatedAt, &a.UpdatedAt,
+		&a.ReviewStatus, &a.ReviewedBy, &a.ReviewedAt, &a.ReviewNote,
+		&a.UserName, &a.JurisdictionName,
+	)
 }
 
 // GetActivity retrieves an activity by ID
 func (r *Repository) GetActivity(ctx context.Context, id uuid.UUID) (*models.Activity, error) {
 	query := `
-		SELECT a.id, a.user_id, a.jurisdiction_id, a.committee_id, a.title, a.description, a.category, a.activity_date, a.created_at, a.updated_at,
-		       u.full_name as user_name, j.name as jurisdiction_name
+		SELECT ` + activityColumns + `
 		FROM activities a
 		JOIN users u ON a.user_id = u.id
 		JOIN jurisdictions j ON a.jurisdiction_id = j.id
 		WHERE a.id = $1 AND a.deleted_at IS NULL
 	`
 	var a models.Activity
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&a.ID, &a.UserID, &a.JurisdictionID, &a.CommitteeID, &a.Title, &a.Description, &a.Category, &a.ActivityDate, &a.CreatedAt, &a.UpdatedAt,
-		&a.UserName, &a.JurisdictionName,
-	)
+	err := scanActivity(r.db.QueryRow(ctx, query, id), &a)
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("activity not found")
+		return nil, ErrActivityNotFound
 	}
 	return &a, err
 }
 
-// ListActivities returns activities filtered by jurisdiction and/or user
-func (r *Repository) ListActivities(ctx context.Context, jurisdictionID *uuid.UUID, userID *uuid.UUID, limit, offset int) ([]*models.Activity, error) {
+// ListActivities returns activities filtered by jurisdiction, user and/or review status
+func (r *Repository) ListActivities(ctx context.Context, jurisdictionID *uuid.UUID, userID *uuid.UUID, reviewStatus string, limit, offset int) ([]*models.Activity, error) {
 	query := `
-		SELECT a.id, a.user_id, a.jurisdiction_id, a.committee_id, a.title, a.description, a.category, a.activity_date, a.created_at, a.updated_at,
-		       u.full_name as user_name, j.name as jurisdiction_name
+		SELECT ` + activityColumns + `
 		FROM activities a
 		JOIN users u ON a.user_id = u.id
 		JOIN jurisdictions j ON a.jurisdiction_id = j.id
@@ -78,6 +115,10 @@ func (r *Repository) ListActivities(ctx context.Context, jurisdictionID *uuid.UU
 		args = append(args, *userID)
 		query += fmt.Sprintf(" AND a.user_id = $%d", len(args))
 	}
+	if reviewStatus != "" {
+		args = append(args, reviewStatus)
+		query += fmt.Sprintf(" AND a.review_status = $%d", len(args))
+	}
 
 	query += fmt.Sprintf(" ORDER BY a.activity_date DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 	args = append(args, limit, offset)
@@ -91,52 +132,445 @@ func (r *Repository) ListActivities(ctx context.Context, jurisdictionID *uuid.UU
 	var list []*models.Activity
 	for rows.Next() {
 		var a models.Activity
+		if err := scanActivity(rows, &a); err != nil {
+			return nil, err
+		}
+		list = append(list, &a)
+	}
+	return list, nil
+}
+
+// ReviewActivity moves an activity from one review state to another and records it in the
+// review history. Fails if the state changed since the reviewer loaded it.
+func (r *Repository) ReviewActivity(ctx context.Context, id, reviewerID uuid.UUID, from, to string, note *string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE activities
+		SET review_status = $3, reviewed_by = $4, reviewed_at = NOW(), review_note = $5, updated_at = NOW()
+		WHERE id = $1 AND review_status = $2 AND deleted_at IS NULL
+	`
+	tag, err := tx.Exec(ctx, query, id, from, to, reviewerID, note)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReviewChanged
+	}
+
+	logQuery := `
+		INSERT INTO activity_reviews (activity_id, reviewer_id, from_status, to_status, note)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.Exec(ctx, logQuery, id, reviewerID, from, to, note); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ListActivityReviews returns an activity's review history, oldest first
+func (r *Repository) ListActivityReviews(ctx context.Context, activityID uuid.UUID) ([]*models.ActivityReview, error) {
+	query := `
+		SELECT ar.id, ar.activity_id, ar.reviewer_id, u.full_name, ar.from_status, ar.to_status, ar.note, ar.created_at
+		FROM activity_reviews ar
+		JOIN users u ON ar.reviewer_id = u.id
+		WHERE ar.activity_id = $1
+		ORDER BY ar.created_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ActivityReview
+	for rows.Next() {
+		var ar models.ActivityReview
+		err := rows.Scan(&ar.ID, &ar.ActivityID, &ar.ReviewerID, &ar.ReviewerName, &ar.FromStatus, &ar.ToStatus, &ar.Note, &ar.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &ar)
+	}
+	return list, rows.Err()
+}
+
+// CreateProof links an uploaded file to an activity or a task
+func (r *Repository) CreateProof(ctx context.Context, p *models.ActivityProof) error {
+	query := `
+		INSERT INTO activity_proofs (activity_id, task_id, file_path, file_type, file_size, content_hash, original_name, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, processing_status
+	`
+	return r.db.QueryRow(ctx, query,
+		p.ActivityID, p.TaskID, p.FilePath, p.FileType, p.FileSize, p.ContentHash, p.OriginalName, p.UploadedBy,
+	).Scan(&p.ID, &p.CreatedAt, &p.ProcessingStatus)
+}
+
+// ListProofs returns the files attached to an activity
+func (r *Repository) ListProofs(ctx context.Context, activityID uuid.UUID) ([]*models.ActivityProof, error) {
+	return r.listProofs(ctx, "activity_id", activityID)
+}
+
+// ListTaskProofs returns the files attached to a task
+func (r *Repository) ListTaskProofs(ctx context.Context, taskID uuid.UUID) ([]*models.ActivityProof, error) {
+	return r.listProofs(ctx, "task_id", taskID)
+}
+
+func (r *Repository) listProofs(ctx context.Context, column string, id uuid.UUID) ([]*models.ActivityProof, error) {
+	query := fmt.Sprintf(`
+		SELECT id, activity_id, task_id, file_path, COALESCE(file_type, ''), COALESCE(file_size, 0),
+		       COALESCE(content_hash, ''), COALESCE(original_name, ''), uploaded_by, created_at,
+		       processing_status, processing_error, processed_at, original_path, thumbnail_path
+		FROM activity_proofs
+		WHERE %s = $1
+		ORDER BY created_at ASC
+	`, column)
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ActivityProof
+	for rows.Next() {
+		var p models.ActivityProof
 		err := rows.Scan(
-			&a.ID, &a.UserID, &a.JurisdictionID, &a.CommitteeID, &a.Title, &a.Description, &a.Category, &a.ActivityDate, &a.CreatedAt, &a.UpdatedAt,
-			&a.UserName, &a.JurisdictionName,
+			&p.ID, &p.ActivityID, &p.TaskID, &p.FilePath, &p.FileType, &p.FileSize,
+			&p.ContentHash, &p.OriginalName, &p.UploadedBy, &p.CreatedAt,
+			&p.ProcessingStatus, &p.ProcessingError, &p.ProcessedAt, &p.OriginalPath, &p.ThumbnailPath,
 		)
 		if err != nil {
 			return nil, err
 		}
-		list = append(list, &a)
+		list = append(list, &p)
 	}
 	return list, nil
 }
 
+// CountProofs returns how many files are attached to an activity
+func (r *Repository) CountProofs(ctx context.Context, activityID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM activity_proofs WHERE activity_id = $1", activityID).Scan(&n)
+	return n, err
+}
+
+// CountTaskProofs returns how many usable files are attached to a task, optionally only those
+// from one uploader. Rejected and failed uploads do not count.
+func (r *Repository) CountTaskProofs(ctx context.Context, taskID uuid.UUID, uploadedBy *uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM activity_proofs
+		WHERE task_id = $1 AND processing_status NOT IN ('rejected', 'failed')
+		  AND ($2::uuid IS NULL OR uploaded_by = $2)
+	`, taskID, uploadedBy).Scan(&n)
+	return n, err
+}
+
 // TASKS
 
-// CreateTask inserts a new task
+const taskColumns = `
+	id, creator_id, assignee_id, committee_id, jurisdiction_id, title, description, status, priority, due_date,
+	completed_at, verified_at, created_at, updated_at, requires_completion_note, requires_proof, completion_note,
+	reminded_at, overdue_at, escalation_level, escalated_at, template_id
+`
+
+func scanTask(row pgx.Row, t *models.Task) error {
+	return row.Scan(
+		&t.ID, &t.CreatorID, &t.AssigneeID, &t.CommitteeID, &t.JurisdictionID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.DueDate,
+		&t.CompletedAt, &t.VerifiedAt, &t.CreatedAt, &t.UpdatedAt, &t.RequiresCompletionNote, &t.RequiresProof, &t.CompletionNote,
+		&t.RemindedAt, &t.OverdueAt, &t.EscalationLevel, &t.EscalatedAt, &t.TemplateID,
+	)
+}
+
+// CreateTask inserts a new task and the first history entry
 func (r *Repository) CreateTask(ctx context.Context, t *models.Task) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// A template occurrence is generated once per jurisdiction
 	query := `
-		INSERT INTO tasks (creator_id, assignee_id, committee_id, jurisdiction_id, title, description, status, priority, due_date)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO tasks (creator_id, assignee_id, committee_id, jurisdiction_id, title, description, status, priority, due_date,
+		                   requires_completion_note, requires_proof, template_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (template_id, jurisdiction_id, due_date) WHERE template_id IS NOT NULL DO NOTHING
 		RETURNING id, created_at, updated_at
 	`
-	return r.db.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		t.CreatorID, t.AssigneeID, t.CommitteeID, t.JurisdictionID, t.Title, t.Description, t.Status, t.Priority, t.DueDate,
+		t.RequiresCompletionNote, t.RequiresProof, t.TemplateID,
 	).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrOccurrenceGenerated
+	}
+	if err != nil {
+		return err
+	}
+
+	logQuery := `
+		INSERT INTO task_logs (task_id, user_id, action, new_status)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.Exec(ctx, logQuery, t.ID, t.CreatorID, models.TaskActionCreated, t.Status); err != nil {
+		return err
+	}
+
+	// A committee task gets one assignment per active member
+	if t.AssigneeID == nil && t.CommitteeID != nil {
+		fanOutQuery := `
+			INSERT INTO task_assignments (task_id, user_id)
+			SELECT DISTINCT $1::uuid, user_id FROM committee_members
+			WHERE committee_id = $2 AND ended_at IS NULL AND is_active = TRUE
+			ON CONFLICT (task_id, user_id) DO NOTHING
+		`
+		if _, err := tx.Exec(ctx, fanOutQuery, t.ID, *t.CommitteeID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTask retrieves a task by ID
+func (r *Repository) GetTask(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL`
+	var t models.Task
+	err := scanTask(r.db.QueryRow(ctx, query, id), &t)
+	if err == pgx.ErrNoRows {
+		return nil, ErrTaskNotFound
+	}
+	return &t, err
 }
 
-// UpdateTaskStatus updates a task status
-func (r *Repository) UpdateTaskStatus(ctx context.Context, id uuid.UUID, status string) error {
+// TransitionTask moves a task from one status to another and logs the change in a transaction.
+// It fails if the task is no longer in the expected status.
+func (r *Repository) TransitionTask(ctx context.Context, id uuid.UUID, from, to string, userID uuid.UUID, note string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Update status and the matching timestamps
 	query := `
-		UPDATE tasks 
-		SET status = $1, 
+		UPDATE tasks
+		SET status = $3,
 		    updated_at = NOW(),
-		    completed_at = CASE WHEN $1 = 'completed' THEN NOW() ELSE completed_at END
-		WHERE id = $2 AND deleted_at IS NULL
+		    completed_at = CASE WHEN $3 = 'completed' THEN NOW()
+		                        WHEN $3 IN ('pending', 'in_progress') THEN NULL
+		                        ELSE completed_at END,
+		    verified_at = CASE WHEN $3 = 'verified' THEN NOW() ELSE verified_at END,
+		    completion_note = CASE WHEN $3 = 'completed' THEN NULLIF($4, '') ELSE completion_note END
+		WHERE id = $1 AND status = $2 AND deleted_at IS NULL
 	`
-	_, err := r.db.Exec(ctx, query, status, id)
-	return err
+	tag, err := tx.Exec(ctx, query, id, from, to, note)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTaskStatusChanged
+	}
+
+	// 2. Log the change
+	logQuery := `
+		INSERT INTO task_logs (task_id, user_id, action, old_status, new_status, note)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+	`
+	_, err = tx.Exec(ctx, logQuery, id, userID, models.TaskActionStatusChange, from, to, note)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// ListTasks returns tasks filtered by jurisdiction or assignee
-func (r *Repository) ListTasks(ctx context.Context, jurisdictionID *uuid.UUID, assigneeID *uuid.UUID, committeeID *uuid.UUID) ([]*models.Task, error) {
+// AddTaskLog appends an entry to a task's history
+func (r *Repository) AddTaskLog(ctx context.Context, l *models.TaskLog) error {
+	query := `
+		INSERT INTO task_logs (task_id, user_id, action, old_status, new_status, note, assignment_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, query,
+		l.TaskID, l.UserID, l.Action, l.OldStatus, l.NewStatus, l.Note, l.AssignmentUserID,
+	).Scan(&l.ID, &l.CreatedAt)
+}
+
+// ListTaskLogs returns a task's history, oldest first
+func (r *Repository) ListTaskLogs(ctx context.Context, taskID uuid.UUID) ([]*models.TaskLog, error) {
+	query := `
+		SELECT l.id, l.task_id, l.user_id, l.action, l.old_status::text, l.new_status::text, l.note, l.created_at,
+		       l.assignment_user_id, COALESCE(u.full_name, '') as user_name, COALESCE(au.full_name, '') as assignment_user_name
+		FROM task_logs l
+		LEFT JOIN users u ON l.user_id = u.id
+		LEFT JOIN users au ON l.assignment_user_id = au.id
+		WHERE l.task_id = $1
+		ORDER BY l.created_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.TaskLog
+	for rows.Next() {
+		var l models.TaskLog
+		err := rows.Scan(
+			&l.ID, &l.TaskID, &l.UserID, &l.Action, &l.OldStatus, &l.NewStatus, &l.Note, &l.CreatedAt,
+			&l.AssignmentUserID, &l.UserName, &l.AssignmentUserName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &l)
+	}
+	return list, nil
+}
+
+// TASK ASSIGNMENTS
+
+// GetAssignment retrieves one member's assignment on a committee task
+func (r *Repository) GetAssignment(ctx context.Context, taskID, userID uuid.UUID) (*models.TaskAssignment, error) {
+	query := `
+		SELECT a.id, a.task_id, a.user_id, a.status::text, a.note, a.completed_at, a.created_at, a.updated_at,
+		       COALESCE(u.full_name, '') as user_name
+		FROM task_assignments a
+		LEFT JOIN users u ON a.user_id = u.id
+		WHERE a.task_id = $1 AND a.user_id = $2
+	`
+	var a models.TaskAssignment
+	err := r.db.QueryRow(ctx, query, taskID, userID).Scan(
+		&a.ID, &a.TaskID, &a.UserID, &a.Status, &a.Note, &a.CompletedAt, &a.CreatedAt, &a.UpdatedAt, &a.UserName,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrAssignmentNotFound
+	}
+	return &a, err
+}
+
+// ListAssignments returns the member checklist of a committee task
+func (r *Repository) ListAssignments(ctx context.Context, taskID uuid.UUID) ([]*models.TaskAssignment, error) {
+	query := `
+		SELECT a.id, a.task_id, a.user_id, a.status::text, a.note, a.completed_at, a.created_at, a.updated_at,
+		       COALESCE(u.full_name, '') as user_name
+		FROM task_assignments a
+		LEFT JOIN users u ON a.user_id = u.id
+		WHERE a.task_id = $1
+		ORDER BY u.full_name ASC
+	`
+	rows, err := r.db.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.TaskAssignment
+	for rows.Next() {
+		var a models.TaskAssignment
+		err := rows.Scan(&a.ID, &a.TaskID, &a.UserID, &a.Status, &a.Note, &a.CompletedAt, &a.CreatedAt, &a.UpdatedAt, &a.UserName)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &a)
+	}
+	return list, nil
+}
+
+// TransitionAssignment moves one member's assignment from one status to another and logs the
+// change against the task. It fails if the assignment is no longer in the expected status.
+func (r *Repository) TransitionAssignment(ctx context.Context, taskID, memberID uuid.UUID, from, to string, actorID uuid.UUID, note string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Update status
 	query := `
-		SELECT id, creator_id, assignee_id, committee_id, jurisdiction_id, title, description, status, priority, due_date, completed_at, verified_at, created_at, updated_at
-		FROM tasks
-		WHERE deleted_at IS NULL
+		UPDATE task_assignments
+		SET status = $4,
+		    updated_at = NOW(),
+		    completed_at = CASE WHEN $4 = 'completed' THEN NOW() ELSE NULL END,
+		    note = CASE WHEN $4 = 'completed' THEN NULLIF($5, '') ELSE note END
+		WHERE task_id = $1 AND user_id = $2 AND status = $3
+	`
+	tag, err := tx.Exec(ctx, query, taskID, memberID, from, to, note)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAssignmentStatusChanged
+	}
+
+	// 2. Log the change
+	logQuery := `
+		INSERT INTO task_logs (task_id, user_id, action, old_status, new_status, note, assignment_user_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+	`
+	_, err = tx.Exec(ctx, logQuery, taskID, actorID, models.TaskActionAssignment, from, to, note, memberID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTaskProgress aggregates assignment statuses for the given tasks. Tasks without
+// assignments are absent from the result.
+func (r *Repository) GetTaskProgress(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID]*models.TaskProgress, error) {
+	query := `
+		SELECT task_id,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'pending'),
+		       COUNT(*) FILTER (WHERE status = 'in_progress'),
+		       COUNT(*) FILTER (WHERE status = 'completed')
+		FROM task_assignments
+		WHERE task_id = ANY($1)
+		GROUP BY task_id
+	`
+	rows, err := r.db.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make(map[uuid.UUID]*models.TaskProgress)
+	for rows.Next() {
+		var id uuid.UUID
+		var p models.TaskProgress
+		if err := rows.Scan(&id, &p.Total, &p.Pending, &p.InProgress, &p.Completed); err != nil {
+			return nil, err
+		}
+		p.Summary = fmt.Sprintf("%d/%d completed", p.Completed, p.Total)
+		progress[id] = &p
+	}
+	return progress, rows.Err()
+}
+
+// IsActiveCommitteeMember checks whether a user currently sits on a committee
+func (r *Repository) IsActiveCommitteeMember(ctx context.Context, committeeID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM committee_members
+			WHERE committee_id = $1 AND user_id = $2 AND ended_at IS NULL AND is_active = TRUE
+		)
 	`
+	var ok bool
+	err := r.db.QueryRow(ctx, query, committeeID, userID).Scan(&ok)
+	return ok, err
+}
+
+// ListTasks returns tasks filtered by jurisdiction or assignee (including committee members with an assignment)
+func (r *Repository) ListTasks(ctx context.Context, jurisdictionID *uuid.UUID, assigneeID *uuid.UUID, committeeID *uuid.UUID) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE deleted_at IS NULL`
 	args := []interface{}{}
 	if jurisdictionID != nil {
 		args = append(args, *jurisdictionID)
@@ -144,7 +578,7 @@ func (r *Repository) ListTasks(ctx context.Context, jurisdictionID *uuid.UUID, a
 	}
 	if assigneeID != nil {
 		args = append(args, *assigneeID)
-		query += fmt.Sprintf(" AND assignee_id = $%d", len(args))
+		query += fmt.Sprintf(" AND (assignee_id = $%[1]d OR EXISTS (SELECT 1 FROM task_assignments a WHERE a.task_id = tasks.id AND a.user_id = $%[1]d))", len(args))
 	}
 	if committeeID != nil {
 		args = append(args, *committeeID)
@@ -162,10 +596,7 @@ func (r *Repository) ListTasks(ctx context.Context, jurisdictionID *uuid.UUID, a
 	var list []*models.Task
 	for rows.Next() {
 		var t models.Task
-		err := rows.Scan(
-			&t.ID, &t.CreatorID, &t.AssigneeID, &t.CommitteeID, &t.JurisdictionID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.DueDate, &t.CompletedAt, &t.VerifiedAt, &t.CreatedAt, &t.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanTask(rows, &t); err != nil {
 			return nil, err
 		}
 		list = append(list, &t)
@@ -173,33 +604,64 @@ func (r *Repository) ListTasks(ctx context.Context, jurisdictionID *uuid.UUID, a
 	return list, nil
 }
 
-// EVENTS
+// TEMPLATES
 
-// CreateEvent inserts a new event
-func (r *Repository) CreateEvent(ctx context.Context, e *models.Event) error {
+const templateColumns = `
+	t.id, t.title, COALESCE(t.description, ''), t.priority, t.requires_completion_note, t.requires_proof,
+	t.jurisdiction_id, t.level_id, t.assign_to, t.rrule, t.starts_at, t.lead_days, t.is_paused,
+	t.next_due_at, t.last_generated_at, t.created_by, t.created_at, t.updated_at,
+	j.name, jl.name
+`
+
+const templateFrom = `
+	FROM task_templates t
+	JOIN jurisdictions j ON t.jurisdiction_id = j.id
+	JOIN jurisdiction_levels jl ON t.level_id = jl.id
+`
+
+func scanTemplate(row pgx.Row, t *models.TaskTemplate) error {
+	return row.Scan(
+		&t.ID, &t.Title, &t.Description, &t.Priority, &t.RequiresCompletionNote, &t.RequiresProof,
+		&t.JurisdictionID, &t.LevelID, &t.AssignTo, &t.RRule, &t.StartsAt, &t.LeadDays, &t.IsPaused,
+		&t.NextDueAt, &t.LastGeneratedAt, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
+		&t.JurisdictionName, &t.LevelName,
+	)
+}
+
+// CreateTemplate inserts a task template
+func (r *Repository) CreateTemplate(ctx context.Context, t *models.TaskTemplate) error {
 	query := `
-		INSERT INTO events (jurisdiction_id, organizer_id, title, description, location, start_time, end_time, is_public)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO task_templates (title, description, priority, requires_completion_note, requires_proof,
+		                            jurisdiction_id, level_id, assign_to, rrule, starts_at, lead_days, is_paused, next_due_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at, updated_at
 	`
 	return r.db.QueryRow(ctx, query,
-		e.JurisdictionID, e.OrganizerID, e.Title, e.Description, e.Location, e.StartTime, e.EndTime, e.IsPublic,
-	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+		t.Title, t.Description, t.Priority, t.RequiresCompletionNote, t.RequiresProof,
+		t.JurisdictionID, t.LevelID, t.AssignTo, t.RRule, t.StartsAt, t.LeadDays, t.IsPaused, t.NextDueAt, t.CreatedBy,
+	).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
 }
 
-// ListEvents returns events for a jurisdiction
-func (r *Repository) ListEvents(ctx context.Context, jurisdictionID *uuid.UUID) ([]*models.Event, error) {
-	query := `
-		SELECT id, jurisdiction_id, organizer_id, title, description, location, start_time, end_time, is_public, created_at, updated_at
-		FROM events
-		WHERE deleted_at IS NULL
-	`
+// GetTemplate retrieves a task template by ID
+func (r *Repository) GetTemplate(ctx context.Context, id uuid.UUID) (*models.TaskTemplate, error) {
+	query := `SELECT ` + templateColumns + templateFrom + ` WHERE t.id = $1 AND t.deleted_at IS NULL`
+	var t models.TaskTemplate
+	err := scanTemplate(r.db.QueryRow(ctx, query, id), &t)
+	if err == pgx.ErrNoRows {
+		return nil, ErrTemplateNotFound
+	}
+	return &t, err
+}
+
+// ListTemplates returns templates, optionally those rooted at a jurisdiction
+func (r *Repository) ListTemplates(ctx context.Context, jurisdictionID *uuid.UUID) ([]*models.TaskTemplate, error) {
+	query := `SELECT ` + templateColumns + templateFrom + ` WHERE t.deleted_at IS NULL`
 	args := []interface{}{}
 	if jurisdictionID != nil {
 		args = append(args, *jurisdictionID)
-		query += " AND jurisdiction_id = $1"
+		query += " AND t.jurisdiction_id = $1"
 	}
-	query += " ORDER BY start_time DESC"
+	query += " ORDER BY t.is_paused ASC, t.next_due_at ASC NULLS LAST, t.title ASC"
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -207,27 +669,1166 @@ func (r *Repository) ListEvents(ctx context.Context, jurisdictionID *uuid.UUID)
 	}
 	defer rows.Close()
 
-	var list []*models.Event
+	var list []*models.TaskTemplate
 	for rows.Next() {
-		var e models.Event
-		err := rows.Scan(
-			&e.ID, &e.JurisdictionID, &e.OrganizerID, &e.Title, &e.Description, &e.Location, &e.StartTime, &e.EndTime, &e.IsPublic, &e.CreatedAt, &e.UpdatedAt,
-		)
-		if err != nil {
+		var t models.TaskTemplate
+		if err := scanTemplate(rows, &t); err != nil {
 			return nil, err
 		}
-		list = append(list, &e)
+		list = append(list, &t)
 	}
-	return list, nil
+	return list, rows.Err()
 }
 
-// MarkAttendance records a user's presence at an event
-func (r *Repository) MarkAttendance(ctx context.Context, eventID, userID uuid.UUID) error {
+// UpdateTemplate saves a template's editable fields and schedule
+func (r *Repository) UpdateTemplate(ctx context.Context, t *models.TaskTemplate) error {
 	query := `
-		INSERT INTO event_attendance (event_id, user_id, attended_at)
-		VALUES ($1, $2, NOW())
-		ON CONFLICT (event_id, user_id) DO NOTHING
+		UPDATE task_templates
+		SET title = $2, description = $3, priority = $4, requires_completion_note = $5, requires_proof = $6,
+		    level_id = $7, assign_to = $8, rrule = $9, starts_at = $10, lead_days = $11, is_paused = $12,
+		    next_due_at = $13, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING updated_at
 	`
-	_, err := r.db.Exec(ctx, query, eventID, userID)
+	err := r.db.QueryRow(ctx, query,
+		t.ID, t.Title, t.Description, t.Priority, t.RequiresCompletionNote, t.RequiresProof,
+		t.LevelID, t.AssignTo, t.RRule, t.StartsAt, t.LeadDays, t.IsPaused, t.NextDueAt,
+	).Scan(&t.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrTemplateNotFound
+	}
 	return err
 }
+
+// DeleteTemplate soft-deletes a template; tasks it generated are kept
+func (r *Repository) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `UPDATE task_templates SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// ListDueTemplates returns active templates whose next occurrence is within its lead time of now
+func (r *Repository) ListDueTemplates(ctx context.Context, now time.Time) ([]*models.TaskTemplate, error) {
+	query := `SELECT ` + templateColumns + templateFrom + `
+		WHERE t.deleted_at IS NULL AND t.is_paused = FALSE AND t.next_due_at IS NOT NULL
+		  AND t.next_due_at <= $1::timestamp + make_interval(days => t.lead_days)
+		ORDER BY t.next_due_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.TaskTemplate
+	for rows.Next() {
+		var t models.TaskTemplate
+		if err := scanTemplate(rows, &t); err != nil {
+			return nil, err
+		}
+		list = append(list, &t)
+	}
+	return list, rows.Err()
+}
+
+// AdvanceTemplate moves a template past a generated occurrence. It returns false if the
+// template was edited, paused or advanced by another run in the meantime.
+func (r *Repository) AdvanceTemplate(ctx context.Context, id uuid.UUID, from time.Time, next *time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE task_templates SET next_due_at = $3, last_generated_at = NOW()
+		WHERE id = $1 AND next_due_at = $2 AND is_paused = FALSE AND deleted_at IS NULL
+	`, id, from, next)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListTemplateTargets returns the jurisdictions at a level under a root, each with its active
+// main committee and that committee's head (the lowest-ranked lead position held)
+func (r *Repository) ListTemplateTargets(ctx context.Context, rootID uuid.UUID, levelID int) ([]*models.TemplateTarget, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT j.id FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+			WHERE j.deleted_at IS NULL
+		)
+		SELECT j.id, j.name, c.id, l.user_id, COALESCE(l.full_name, '')
+		FROM subtree s
+		JOIN jurisdictions j ON j.id = s.id
+		LEFT JOIN committees c ON c.jurisdiction_id = j.id AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+		LEFT JOIN LATERAL (
+			SELECT cm.user_id, u.full_name
+			FROM committee_members cm
+			JOIN positions p ON cm.position_id = p.id
+			JOIN users u ON cm.user_id = u.id
+			WHERE cm.committee_id = c.id AND cm.ended_at IS NULL AND cm.is_active = TRUE AND p.rank <= $3
+			ORDER BY p.rank ASC, cm.joined_at ASC
+			LIMIT 1
+		) l ON TRUE
+		WHERE j.level_id = $2
+		ORDER BY j.name ASC
+	`
+	rows, err := r.db.Query(ctx, query, rootID, levelID, auth.LeaderRank)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.TemplateTarget
+	for rows.Next() {
+		var t models.TemplateTarget
+		if err := rows.Scan(&t.JurisdictionID, &t.Name, &t.CommitteeID, &t.LeaderID, &t.LeaderName); err != nil {
+			return nil, err
+		}
+		list = append(list, &t)
+	}
+	return list, rows.Err()
+}
+
+// ESCALATION
+
+// ListOpenTasksDueBefore returns open tasks with a due date before the cutoff that have not yet
+// reached the last escalation level
+func (r *Repository) ListOpenTasksDueBefore(ctx context.Context, cutoff time.Time) ([]*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + ` FROM tasks
+		WHERE status IN ('pending', 'in_progress') AND due_date IS NOT NULL AND due_date < $1
+		  AND deleted_at IS NULL AND escalation_level < $2
+		ORDER BY due_date ASC
+	`
+	rows, err := r.db.Query(ctx, query, cutoff, models.TaskEscalationParent)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Task
+	for rows.Next() {
+		var t models.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
+		}
+		list = append(list, &t)
+	}
+	return list, rows.Err()
+}
+
+// MarkTaskReminded records that the due-date reminder went out. It returns false if another
+// run already sent it.
+func (r *Repository) MarkTaskReminded(ctx context.Context, id uuid.UUID, note string) (bool, error) {
+	return r.markTask(ctx, id, `
+		UPDATE tasks SET reminded_at = NOW()
+		WHERE id = $1 AND reminded_at IS NULL
+	`, models.TaskActionReminder, note)
+}
+
+// MarkTaskOverdue flags a task as overdue. It returns false if it was already flagged.
+func (r *Repository) MarkTaskOverdue(ctx context.Context, id uuid.UUID, note string) (bool, error) {
+	return r.markTask(ctx, id, `
+		UPDATE tasks SET overdue_at = NOW()
+		WHERE id = $1 AND overdue_at IS NULL
+	`, models.TaskActionOverdue, note)
+}
+
+// EscalateTask raises a task from one escalation level to the next. It returns false if
+// another run already did.
+func (r *Repository) EscalateTask(ctx context.Context, id uuid.UUID, from, to int, note string) (bool, error) {
+	return r.markTask(ctx, id, fmt.Sprintf(`
+		UPDATE tasks SET escalation_level = %d, escalated_at = NOW()
+		WHERE id = $1 AND escalation_level = %d
+	`, to, from), models.TaskActionEscalated, note)
+}
+
+// markTask runs a guarded update and logs it as a system entry in the same transaction
+func (r *Repository) markTask(ctx context.Context, id uuid.UUID, update, action, note string) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, update, id)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	logQuery := `
+		INSERT INTO task_logs (task_id, action, note)
+		VALUES ($1, $2, NULLIF($3, ''))
+	`
+	if _, err := tx.Exec(ctx, logQuery, id, action, note); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// ListTaskWorkers returns the users expected to work on a task: the assignee, the members of a
+// committee task who have not finished their part, or the committee's current members
+func (r *Repository) ListTaskWorkers(ctx context.Context, t *models.Task) ([]uuid.UUID, error) {
+	if t.AssigneeID != nil {
+		return []uuid.UUID{*t.AssigneeID}, nil
+	}
+	if t.CommitteeID == nil {
+		return nil, nil
+	}
+	query := `
+		SELECT user_id FROM task_assignments WHERE task_id = $1 AND status <> 'completed'
+		UNION
+		SELECT user_id FROM committee_members
+		WHERE committee_id = $2 AND ended_at IS NULL AND is_active = TRUE
+		  AND NOT EXISTS (SELECT 1 FROM task_assignments WHERE task_id = $1)
+	`
+	return r.queryUserIDs(ctx, query, t.ID, *t.CommitteeID)
+}
+
+// FindAssigneeCommittee returns the committee a task escalates to first: the committee it was
+// assigned to, else the active committee in the task's jurisdiction that the assignee sits on
+// (the main committee before sub-committees), else the jurisdiction's main committee
+func (r *Repository) FindAssigneeCommittee(ctx context.Context, t *models.Task) (*uuid.UUID, error) {
+	if t.CommitteeID != nil {
+		return t.CommitteeID, nil
+	}
+	query := `
+		SELECT c.id FROM committees c
+		WHERE c.jurisdiction_id = $1 AND c.status = 'active' AND c.deleted_at IS NULL
+		  AND (c.parent_committee_id IS NULL OR EXISTS (
+			SELECT 1 FROM committee_members cm
+			WHERE cm.committee_id = c.id AND cm.user_id = $2 AND cm.ended_at IS NULL AND cm.is_active = TRUE))
+		ORDER BY EXISTS (
+			SELECT 1 FROM committee_members cm
+			WHERE cm.committee_id = c.id AND cm.user_id = $2 AND cm.ended_at IS NULL AND cm.is_active = TRUE) DESC,
+			(c.parent_committee_id IS NULL) DESC
+		LIMIT 1
+	`
+	var assignee uuid.UUID
+	if t.AssigneeID != nil {
+		assignee = *t.AssigneeID
+	}
+	var id uuid.UUID
+	err := r.db.QueryRow(ctx, query, t.JurisdictionID, assignee).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// FindParentCommittee returns the active main committee of a jurisdiction's parent and the
+// parent's name. The committee is nil when the parent has none, and both are empty at the top.
+func (r *Repository) FindParentCommittee(ctx context.Context, jurisdictionID uuid.UUID) (*uuid.UUID, string, error) {
+	query := `
+		SELECT p.name, c.id
+		FROM jurisdictions j
+		JOIN jurisdictions p ON p.id = j.parent_id
+		LEFT JOIN committees c ON c.jurisdiction_id = p.id AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+		WHERE j.id = $1
+	`
+	var name string
+	var id *uuid.UUID
+	err := r.db.QueryRow(ctx, query, jurisdictionID).Scan(&name, &id)
+	if err == pgx.ErrNoRows {
+		return nil, "", nil
+	}
+	return id, name, err
+}
+
+// ListCommitteeLeaders returns the current holders of a committee's lead positions
+func (r *Repository) ListCommitteeLeaders(ctx context.Context, committeeID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT cm.user_id
+		FROM committee_members cm
+		JOIN positions p ON cm.position_id = p.id
+		WHERE cm.committee_id = $1 AND cm.ended_at IS NULL AND cm.is_active = TRUE AND p.rank <= $2
+	`
+	return r.queryUserIDs(ctx, query, committeeID, auth.LeaderRank)
+}
+
+func (r *Repository) queryUserIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListOverdueCounts walks a jurisdiction subtree and counts open and overdue tasks per node
+func (r *Repository) ListOverdueCounts(ctx context.Context, rootID uuid.UUID) ([]*models.OverdueCount, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT j.id, s.depth + 1 FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+			WHERE j.deleted_at IS NULL
+		)
+		SELECT j.id, j.parent_id, j.name, j.name_bn, jl.name, s.depth,
+		       COUNT(t.id),
+		       COUNT(t.id) FILTER (WHERE t.due_date < NOW()),
+		       COUNT(t.id) FILTER (WHERE t.due_date < NOW() AND t.escalation_level >= $2),
+		       COUNT(t.id) FILTER (WHERE t.due_date < NOW() AND t.escalation_level >= $3)
+		FROM subtree s
+		JOIN jurisdictions j ON j.id = s.id
+		JOIN jurisdiction_levels jl ON j.level_id = jl.id
+		LEFT JOIN tasks t ON t.jurisdiction_id = j.id AND t.status IN ('pending', 'in_progress') AND t.deleted_at IS NULL
+		GROUP BY j.id, j.parent_id, j.name, j.name_bn, jl.name, jl.rank, s.depth
+		ORDER BY s.depth ASC, jl.rank ASC, j.name ASC
+	`
+	rows, err := r.db.Query(ctx, query, rootID, models.TaskEscalationCommittee, models.TaskEscalationParent)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.OverdueCount
+	for rows.Next() {
+		var c models.OverdueCount
+		err := rows.Scan(
+			&c.JurisdictionID, &c.ParentID, &c.Name, &c.NameBn, &c.Level, &c.Depth,
+			&c.OpenTasks, &c.Overdue, &c.Escalated, &c.EscalatedUp,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &c)
+	}
+	return list, rows.Err()
+}
+
+// EVENTS
+
+// CreateEvent inserts a new event
+func (r *Repository) CreateEvent(ctx context.Context, e *models.Event) error {
+	query := `
+		INSERT INTO events (
+			jurisdiction_id, organizer_id, title, description, location, start_time, end_time, is_public, capacity, rsvp_deadline,
+			latitude, longitude, geofence_radius_m, enforce_time_window
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRow(ctx, query,
+		e.JurisdictionID, e.OrganizerID, e.Title, e.Description, e.Location, e.StartTime, e.EndTime, e.IsPublic, e.Capacity, e.RSVPDeadline,
+		e.Latitude, e.Longitude, e.GeofenceRadius, e.EnforceTimeWindow,
+	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+}
+
+const eventColumns = `
+	e.id, e.jurisdiction_id, e.organizer_id, e.title, e.description, e.location, e.start_time, e.end_time,
+	e.is_public, e.capacity, e.rsvp_deadline, e.latitude, e.longitude, e.geofence_radius_m, e.enforce_time_window,
+	e.created_at, e.updated_at,
+	(SELECT COUNT(*) FROM event_invitations i WHERE i.event_id = e.id AND i.rsvp = 'yes'),
+	(SELECT COUNT(*) FROM event_invitations i WHERE i.event_id = e.id AND i.rsvp = 'waitlisted')
+`
+
+func scanEvent(row pgx.Row, e *models.Event) error {
+	return row.Scan(
+		&e.ID, &e.JurisdictionID, &e.OrganizerID, &e.Title, &e.Description, &e.Location, &e.StartTime, &e.EndTime,
+		&e.IsPublic, &e.Capacity, &e.RSVPDeadline, &e.Latitude, &e.Longitude, &e.GeofenceRadius, &e.EnforceTimeWindow,
+		&e.CreatedAt, &e.UpdatedAt, &e.Going, &e.Waitlisted,
+	)
+}
+
+// GetEvent returns an event with its seat counts
+func (r *Repository) GetEvent(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events e WHERE e.id = $1 AND e.deleted_at IS NULL`
+	var e models.Event
+	err := scanEvent(r.db.QueryRow(ctx, query, id), &e)
+	if err == pgx.ErrNoRows {
+		return nil, ErrEventNotFound
+	}
+	return &e, err
+}
+
+// ListEvents returns events for a jurisdiction
+func (r *Repository) ListEvents(ctx context.Context, jurisdictionID *uuid.UUID) ([]*models.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events e WHERE e.deleted_at IS NULL`
+	args := []interface{}{}
+	if jurisdictionID != nil {
+		args = append(args, *jurisdictionID)
+		query += " AND e.jurisdiction_id = $1"
+	}
+	query += " ORDER BY e.start_time DESC"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Event
+	for rows.Next() {
+		var e models.Event
+		if err := scanEvent(rows, &e); err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	return list, nil
+}
+
+// UpdateEventCheckIn stores an event's venue location and check-in rules
+func (r *Repository) UpdateEventCheckIn(ctx context.Context, e *models.Event) error {
+	query := `
+		UPDATE events
+		SET latitude = $2, longitude = $3, geofence_radius_m = $4, enforce_time_window = $5, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	tag, err := r.db.Exec(ctx, query, e.ID, e.Latitude, e.Longitude, e.GeofenceRadius, e.EnforceTimeWindow)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrEventNotFound
+	}
+	return nil
+}
+
+// RecordAttendance stores a check-in and reports whether it is new. A member is counted once per
+// event; later check-ins are ignored.
+func (r *Repository) RecordAttendance(ctx context.Context, a *models.EventAttendance) (bool, error) {
+	query := `
+		INSERT INTO event_attendance (event_id, user_id, attended_at, method, recorded_by, latitude, longitude, distance_m, synced_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (event_id, user_id) DO NOTHING
+		RETURNING id
+	`
+	err := r.db.QueryRow(ctx, query,
+		a.EventID, a.UserID, a.AttendedAt, a.Method, a.RecordedBy, a.Latitude, a.Longitude, a.DistanceM, a.SyncedAt,
+	).Scan(&a.ID)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListActiveUserIDs returns the given users that exist and are active
+func (r *Repository) ListActiveUserIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT id FROM users WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL AND is_active = TRUE`
+	return r.queryUserIDs(ctx, query, ids)
+}
+
+// RSVPS
+
+// InviteToEvent invites the members matching an audience and returns those not invited before
+func (r *Repository) InviteToEvent(ctx context.Context, eventID, invitedBy uuid.UUID, aud *models.EventAudience) ([]uuid.UUID, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM jurisdictions WHERE id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT j.id FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+			WHERE $3 AND j.deleted_at IS NULL
+		),
+		audience AS (
+			SELECT cm.user_id FROM committee_members cm
+			JOIN committees c ON cm.committee_id = c.id
+			WHERE cm.ended_at IS NULL AND cm.is_active = TRUE
+			  AND c.status = 'active' AND c.deleted_at IS NULL
+			  AND ($2::uuid IS NOT NULL OR $4::uuid IS NOT NULL OR $5::int IS NOT NULL)
+			  AND ($2::uuid IS NULL OR c.jurisdiction_id IN (SELECT id FROM subtree))
+			  AND ($4::uuid IS NULL OR c.id = $4)
+			  AND ($5::int IS NULL OR cm.position_id = $5)
+			UNION
+			SELECT id FROM users WHERE id = ANY($6::uuid[]) AND deleted_at IS NULL AND is_active = TRUE
+		)
+		INSERT INTO event_invitations (event_id, user_id, invited_by, invited_at)
+		SELECT $1, user_id, $7, NOW() FROM audience
+		ON CONFLICT (event_id, user_id) DO NOTHING
+		RETURNING user_id
+	`
+	userIDs := aud.UserIDs
+	if userIDs == nil {
+		userIDs = []uuid.UUID{}
+	}
+	return r.queryUserIDs(ctx, query, eventID, aud.JurisdictionID, aud.IncludeSubtree, aud.CommitteeID, aud.PositionID, userIDs, invitedBy)
+}
+
+// RespondToEvent records a member's answer. A yes takes a seat while there is one and joins the
+// waitlist otherwise; a confirmed member who backs out frees their seat for the waitlist.
+// Uninvited members may only answer when allowUninvited is set (public events).
+// Returns the stored answer and the members promoted from the waitlist.
+func (r *Repository) RespondToEvent(ctx context.Context, eventID, userID uuid.UUID, answer string, allowUninvited bool) (string, []uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the event so seats are handed out one answer at a time
+	var capacity *int
+	err = tx.QueryRow(ctx, "SELECT capacity FROM events WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", eventID).Scan(&capacity)
+	if err == pgx.ErrNoRows {
+		return "", nil, ErrEventNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// 2. Current answer
+	var current string
+	err = tx.QueryRow(ctx, "SELECT rsvp FROM event_invitations WHERE event_id = $1 AND user_id = $2", eventID, userID).Scan(&current)
+	if err == pgx.ErrNoRows {
+		if !allowUninvited {
+			return "", nil, ErrNotInvited
+		}
+	} else if err != nil {
+		return "", nil, err
+	}
+
+	// 3. A yes keeps its seat or queue place; a new one gets a seat if any is left
+	next := answer
+	if answer == models.RSVPYes {
+		switch {
+		case current == models.RSVPYes || current == models.RSVPWaitlisted:
+			next = current
+		case capacity != nil:
+			var going int
+			if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM event_invitations WHERE event_id = $1 AND rsvp = 'yes'", eventID).Scan(&going); err != nil {
+				return "", nil, err
+			}
+			if going >= *capacity {
+				next = models.RSVPWaitlisted
+			}
+		}
+	}
+
+	upsert := `
+		INSERT INTO event_invitations (event_id, user_id, rsvp, responded_at, waitlisted_at)
+		VALUES ($1, $2, $3, NOW(), CASE WHEN $3 = 'waitlisted' THEN NOW() END)
+		ON CONFLICT (event_id, user_id) DO UPDATE
+		SET rsvp = EXCLUDED.rsvp,
+		    responded_at = NOW(),
+		    updated_at = NOW(),
+		    waitlisted_at = CASE WHEN EXCLUDED.rsvp = 'waitlisted'
+		                         THEN COALESCE(event_invitations.waitlisted_at, NOW()) END
+	`
+	if _, err := tx.Exec(ctx, upsert, eventID, userID, next); err != nil {
+		return "", nil, err
+	}
+
+	// 4. A freed seat goes to the head of the waitlist
+	var promoted []uuid.UUID
+	if current == models.RSVPYes && next != models.RSVPYes {
+		if promoted, err = promoteWaitlist(ctx, tx, eventID); err != nil {
+			return "", nil, err
+		}
+	}
+	return next, promoted, tx.Commit(ctx)
+}
+
+// SetEventCapacity changes an event's capacity and fills any new seats from the waitlist.
+// Lowering it below the confirmed count keeps existing confirmations.
+func (r *Repository) SetEventCapacity(ctx context.Context, eventID uuid.UUID, capacity *int) ([]uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE events SET capacity = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	tag, err := tx.Exec(ctx, query, eventID, capacity)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrEventNotFound
+	}
+
+	promoted, err := promoteWaitlist(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return promoted, tx.Commit(ctx)
+}
+
+// promoteWaitlist confirms waitlisted members in queue order while seats are free.
+// The caller must hold the event row lock.
+func promoteWaitlist(ctx context.Context, tx pgx.Tx, eventID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		UPDATE event_invitations
+		SET rsvp = 'yes', waitlisted_at = NULL, updated_at = NOW()
+		WHERE id IN (
+			SELECT i.id FROM event_invitations i
+			WHERE i.event_id = $1 AND i.rsvp = 'waitlisted'
+			ORDER BY i.waitlisted_at ASC
+			LIMIT (
+				-- NULL (no limit) when the event has no capacity
+				SELECT CASE WHEN e.capacity IS NULL THEN NULL ELSE GREATEST(e.capacity - (
+					SELECT COUNT(*) FROM event_invitations y WHERE y.event_id = $1 AND y.rsvp = 'yes'
+				), 0) END
+				FROM events e WHERE e.id = $1
+			)
+		)
+		RETURNING user_id
+	`
+	rows, err := tx.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetEventInvitation returns one member's invitation
+func (r *Repository) GetEventInvitation(ctx context.Context, eventID, userID uuid.UUID) (*models.EventInvitation, error) {
+	list, err := r.listEventInvitations(ctx, eventID, "", &userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrInvitationNotFound
+	}
+	return list[0], nil
+}
+
+// ListEventInvitations returns an event's invitations, optionally with one answer
+func (r *Repository) ListEventInvitations(ctx context.Context, eventID uuid.UUID, rsvp string) ([]*models.EventInvitation, error) {
+	return r.listEventInvitations(ctx, eventID, rsvp, nil)
+}
+
+func (r *Repository) listEventInvitations(ctx context.Context, eventID uuid.UUID, rsvp string, userID *uuid.UUID) ([]*models.EventInvitation, error) {
+	query := `
+		SELECT * FROM (
+			SELECT i.id, i.event_id, i.user_id, i.rsvp, i.invited_by, i.invited_at, i.responded_at,
+			       i.waitlisted_at, i.reminded_at, u.full_name,
+			       CASE WHEN i.rsvp = 'waitlisted'
+			            THEN ROW_NUMBER() OVER (PARTITION BY i.rsvp = 'waitlisted' ORDER BY i.waitlisted_at ASC)
+			            ELSE 0 END AS waitlist_position,
+			       EXISTS (SELECT 1 FROM event_attendance a WHERE a.event_id = i.event_id AND a.user_id = i.user_id) AS attended
+			FROM event_invitations i
+			JOIN users u ON i.user_id = u.id
+			WHERE i.event_id = $1
+		) inv
+		WHERE ($2 = '' OR inv.rsvp = $2) AND ($3::uuid IS NULL OR inv.user_id = $3)
+		ORDER BY inv.waitlist_position ASC, inv.full_name ASC
+	`
+	rows, err := r.db.Query(ctx, query, eventID, rsvp, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.EventInvitation
+	for rows.Next() {
+		var i models.EventInvitation
+		err := rows.Scan(
+			&i.ID, &i.EventID, &i.UserID, &i.RSVP, &i.InvitedBy, &i.InvitedAt, &i.RespondedAt,
+			&i.WaitlistedAt, &i.RemindedAt, &i.UserName, &i.WaitlistPosition, &i.Attended,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &i)
+	}
+	return list, rows.Err()
+}
+
+// GetEventTurnout counts answers and checks them against recorded attendance
+func (r *Repository) GetEventTurnout(ctx context.Context, eventID uuid.UUID) (*models.EventTurnout, error) {
+	query := `
+		SELECT
+			COUNT(i.id) FILTER (WHERE i.invited_at IS NOT NULL),
+			COUNT(i.id) FILTER (WHERE i.rsvp = 'pending'),
+			COUNT(i.id) FILTER (WHERE i.rsvp = 'yes'),
+			COUNT(i.id) FILTER (WHERE i.rsvp = 'maybe'),
+			COUNT(i.id) FILTER (WHERE i.rsvp = 'no'),
+			COUNT(i.id) FILTER (WHERE i.rsvp = 'waitlisted'),
+			(SELECT COUNT(*) FROM event_attendance a WHERE a.event_id = $1),
+			COUNT(i.id) FILTER (WHERE i.rsvp = 'yes' AND a.id IS NOT NULL),
+			COUNT(i.id) FILTER (WHERE i.rsvp = 'maybe' AND a.id IS NOT NULL)
+		FROM event_invitations i
+		LEFT JOIN event_attendance a ON a.event_id = i.event_id AND a.user_id = i.user_id
+		WHERE i.event_id = $1
+	`
+	t := &models.EventTurnout{EventID: eventID}
+	err := r.db.QueryRow(ctx, query, eventID).Scan(
+		&t.Invited, &t.Pending, &t.Yes, &t.Maybe, &t.No, &t.Waitlisted,
+		&t.Attended, &t.AttendedYes, &t.AttendedMaybe,
+	)
+	return t, err
+}
+
+// RSVPReminder is a pending invitation whose RSVP deadline is near
+type RSVPReminder struct {
+	UserID         uuid.UUID
+	EventID        uuid.UUID
+	JurisdictionID uuid.UUID
+	Title          string
+	Deadline       time.Time
+}
+
+// ClaimRSVPReminders marks unanswered invitations whose deadline falls before the given time as
+// reminded and returns them. Each invitation is reminded once, even with several instances running.
+func (r *Repository) ClaimRSVPReminders(ctx context.Context, now, before time.Time) ([]*RSVPReminder, error) {
+	query := `
+		UPDATE event_invitations i
+		SET reminded_at = NOW(), updated_at = NOW()
+		FROM events e
+		WHERE i.event_id = e.id AND e.deleted_at IS NULL
+		  AND i.rsvp = 'pending' AND i.reminded_at IS NULL
+		  AND COALESCE(e.rsvp_deadline, e.start_time) > $1
+		  AND COALESCE(e.rsvp_deadline, e.start_time) <= $2
+		RETURNING i.user_id, e.id, e.jurisdiction_id, e.title, COALESCE(e.rsvp_deadline, e.start_time)
+	`
+	rows, err := r.db.Query(ctx, query, now, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*RSVPReminder
+	for rows.Next() {
+		var rm RSVPReminder
+		if err := rows.Scan(&rm.UserID, &rm.EventID, &rm.JurisdictionID, &rm.Title, &rm.Deadline); err != nil {
+			return nil, err
+		}
+		list = append(list, &rm)
+	}
+	return list, rows.Err()
+}
+
+// SCORES
+
+// ListScoreWeights returns the scoring model
+func (r *Repository) ListScoreWeights(ctx context.Context) ([]*models.ScoreWeight, error) {
+	query := `
+		SELECT category, points, proof_bonus, max_proof_bonus, updated_by, updated_at
+		FROM activity_score_weights
+		ORDER BY points DESC, category ASC
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ScoreWeight
+	for rows.Next() {
+		var w models.ScoreWeight
+		if err := rows.Scan(&w.Category, &w.Points, &w.ProofBonus, &w.MaxProofBonus, &w.UpdatedBy, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, &w)
+	}
+	return list, rows.Err()
+}
+
+// UpsertScoreWeight sets the weights of a category
+func (r *Repository) UpsertScoreWeight(ctx context.Context, w *models.ScoreWeight) error {
+	query := `
+		INSERT INTO activity_score_weights (category, points, proof_bonus, max_proof_bonus, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (category) DO UPDATE
+		SET points = EXCLUDED.points, proof_bonus = EXCLUDED.proof_bonus, max_proof_bonus = EXCLUDED.max_proof_bonus,
+		    updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at
+	`
+	return r.db.QueryRow(ctx, query, w.Category, w.Points, w.ProofBonus, w.MaxProofBonus, w.UpdatedBy).Scan(&w.UpdatedAt)
+}
+
+// scoredActivities scores each verified activity of the month starting at $1: the category's
+// points plus the proof bonus, capped. Proofs rejected by the media pipeline do not count.
+const scoredActivities = `
+	scored AS (
+		SELECT a.id, a.user_id, a.jurisdiction_id, p.n AS proofs,
+		       w.points + LEAST(p.n * w.proof_bonus, w.max_proof_bonus) AS score
+		FROM activities a
+		JOIN activity_score_weights w ON w.category = a.category
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS n FROM activity_proofs ap
+			WHERE ap.activity_id = a.id AND ap.processing_status NOT IN ('rejected', 'failed')
+		) p
+		WHERE a.review_status = 'verified' AND a.deleted_at IS NULL
+		  AND a.activity_date >= $1::date AND a.activity_date < $1::date + INTERVAL '1 month'
+	)
+`
+
+// RefreshMonthlyScores recomputes the member and jurisdiction scores of a month
+func (r *Repository) RefreshMonthlyScores(ctx context.Context, month time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Replace the month's rows
+	if _, err := tx.Exec(ctx, "DELETE FROM member_monthly_scores WHERE month = $1::date", month); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM jurisdiction_monthly_scores WHERE month = $1::date", month); err != nil {
+		return err
+	}
+
+	// 2. Members
+	memberQuery := `
+		WITH ` + scoredActivities + `
+		INSERT INTO member_monthly_scores (month, user_id, jurisdiction_id, activity_count, proof_count, score)
+		SELECT $1::date, s.user_id, u.jurisdiction_id, COUNT(*), SUM(s.proofs), SUM(s.score)
+		FROM scored s
+		JOIN users u ON s.user_id = u.id
+		GROUP BY s.user_id, u.jurisdiction_id
+	`
+	if _, err := tx.Exec(ctx, memberQuery, month); err != nil {
+		return err
+	}
+
+	// 3. Jurisdictions, rolling each activity up to every jurisdiction above it
+	jurisdictionQuery := `
+		WITH RECURSIVE ` + scoredActivities + `,
+		ancestry AS (
+			SELECT j.id AS jurisdiction_id, j.id AS ancestor_id, j.parent_id
+			FROM jurisdictions j
+			WHERE j.id IN (SELECT DISTINCT jurisdiction_id FROM scored)
+			UNION ALL
+			SELECT an.jurisdiction_id, p.id, p.parent_id
+			FROM ancestry an
+			JOIN jurisdictions p ON p.id = an.parent_id
+		)
+		INSERT INTO jurisdiction_monthly_scores (month, jurisdiction_id, own_score, total_score, activity_count, member_count)
+		SELECT $1::date, an.ancestor_id,
+		       COALESCE(SUM(s.score) FILTER (WHERE s.jurisdiction_id = an.ancestor_id), 0),
+		       SUM(s.score), COUNT(*), COUNT(DISTINCT s.user_id)
+		FROM scored s
+		JOIN ancestry an ON an.jurisdiction_id = s.jurisdiction_id
+		GROUP BY an.ancestor_id
+	`
+	if _, err := tx.Exec(ctx, jurisdictionQuery, month); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetMemberLeaderboard ranks members for a month, optionally only those in a jurisdiction's subtree
+func (r *Repository) GetMemberLeaderboard(ctx context.Context, month time.Time, jurisdictionID *uuid.UUID, limit int) ([]*models.MemberScore, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM jurisdictions WHERE id = $2
+			UNION ALL
+			SELECT j.id FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+		)
+		SELECT RANK() OVER (ORDER BY ms.score DESC)::int, ms.user_id, u.full_name, ms.jurisdiction_id, COALESCE(j.name, ''),
+		       ms.activity_count, ms.proof_count, ms.score
+		FROM member_monthly_scores ms
+		JOIN users u ON ms.user_id = u.id
+		LEFT JOIN jurisdictions j ON ms.jurisdiction_id = j.id
+		WHERE ms.month = $1::date AND u.deleted_at IS NULL
+		  AND ($2::uuid IS NULL OR ms.jurisdiction_id IN (SELECT id FROM subtree))
+		ORDER BY ms.score DESC, u.full_name ASC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(ctx, query, month, jurisdictionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.MemberScore
+	for rows.Next() {
+		var m models.MemberScore
+		err := rows.Scan(&m.Rank, &m.UserID, &m.UserName, &m.JurisdictionID, &m.JurisdictionName, &m.ActivityCount, &m.ProofCount, &m.Score)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &m)
+	}
+	return list, rows.Err()
+}
+
+// GetJurisdictionLeaderboard ranks jurisdictions for a month by total score. Either the direct
+// children of parentID are compared, or all jurisdictions of a level.
+func (r *Repository) GetJurisdictionLeaderboard(ctx context.Context, month time.Time, parentID *uuid.UUID, level string, limit int) ([]*models.JurisdictionScore, error) {
+	query := `
+		SELECT RANK() OVER (ORDER BY COALESCE(js.total_score, 0) DESC)::int, j.id, j.name, jl.name,
+		       COALESCE(js.own_score, 0), COALESCE(js.total_score, 0),
+		       COALESCE(js.activity_count, 0), COALESCE(js.member_count, 0)
+		FROM jurisdictions j
+		JOIN jurisdiction_levels jl ON j.level_id = jl.id
+		LEFT JOIN jurisdiction_monthly_scores js ON js.jurisdiction_id = j.id AND js.month = $1::date
+		WHERE j.deleted_at IS NULL
+		  AND ($2::uuid IS NULL OR j.parent_id = $2)
+		  AND ($3 = '' OR jl.name ILIKE $3)
+		ORDER BY COALESCE(js.total_score, 0) DESC, j.name ASC
+		LIMIT $4
+	`
+	rows, err := r.db.Query(ctx, query, month, parentID, level, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.JurisdictionScore
+	for rows.Next() {
+		var js models.JurisdictionScore
+		err := rows.Scan(&js.Rank, &js.JurisdictionID, &js.JurisdictionName, &js.Level,
+			&js.OwnScore, &js.TotalScore, &js.ActivityCount, &js.MemberCount)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &js)
+	}
+	return list, rows.Err()
+}
+
+// GetScoresComputedAt returns when a month's scores were last computed, nil if never
+func (r *Repository) GetScoresComputedAt(ctx context.Context, month time.Time) (*time.Time, error) {
+	var at *time.Time
+	query := `
+		SELECT MAX(computed_at) FROM (
+			SELECT computed_at FROM member_monthly_scores WHERE month = $1::date
+			UNION ALL
+			SELECT computed_at FROM jurisdiction_monthly_scores WHERE month = $1::date
+		) c
+	`
+	err := r.db.QueryRow(ctx, query, month).Scan(&at)
+	return at, err
+}
+
+// EDITING
+
+// softDeleteTables are the records that support audited soft delete and restore
+var softDeleteTables = map[string]string{
+	"activity": "activities",
+	"task":     "tasks",
+	"event":    "events",
+}
+
+// softDeleteNotFound is the error for each record type when there is no record to delete or restore
+var softDeleteNotFound = map[string]error{
+	"activity": ErrActivityNotFound,
+	"task":     ErrTaskNotFound,
+	"event":    ErrEventNotFound,
+}
+
+// UpdateActivity stores an edited activity with an audit entry. A resubmitted activity goes back
+// to the review queue. Fails if the activity changed since it was loaded.
+func (r *Repository) UpdateActivity(ctx context.Context, old, a *models.Activity, userID uuid.UUID, resubmit bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE activities
+		SET title = $3, description = $4, category = $5, activity_date = $6,
+		    review_status = CASE WHEN $7 THEN 'submitted' ELSE review_status END,
+		    updated_at = NOW()
+		WHERE id = $1 AND updated_at = $2 AND deleted_at IS NULL
+		RETURNING updated_at, review_status
+	`
+	err = tx.QueryRow(ctx, query, a.ID, old.UpdatedAt, a.Title, a.Description, a.Category, a.ActivityDate, resubmit).
+		Scan(&a.UpdatedAt, &a.ReviewStatus)
+	if err == pgx.ErrNoRows {
+		return ErrActivityChanged
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertAudit(ctx, tx, userID, "update", "activity", a.ID, old, a); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateTask stores an edited task with a history and audit entry. Moving the due date restarts
+// the reminder and escalation cycle. Fails if the task changed since it was loaded.
+func (r *Repository) UpdateTask(ctx context.Context, old, t *models.Task, userID uuid.UUID, note string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Update; the CASEs read the stored due date
+	query := `
+		UPDATE tasks
+		SET title = $3, description = $4, priority = $5, due_date = $6,
+		    requires_completion_note = $7, requires_proof = $8,
+		    reminded_at = CASE WHEN due_date IS DISTINCT FROM $6 THEN NULL ELSE reminded_at END,
+		    overdue_at = CASE WHEN due_date IS DISTINCT FROM $6 THEN NULL ELSE overdue_at END,
+		    escalation_level = CASE WHEN due_date IS DISTINCT FROM $6 THEN 0 ELSE escalation_level END,
+		    escalated_at = CASE WHEN due_date IS DISTINCT FROM $6 THEN NULL ELSE escalated_at END,
+		    updated_at = NOW()
+		WHERE id = $1 AND updated_at = $2 AND deleted_at IS NULL
+		RETURNING updated_at, reminded_at, overdue_at, escalation_level, escalated_at
+	`
+	err = tx.QueryRow(ctx, query,
+		t.ID, old.UpdatedAt, t.Title, t.Description, t.Priority, t.DueDate, t.RequiresCompletionNote, t.RequiresProof,
+	).Scan(&t.UpdatedAt, &t.RemindedAt, &t.OverdueAt, &t.EscalationLevel, &t.EscalatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrTaskChanged
+	}
+	if err != nil {
+		return err
+	}
+
+	// 2. History and audit
+	logQuery := `
+		INSERT INTO task_logs (task_id, user_id, action, note)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+	`
+	if _, err := tx.Exec(ctx, logQuery, t.ID, userID, models.TaskActionUpdated, note); err != nil {
+		return err
+	}
+	if err := insertAudit(ctx, tx, userID, "update", "task", t.ID, old, t); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateEvent stores an edited event with an audit entry. When the RSVP deadline moves, pending
+// invitees are reminded again. Fails if the event changed since it was loaded.
+func (r *Repository) UpdateEvent(ctx context.Context, old, e *models.Event, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE events
+		SET title = $3, description = $4, location = $5, start_time = $6, end_time = $7,
+		    is_public = $8, rsvp_deadline = $9, updated_at = NOW()
+		WHERE id = $1 AND updated_at = $2 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+	err = tx.QueryRow(ctx, query,
+		e.ID, old.UpdatedAt, e.Title, e.Description, e.Location, e.StartTime, e.EndTime, e.IsPublic, e.RSVPDeadline,
+	).Scan(&e.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrEventChanged
+	}
+	if err != nil {
+		return err
+	}
+
+	if !e.StartTime.Equal(old.StartTime) || !sameTime(e.RSVPDeadline, old.RSVPDeadline) {
+		resetQuery := `UPDATE event_invitations SET reminded_at = NULL, updated_at = NOW() WHERE event_id = $1 AND rsvp = 'pending'`
+		if _, err := tx.Exec(ctx, resetQuery, e.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := insertAudit(ctx, tx, userID, "update", "event", e.ID, old, e); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// SoftDelete marks an activity, task or event deleted and audits it with a snapshot of the record
+func (r *Repository) SoftDelete(ctx context.Context, entity string, id, userID uuid.UUID, snapshot interface{}) error {
+	return r.setDeleted(ctx, entity, id, userID, true, snapshot)
+}
+
+// Restore brings back a soft-deleted activity, task or event
+func (r *Repository) Restore(ctx context.Context, entity string, id, userID uuid.UUID) error {
+	return r.setDeleted(ctx, entity, id, userID, false, nil)
+}
+
+func (r *Repository) setDeleted(ctx context.Context, entity string, id, userID uuid.UUID, deleted bool, snapshot interface{}) error {
+	table, ok := softDeleteTables[entity]
+	if !ok {
+		return fmt.Errorf("unknown record type %q", entity)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Flip deleted_at
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET deleted_at = CASE WHEN $2 THEN NOW() ELSE NULL END, updated_at = NOW()
+		WHERE id = $1 AND (deleted_at IS NULL) = $2
+	`, table)
+	tag, err := tx.Exec(ctx, query, id, deleted)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		if deleted {
+			return softDeleteNotFound[entity]
+		}
+		return fmt.Errorf("no deleted record to restore: %w", softDeleteNotFound[entity])
+	}
+
+	// 2. Audit, and task history
+	action, taskAction := "restore", models.TaskActionRestored
+	if deleted {
+		action, taskAction = "delete", models.TaskActionDeleted
+	}
+	if entity == "task" {
+		logQuery := `INSERT INTO task_logs (task_id, user_id, action) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(ctx, logQuery, id, userID, taskAction); err != nil {
+			return err
+		}
+	}
+	if err := insertAudit(ctx, tx, userID, action, entity, id, snapshot, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListEventInviteeIDs returns the invitees of an event with one of the given answers
+func (r *Repository) ListEventInviteeIDs(ctx context.Context, eventID uuid.UUID, rsvps []string) ([]uuid.UUID, error) {
+	query := `SELECT user_id FROM event_invitations WHERE event_id = $1 AND rsvp = ANY($2::text[])`
+	return r.queryUserIDs(ctx, query, eventID, rsvps)
+}
+
+// insertAudit records a change in the system audit log; values are stored as JSON snapshots
+func insertAudit(ctx context.Context, tx pgx.Tx, userID uuid.UUID, action, entity string, id uuid.UUID, oldValue, newValue interface{}) error {
+	query := `
+		INSERT INTO audit_logs (user_id, action, entity, entity_id, old_value, new_value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+	`
+	_, err := tx.Exec(ctx, query, userID, action, entity, id, oldValue, newValue)
+	return err
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,215 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/google/uuid"
+)
+
+// Scoring settings
+const (
+	MaxLeaderboardSize     = 100
+	defaultLeaderboardSize = 20
+	defaultLeaderboardTier = "District" // Jurisdiction level compared when no parent is given
+	maxReviewNoteLength    = 2000
+)
+
+// Review and scoring errors
+var (
+	ErrUnknownReview       = fmt.Errorf("review status must be %q or %q", models.ReviewVerified, models.ReviewDisputed)
+	ErrDisputeNoteRequired = errors.New("a note is required to dispute an activity")
+	ErrReviewNoteTooLong   = fmt.Errorf("note must be at most %d characters", maxReviewNoteLength)
+	ErrAlreadyReviewed     = errors.New("activity already has this review status")
+	ErrOwnActivityReview   = errors.New("only a superior committee can review an activity, not the member who logged it")
+	ErrNotReviewer         = errors.New("only committee leaders above the activity's jurisdiction can review it")
+	ErrNotSuperAdmin       = errors.New("only the Super Admin can do this")
+	ErrNegativeWeight      = errors.New("points and bonuses cannot be negative")
+	ErrFutureMonth         = errors.New("cannot compute scores for a future month")
+)
+
+// ReviewActivity verifies or disputes an activity. A dispute needs a note telling the member
+// what is wrong; a disputed activity can be verified once it is cleared up, and a verified one
+// disputed again.
+func (s *Service) ReviewActivity(ctx context.Context, activityID, reviewerID uuid.UUID, status, note string) (*models.Activity, error) {
+	// 1. Validate
+	if status != models.ReviewVerified && status != models.ReviewDisputed {
+		return nil, ErrUnknownReview
+	}
+	note = strings.TrimSpace(note)
+	if status == models.ReviewDisputed && note == "" {
+		return nil, ErrDisputeNoteRequired
+	}
+	if len(note) > maxReviewNoteLength {
+		return nil, ErrReviewNoteTooLong
+	}
+
+	a, err := s.repo.GetActivity(ctx, activityID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkReviewAccess(ctx, reviewerID, a); err != nil {
+		return nil, err
+	}
+	if a.ReviewStatus == status {
+		return nil, fmt.Errorf("activity is already %s: %w", status, ErrAlreadyReviewed)
+	}
+
+	// 2. Apply
+	var notePtr *string
+	if note != "" {
+		notePtr = &note
+	}
+	if err := s.repo.ReviewActivity(ctx, activityID, reviewerID, a.ReviewStatus, status, notePtr); err != nil {
+		return nil, err
+	}
+
+	// 3. Tell the member
+	msg := fmt.Sprintf("Your activity \"%s\" has been verified.", a.Title)
+	if status == models.ReviewDisputed {
+		msg = fmt.Sprintf("Your activity \"%s\" has been disputed: %s", a.Title, note)
+	}
+	s.notification.Create(ctx, &notification.Notification{
+		UserID:         a.UserID,
+		Type:           notification.TypeActivityReview,
+		Title:          "Activity Reviewed",
+		Message:        msg,
+		JurisdictionID: a.JurisdictionID,
+	})
+
+	return s.repo.GetActivity(ctx, activityID)
+}
+
+// ListActivityReviews returns an activity's review history
+func (s *Service) ListActivityReviews(ctx context.Context, activityID uuid.UUID) ([]*models.ActivityReview, error) {
+	if _, err := s.repo.GetActivity(ctx, activityID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListActivityReviews(ctx, activityID)
+}
+
+// ListScoreWeights returns the scoring model
+func (s *Service) ListScoreWeights(ctx context.Context) ([]*models.ScoreWeight, error) {
+	return s.repo.ListScoreWeights(ctx)
+}
+
+// UpdateScoreWeight changes a category's weights. New weights apply when scores are next
+// refreshed; months already past keep their scores unless refreshed explicitly.
+func (s *Service) UpdateScoreWeight(ctx context.Context, userID uuid.UUID, w *models.ScoreWeight) error {
+	if err := s.checkSuperAdmin(ctx, userID, "change scoring weights"); err != nil {
+		return err
+	}
+	switch w.Category {
+	case models.CategoryPolitical, models.CategorySocial, models.CategoryOrganizational, models.CategoryProtest, models.CategoryOther:
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownCategory, w.Category)
+	}
+	if w.Points < 0 || w.ProofBonus < 0 || w.MaxProofBonus < 0 {
+		return ErrNegativeWeight
+	}
+	w.UpdatedBy = &userID
+	return s.repo.UpsertScoreWeight(ctx, w)
+}
+
+// RefreshScores recomputes a month's scores on request
+func (s *Service) RefreshScores(ctx context.Context, userID uuid.UUID, month time.Time) error {
+	if err := s.checkSuperAdmin(ctx, userID, "recompute scores"); err != nil {
+		return err
+	}
+	if month.After(time.Now()) {
+		return ErrFutureMonth
+	}
+	return s.repo.RefreshMonthlyScores(ctx, month)
+}
+
+// RefreshCurrentScores recomputes the current and previous month, so reviews of late-reported
+// activities are picked up
+func (s *Service) RefreshCurrentScores(ctx context.Context, now time.Time) error {
+	month := startOfMonth(now)
+	if err := s.repo.RefreshMonthlyScores(ctx, month.AddDate(0, -1, 0)); err != nil {
+		return err
+	}
+	return s.repo.RefreshMonthlyScores(ctx, month)
+}
+
+// GetMemberLeaderboard ranks members for a month, optionally within a jurisdiction's subtree
+func (s *Service) GetMemberLeaderboard(ctx context.Context, month time.Time, jurisdictionID *uuid.UUID, limit int) (*models.Leaderboard, error) {
+	lb, limit, err := s.newLeaderboard(ctx, month, limit)
+	if err != nil {
+		return nil, err
+	}
+	lb.Members, err = s.repo.GetMemberLeaderboard(ctx, month, jurisdictionID, limit)
+	return lb, err
+}
+
+// GetJurisdictionLeaderboard ranks the direct children of a jurisdiction for a month, or every
+// jurisdiction of a level (districts by default)
+func (s *Service) GetJurisdictionLeaderboard(ctx context.Context, month time.Time, parentID *uuid.UUID, level string, limit int) (*models.Leaderboard, error) {
+	lb, limit, err := s.newLeaderboard(ctx, month, limit)
+	if err != nil {
+		return nil, err
+	}
+	if parentID == nil && level == "" {
+		level = defaultLeaderboardTier
+	}
+	lb.Jurisdictions, err = s.repo.GetJurisdictionLeaderboard(ctx, month, parentID, level, limit)
+	return lb, err
+}
+
+func (s *Service) newLeaderboard(ctx context.Context, month time.Time, limit int) (*models.Leaderboard, int, error) {
+	if limit < 1 || limit > MaxLeaderboardSize {
+		limit = defaultLeaderboardSize
+	}
+	computedAt, err := s.repo.GetScoresComputedAt(ctx, month)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &models.Leaderboard{Month: month.Format("2006-01"), ComputedAt: computedAt}, limit, nil
+}
+
+// checkReviewAccess allows the Super Admin and committee leaders (positions up to auth.LeaderRank)
+// of a jurisdiction above the activity's; members never review their own activities
+func (s *Service) checkReviewAccess(ctx context.Context, userID uuid.UUID, a *models.Activity) error {
+	if a.UserID == userID {
+		return ErrOwnActivityReview
+	}
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if authority.SuperAdmin {
+		return nil
+	}
+	if authority.JurisdictionID != nil && authority.IsLeader() && *authority.JurisdictionID != a.JurisdictionID {
+		ok, err := s.org.IsChildJurisdiction(ctx, *authority.JurisdictionID, a.JurisdictionID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return ErrNotReviewer
+}
+
+func (s *Service) checkSuperAdmin(ctx context.Context, userID uuid.UUID, action string) error {
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !authority.SuperAdmin {
+		return fmt.Errorf("cannot %s: %w", action, ErrNotSuperAdmin)
+	}
+	return nil
+}
+
+// startOfMonth returns midnight UTC on the first day of t's month
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -2,23 +2,58 @@ package activity
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/bjdms/api/internal/auth"
 	"github.com/bjdms/api/internal/models"
 	"github.com/bjdms/api/internal/notification"
+	"github.com/bjdms/api/pkg/storage"
 	"github.com/google/uuid"
 )
 
+// MaxProofsPerActivity caps the number of files attached to one activity
+const MaxProofsPerActivity = 20
+
+// Activity proof and report errors
+var (
+	ErrNotActivityOwner     = errors.New("only the member who logged this activity can attach proofs")
+	ErrNotProofViewer       = errors.New("only the member who logged this activity, their superiors and its reviewers can view its proofs")
+	ErrTooManyProofs        = fmt.Errorf("an activity can have at most %d proofs", MaxProofsPerActivity)
+	ErrJurisdictionNotFound = errors.New("jurisdiction not found")
+)
+
+// Record validation errors, shared by activities, tasks and events
+var (
+	ErrTitleRequired      = errors.New("title is required")
+	ErrUnknownCategory    = errors.New("unknown activity category")
+	ErrFutureActivity     = errors.New("activity date cannot be in the future")
+	ErrInvalidPriority    = errors.New("priority must be between 1 and 4")
+	ErrStartRequired      = errors.New("start time is required")
+	ErrEndBeforeStart     = errors.New("end time must be after the start time")
+	ErrInvalidCapacity    = errors.New("capacity must be positive")
+	ErrDeadlineAfterStart = errors.New("rsvp deadline must not be after the start time")
+)
+
+// JurisdictionChecker resolves jurisdiction hierarchy (implemented by committee.Service)
+type JurisdictionChecker interface {
+	IsChildJurisdiction(ctx context.Context, parentID, targetID uuid.UUID) (bool, error)
+}
+
 // Service defines business logic for activities and tasks
 type Service struct {
 	repo         *Repository
 	notification *notification.Service
+	files        *storage.Uploader
+	authRepo     *auth.Repository
+	org          JurisdictionChecker
+	checkIn      *CheckInSigner
 }
 
 // NewService creates a new activity service
-func NewService(repo *Repository, ns *notification.Service) *Service {
-	return &Service{repo: repo, notification: ns}
+func NewService(repo *Repository, ns *notification.Service, files *storage.Uploader, authRepo *auth.Repository, org JurisdictionChecker, checkIn *CheckInSigner) *Service {
+	return &Service{repo: repo, notification: ns, files: files, authRepo: authRepo, org: org, checkIn: checkIn}
 }
 
 // ACTIVITIES
@@ -38,7 +73,7 @@ func (s *Service) LogActivity(ctx context.Context, a *models.Activity) error {
 }
 
 // ListActivities returns activities with jurisdiction-aware filtering
-func (s *Service) ListActivities(ctx context.Context, jurisdictionID *uuid.UUID, userID *uuid.UUID, page, pageSize int) ([]*models.Activity, error) {
+func (s *Service) ListActivities(ctx context.Context, jurisdictionID *uuid.UUID, userID *uuid.UUID, reviewStatus string, page, pageSize int) ([]*models.Activity, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -47,7 +82,117 @@ func (s *Service) ListActivities(ctx context.Context, jurisdictionID *uuid.UUID,
 	}
 	offset := (page - 1) * pageSize
 
-	return s.repo.ListActivities(ctx, jurisdictionID, userID, pageSize, offset)
+	return s.repo.ListActivities(ctx, jurisdictionID, userID, reviewStatus, pageSize, offset)
+}
+
+// CanAttachProofs checks that userID may add proof files to the activity.
+// Only the member who logged the activity may attach proofs.
+func (s *Service) CanAttachProofs(ctx context.Context, activityID, userID uuid.UUID) error {
+	return s.checkProofQuota(ctx, activityID, userID, 1)
+}
+
+func (s *Service) checkProofQuota(ctx context.Context, activityID, userID uuid.UUID, adding int) error {
+	a, err := s.repo.GetActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if a.UserID != userID {
+		return ErrNotActivityOwner
+	}
+
+	count, err := s.repo.CountProofs(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if count+adding > MaxProofsPerActivity {
+		return ErrTooManyProofs
+	}
+	return nil
+}
+
+// AttachProofs records stored files as proofs of an activity
+func (s *Service) AttachProofs(ctx context.Context, activityID, userID uuid.UUID, files []*storage.File) ([]*models.ActivityProof, error) {
+	// 1. Re-check ownership and the cap now that the number of files is known
+	if err := s.checkProofQuota(ctx, activityID, userID, len(files)); err != nil {
+		return nil, err
+	}
+
+	// 2. Persist one row per file
+	return s.createProofs(ctx, files, userID, &activityID, nil)
+}
+
+// createProofs stores one proof row per uploaded file, owned by either an activity or a task
+func (s *Service) createProofs(ctx context.Context, files []*storage.File, userID uuid.UUID, activityID, taskID *uuid.UUID) ([]*models.ActivityProof, error) {
+	var proofs []*models.ActivityProof
+	for _, f := range files {
+		p := &models.ActivityProof{
+			ActivityID:   activityID,
+			TaskID:       taskID,
+			FilePath:     f.Key,
+			FileType:     f.ContentType,
+			FileSize:     f.Size,
+			ContentHash:  f.SHA256,
+			OriginalName: f.OriginalName,
+			UploadedBy:   &userID,
+		}
+		if err := s.repo.CreateProof(ctx, p); err != nil {
+			return proofs, err
+		}
+		proofs = append(proofs, p)
+	}
+	return proofs, nil
+}
+
+// ListProofs returns an activity's proofs with signed download links to those who may see them
+func (s *Service) ListProofs(ctx context.Context, activityID, userID uuid.UUID) ([]*models.ActivityProof, error) {
+	a, err := s.repo.GetActivity(ctx, activityID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProofViewer(ctx, a, userID); err != nil {
+		return nil, err
+	}
+	proofs, err := s.repo.ListProofs(ctx, activityID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range proofs {
+		s.signProof(ctx, p)
+	}
+	return proofs, nil
+}
+
+// checkProofViewer allows the member who logged an activity, members senior to them at or above
+// its jurisdiction, and those who may review it
+func (s *Service) checkProofViewer(ctx context.Context, a *models.Activity, userID uuid.UUID) error {
+	if a.UserID == userID {
+		return nil
+	}
+	superior, err := s.isSuperior(ctx, userID, a.UserID, a.JurisdictionID)
+	if err != nil {
+		return err
+	}
+	if superior {
+		return nil
+	}
+	if err := s.checkReviewAccess(ctx, userID, a); !errors.Is(err, ErrNotReviewer) {
+		return err
+	}
+	return ErrNotProofViewer
+}
+
+// signProof attaches download links. Files are only linked once the media pipeline is done with them.
+func (s *Service) signProof(ctx context.Context, p *models.ActivityProof) {
+	if !models.MediaViewable(p.ProcessingStatus) {
+		return
+	}
+	p.URL, _ = s.files.SignedURL(ctx, p.FilePath)
+	if p.ThumbnailPath != nil {
+		p.ThumbnailURL, _ = s.files.SignedURL(ctx, *p.ThumbnailPath)
+	}
+	if p.OriginalPath != nil {
+		p.OriginalURL, _ = s.files.SignedURL(ctx, *p.OriginalPath)
+	}
 }
 
 // TASKS
@@ -55,9 +200,9 @@ func (s *Service) ListActivities(ctx context.Context, jurisdictionID *uuid.UUID,
 // CreateTask handles task creation and assignment logic
 func (s *Service) CreateTask(ctx context.Context, t *models.Task) error {
 	// 1. Validation logic (e.g. Creator must have authority over assignee/jurisdiction)
-	if t.Status == "" {
-		t.Status = models.TaskStatusPending
-	}
+	// Every task starts pending; later states are reached through UpdateTaskStatus only
+	t.Status = models.TaskStatusPending
+	t.CompletionNote = nil
 	if t.Priority == 0 {
 		t.Priority = 3 // Medium default
 	}
@@ -67,7 +212,7 @@ func (s *Service) CreateTask(ctx context.Context, t *models.Task) error {
 		return err
 	}
 
-	// 3. Notify Assignee
+	// 3. Notify Assignee, or every member when assigned to a committee or sub-committee
 	if t.AssigneeID != nil {
 		s.notification.Create(ctx, &notification.Notification{
 			UserID:         *t.AssigneeID,
@@ -76,6 +221,13 @@ func (s *Service) CreateTask(ctx context.Context, t *models.Task) error {
 			Message:        fmt.Sprintf("You have been assigned a new task: %s", t.Title),
 			JurisdictionID: t.JurisdictionID,
 		})
+	} else if t.CommitteeID != nil {
+		s.notification.NotifyCommittee(ctx, *t.CommitteeID, notification.Notification{
+			Type:           notification.TypeTaskAssigned,
+			Title:          "New Task Assigned",
+			Message:        fmt.Sprintf("Your committee has been assigned a new task: %s", t.Title),
+			JurisdictionID: t.JurisdictionID,
+		}, &t.CreatorID)
 	}
 
 	return nil
@@ -83,25 +235,44 @@ func (s *Service) CreateTask(ctx context.Context, t *models.Task) error {
 
 // ListTasks returns tasks filtered by jurisdiction, assignee, or committee
 func (s *Service) ListTasks(ctx context.Context, jurisdictionID *uuid.UUID, assigneeID *uuid.UUID, committeeID *uuid.UUID) ([]*models.Task, error) {
-	return s.repo.ListTasks(ctx, jurisdictionID, assigneeID, committeeID)
-}
+	list, err := s.repo.ListTasks(ctx, jurisdictionID, assigneeID, committeeID)
+	if err != nil || len(list) == 0 {
+		return list, err
+	}
 
-// UpdateTaskStatus changes status and handles completion timestamps
-func (s *Service) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, status string) error {
-	// Valid status check
-	validStatuses := map[string]bool{
-		models.TaskStatusPending:    true,
-		models.TaskStatusInProgress: true,
-		models.TaskStatusCompleted:  true,
-		models.TaskStatusVerified:   true,
-		models.TaskStatusCancelled:  true,
+	// Attach member progress to committee tasks
+	ids := make([]uuid.UUID, len(list))
+	for i, t := range list {
+		ids[i] = t.ID
 	}
+	progress, err := s.repo.GetTaskProgress(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range list {
+		t.Progress = progress[t.ID]
+	}
+	return list, nil
+}
 
-	if !validStatuses[status] {
-		return fmt.Errorf("invalid task status: %s", status)
+// GetOverdueReport counts open and overdue tasks for every jurisdiction in a subtree
+func (s *Service) GetOverdueReport(ctx context.Context, rootID uuid.UUID) (*models.OverdueReport, error) {
+	rows, err := s.repo.ListOverdueCounts(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrJurisdictionNotFound
 	}
 
-	return s.repo.UpdateTaskStatus(ctx, taskID, status)
+	report := &models.OverdueReport{RootID: rootID, GeneratedAt: time.Now(), Jurisdictions: rows}
+	for _, c := range rows {
+		report.OpenTasks += c.OpenTasks
+		report.Overdue += c.Overdue
+		report.Escalated += c.Escalated
+		report.EscalatedUp += c.EscalatedUp
+	}
+	return report, nil
 }
 
 // EVENTS
@@ -109,7 +280,16 @@ func (s *Service) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, status
 // CreateEvent handles event creation logic
 func (s *Service) CreateEvent(ctx context.Context, e *models.Event) error {
 	if e.StartTime.IsZero() {
-		return fmt.Errorf("start time is required")
+		return ErrStartRequired
+	}
+	if e.Capacity != nil && *e.Capacity <= 0 {
+		return ErrInvalidCapacity
+	}
+	if e.RSVPDeadline != nil && e.RSVPDeadline.After(e.StartTime) {
+		return ErrDeadlineAfterStart
+	}
+	if err := validateCheckInSettings(e); err != nil {
+		return err
 	}
 	return s.repo.CreateEvent(ctx, e)
 }
@@ -118,8 +298,3 @@ func (s *Service) CreateEvent(ctx context.Context, e *models.Event) error {
 func (s *Service) ListEvents(ctx context.Context, jurisdictionID *uuid.UUID) ([]*models.Event, error) {
 	return s.repo.ListEvents(ctx, jurisdictionID)
 }
-
-// MarkAttendance records attendance at an event
-func (s *Service) MarkAttendance(ctx context.Context, eventID, userID uuid.UUID) error {
-	return s.repo.MarkAttendance(ctx, eventID, userID)
-}
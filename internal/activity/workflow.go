@@ -0,0 +1,503 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/bjdms/api/pkg/storage"
+	"github.com/google/uuid"
+)
+
+// MaxProofsPerTask caps the number of files attached to one task
+const MaxProofsPerTask = 20
+
+// Task transition errors
+var (
+	ErrTaskAlready            = errors.New("task already has this status")
+	ErrTaskTransition         = errors.New("task status change is not allowed")
+	ErrCommitteeTaskProgress  = errors.New("progress on a committee task is tracked per member, update your assignment instead")
+	ErrCommitteeTaskRework    = errors.New("reopen individual member assignments to send a committee task back")
+	ErrNotAssignee            = errors.New("only the assignee can update this task")
+	ErrNotSupervisor          = errors.New("only the task creator or a superior can do this")
+	ErrNotTaskViewer          = errors.New("only the task's assignees, creator or superiors can view it")
+	ErrCompletionNoteRequired = errors.New("a completion note is required for this task")
+	ErrTaskProofRequired      = errors.New("proof must be uploaded before this task can be completed")
+	ErrReworkNoteRequired     = errors.New("a note is required when sending a task back for rework")
+)
+
+// Task proof and assignment errors
+var (
+	ErrProofClosed          = errors.New("proof can only be added to an open task")
+	ErrAssignmentDone       = errors.New("your part of this task is already completed")
+	ErrTooManyTaskProofs    = fmt.Errorf("a task can have at most %d proofs", MaxProofsPerTask)
+	ErrNotCommitteeTask     = errors.New("task is not assigned to committee members")
+	ErrAssignmentAlready    = errors.New("assignment already has this status")
+	ErrAssignmentTransition = errors.New("assignment status change is not allowed")
+	ErrNotOwnAssignment     = errors.New("only the member can update their own assignment")
+	ErrReopenNoteRequired   = errors.New("a note is required when reopening an assignment")
+)
+
+// Who may perform a transition
+const (
+	actorAssignee   = "assignee"   // The assignee, or any active member of an assigned committee
+	actorSupervisor = "supervisor" // The creator, or a higher-ranked leader over the task's jurisdiction
+)
+
+// taskTransitions is the task lifecycle: from status -> to status -> who may move it.
+// Verified and cancelled are final.
+var taskTransitions = map[string]map[string]string{
+	models.TaskStatusPending: {
+		models.TaskStatusInProgress: actorAssignee,
+		models.TaskStatusCompleted:  actorAssignee,
+		models.TaskStatusCancelled:  actorSupervisor,
+	},
+	models.TaskStatusInProgress: {
+		models.TaskStatusPending:   actorAssignee, // Put back, e.g. picked up by mistake
+		models.TaskStatusCompleted: actorAssignee,
+		models.TaskStatusCancelled: actorSupervisor,
+	},
+	models.TaskStatusCompleted: {
+		models.TaskStatusVerified:   actorSupervisor,
+		models.TaskStatusInProgress: actorSupervisor, // Sent back for rework
+	},
+}
+
+// UpdateTaskStatus moves a task along its lifecycle on behalf of userID
+func (s *Service) UpdateTaskStatus(ctx context.Context, taskID, userID uuid.UUID, status, note string) error {
+	note = strings.TrimSpace(note)
+
+	// 1. Check the transition exists
+	t, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if t.Status == status {
+		return fmt.Errorf("task is already %s: %w", status, ErrTaskAlready)
+	}
+	actor, ok := taskTransitions[t.Status][status]
+	if !ok {
+		return fmt.Errorf("task cannot move from %s to %s: %w", t.Status, status, ErrTaskTransition)
+	}
+
+	// Committee tasks move with their member assignments
+	if t.Progress != nil {
+		if actor == actorAssignee {
+			return ErrCommitteeTaskProgress
+		}
+		if t.Status == models.TaskStatusCompleted && status == models.TaskStatusInProgress {
+			return ErrCommitteeTaskRework
+		}
+	}
+
+	// 2. Check who is acting
+	switch actor {
+	case actorAssignee:
+		isAssignee, err := s.isTaskAssignee(ctx, t, userID)
+		if err != nil {
+			return err
+		}
+		if !isAssignee {
+			return fmt.Errorf("cannot mark this task as %s: %w", status, ErrNotAssignee)
+		}
+	case actorSupervisor:
+		isSupervisor, err := s.isTaskSupervisor(ctx, t, userID)
+		if err != nil {
+			return err
+		}
+		if !isSupervisor {
+			return fmt.Errorf("cannot mark this task as %s: %w", status, ErrNotSupervisor)
+		}
+	}
+
+	// 3. Completion requirements
+	if status == models.TaskStatusCompleted {
+		if t.RequiresCompletionNote && note == "" {
+			return ErrCompletionNoteRequired
+		}
+		if t.RequiresProof {
+			count, err := s.repo.CountTaskProofs(ctx, t.ID, nil)
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrTaskProofRequired
+			}
+		}
+	}
+	if t.Status == models.TaskStatusCompleted && status == models.TaskStatusInProgress && note == "" {
+		return ErrReworkNoteRequired
+	}
+
+	// 4. Persist with history
+	if err := s.repo.TransitionTask(ctx, t.ID, t.Status, status, userID, note); err != nil {
+		return err
+	}
+
+	// 5. Notify the other side
+	s.notifyTaskTransition(ctx, t, userID, status)
+	return nil
+}
+
+// notifyTaskTransition tells the creator about completions and the assignees about supervisor decisions
+func (s *Service) notifyTaskTransition(ctx context.Context, t *models.Task, actorID uuid.UUID, status string) {
+	if status == models.TaskStatusCompleted {
+		if t.CreatorID != actorID {
+			s.notification.Create(ctx, &notification.Notification{
+				UserID:         t.CreatorID,
+				Type:           notification.TypeTaskCompleted,
+				Title:          "Task Completed",
+				Message:        fmt.Sprintf("\"%s\" has been marked as completed and is awaiting your verification.", t.Title),
+				JurisdictionID: t.JurisdictionID,
+			})
+		}
+		return
+	}
+
+	var message string
+	switch status {
+	case models.TaskStatusVerified:
+		message = fmt.Sprintf("Your work on \"%s\" has been verified.", t.Title)
+	case models.TaskStatusCancelled:
+		message = fmt.Sprintf("The task \"%s\" has been cancelled.", t.Title)
+	case models.TaskStatusInProgress:
+		if t.Status != models.TaskStatusCompleted {
+			return
+		}
+		message = fmt.Sprintf("The task \"%s\" has been sent back for rework.", t.Title)
+	default:
+		return
+	}
+
+	n := notification.Notification{
+		Type:           notification.TypeTaskUpdated,
+		Title:          "Task Updated",
+		Message:        message,
+		JurisdictionID: t.JurisdictionID,
+	}
+	if t.AssigneeID != nil {
+		if *t.AssigneeID != actorID {
+			n.UserID = *t.AssigneeID
+			s.notification.Create(ctx, &n)
+		}
+	} else if t.CommitteeID != nil {
+		s.notification.NotifyCommittee(ctx, *t.CommitteeID, n, &actorID)
+	}
+}
+
+// isTaskAssignee reports whether userID does the work on the task. For committee tasks that is
+// every member with an assignment; older committee tasks without assignments fall back to
+// current membership.
+func (s *Service) isTaskAssignee(ctx context.Context, t *models.Task, userID uuid.UUID) (bool, error) {
+	if t.AssigneeID != nil {
+		return *t.AssigneeID == userID, nil
+	}
+	if t.Progress != nil {
+		_, err := s.repo.GetAssignment(ctx, t.ID, userID)
+		if err != nil {
+			if errors.Is(err, ErrAssignmentNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+	if t.CommitteeID != nil {
+		return s.repo.IsActiveCommitteeMember(ctx, *t.CommitteeID, userID)
+	}
+	return false, nil
+}
+
+// isTaskSupervisor reports whether userID is the creator, a Super Admin, or outranks the creator
+// from the task's jurisdiction or one above it
+func (s *Service) isTaskSupervisor(ctx context.Context, t *models.Task, userID uuid.UUID) (bool, error) {
+	if t.CreatorID == userID {
+		return true, nil
+	}
+	return s.isSuperior(ctx, userID, t.CreatorID, t.JurisdictionID)
+}
+
+// checkTaskViewer allows the people who may move the task: its assignees and its supervisors.
+// what names the part of the task being read, for the error message.
+func (s *Service) checkTaskViewer(ctx context.Context, t *models.Task, userID uuid.UUID, what string) error {
+	isAssignee, err := s.isTaskAssignee(ctx, t, userID)
+	if err != nil {
+		return err
+	}
+	if isAssignee {
+		return nil
+	}
+	isSupervisor, err := s.isTaskSupervisor(ctx, t, userID)
+	if err != nil {
+		return err
+	}
+	if !isSupervisor {
+		return fmt.Errorf("cannot view the task's %s: %w", what, ErrNotTaskViewer)
+	}
+	return nil
+}
+
+// TASK PROOFS
+
+// CanAttachTaskProofs checks that userID may upload proof for the task
+func (s *Service) CanAttachTaskProofs(ctx context.Context, taskID, userID uuid.UUID) (*models.Task, error) {
+	t, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != models.TaskStatusPending && t.Status != models.TaskStatusInProgress {
+		return nil, fmt.Errorf("task is %s: %w", t.Status, ErrProofClosed)
+	}
+	isAssignee, err := s.isTaskAssignee(ctx, t, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAssignee {
+		return nil, fmt.Errorf("cannot upload proof: %w", ErrNotAssignee)
+	}
+	if t.Progress != nil {
+		a, err := s.repo.GetAssignment(ctx, t.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if a.Status == models.TaskStatusCompleted {
+			return nil, ErrAssignmentDone
+		}
+	}
+	return t, nil
+}
+
+// AttachTaskProofs records stored files as proof of work on a task
+func (s *Service) AttachTaskProofs(ctx context.Context, taskID, userID uuid.UUID, files []*storage.File) ([]*models.ActivityProof, error) {
+	// 1. Permission and cap; on committee tasks the cap applies to each member
+	t, err := s.CanAttachTaskProofs(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	var uploader *uuid.UUID
+	if t.Progress != nil {
+		uploader = &userID
+	}
+	existing, err := s.repo.CountTaskProofs(ctx, taskID, uploader)
+	if err != nil {
+		return nil, err
+	}
+	if existing+len(files) > MaxProofsPerTask {
+		return nil, ErrTooManyTaskProofs
+	}
+
+	// 2. Persist and record in the history
+	proofs, err := s.createProofs(ctx, files, userID, nil, &taskID)
+	if err != nil {
+		return proofs, err
+	}
+	note := fmt.Sprintf("%d file(s) uploaded", len(proofs))
+	s.repo.AddTaskLog(ctx, &models.TaskLog{
+		TaskID: taskID,
+		UserID: &userID,
+		Action: models.TaskActionProofAdded,
+		Note:   &note,
+	})
+	return proofs, nil
+}
+
+// ListTaskProofs returns a task's proofs with signed download links to its assignees and supervisors
+func (s *Service) ListTaskProofs(ctx context.Context, taskID, userID uuid.UUID) ([]*models.ActivityProof, error) {
+	t, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTaskViewer(ctx, t, userID, "proofs"); err != nil {
+		return nil, err
+	}
+	proofs, err := s.repo.ListTaskProofs(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range proofs {
+		s.signProof(ctx, p)
+	}
+	return proofs, nil
+}
+
+// GetTaskHistory returns a task's history log to its assignees and supervisors
+func (s *Service) GetTaskHistory(ctx context.Context, taskID, userID uuid.UUID) ([]*models.TaskLog, error) {
+	t, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTaskViewer(ctx, t, userID, "history"); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTaskLogs(ctx, taskID)
+}
+
+// COMMITTEE ASSIGNMENTS
+
+// assignmentTransitions is the lifecycle of one member's share of a committee task
+var assignmentTransitions = map[string]map[string]string{
+	models.TaskStatusPending: {
+		models.TaskStatusInProgress: actorAssignee,
+		models.TaskStatusCompleted:  actorAssignee,
+	},
+	models.TaskStatusInProgress: {
+		models.TaskStatusPending:   actorAssignee,
+		models.TaskStatusCompleted: actorAssignee,
+	},
+	models.TaskStatusCompleted: {
+		models.TaskStatusInProgress: actorSupervisor, // Reopened for rework
+	},
+}
+
+// GetTask returns a task with its member progress when it is a committee task
+func (s *Service) GetTask(ctx context.Context, taskID uuid.UUID) (*models.Task, error) {
+	t, err := s.repo.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.repo.GetTaskProgress(ctx, []uuid.UUID{t.ID})
+	if err != nil {
+		return nil, err
+	}
+	t.Progress = progress[t.ID]
+	return t, nil
+}
+
+// ListTaskAssignments returns the member checklist of a committee task to its supervisors and members
+func (s *Service) ListTaskAssignments(ctx context.Context, taskID, userID uuid.UUID) ([]*models.TaskAssignment, error) {
+	t, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Progress == nil {
+		return nil, ErrNotCommitteeTask
+	}
+
+	if err := s.checkTaskViewer(ctx, t, userID, "assignments"); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAssignments(ctx, taskID)
+}
+
+// UpdateAssignmentStatus moves memberID's share of a committee task. Members update their own
+// assignment; the creator or a superior can reopen a completed one. The task itself follows:
+// it starts when the first member starts and completes when the last member completes.
+func (s *Service) UpdateAssignmentStatus(ctx context.Context, taskID, memberID, userID uuid.UUID, status, note string) error {
+	note = strings.TrimSpace(note)
+
+	// 1. Load the task and the assignment
+	t, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if t.Progress == nil {
+		return ErrNotCommitteeTask
+	}
+	if t.Status == models.TaskStatusVerified || t.Status == models.TaskStatusCancelled {
+		return fmt.Errorf("task is already %s: %w", t.Status, ErrTaskAlready)
+	}
+	a, err := s.repo.GetAssignment(ctx, taskID, memberID)
+	if err != nil {
+		return err
+	}
+
+	// 2. Check the transition and who is acting
+	if a.Status == status {
+		return fmt.Errorf("assignment is already %s: %w", status, ErrAssignmentAlready)
+	}
+	actor, ok := assignmentTransitions[a.Status][status]
+	if !ok {
+		return fmt.Errorf("assignment cannot move from %s to %s: %w", a.Status, status, ErrAssignmentTransition)
+	}
+	switch actor {
+	case actorAssignee:
+		if memberID != userID {
+			return ErrNotOwnAssignment
+		}
+	case actorSupervisor:
+		isSupervisor, err := s.isTaskSupervisor(ctx, t, userID)
+		if err != nil {
+			return err
+		}
+		if !isSupervisor {
+			return fmt.Errorf("cannot reopen an assignment: %w", ErrNotSupervisor)
+		}
+		if note == "" {
+			return ErrReopenNoteRequired
+		}
+	}
+
+	// 3. Completion requirements apply to each member
+	if status == models.TaskStatusCompleted {
+		if t.RequiresCompletionNote && note == "" {
+			return ErrCompletionNoteRequired
+		}
+		if t.RequiresProof {
+			count, err := s.repo.CountTaskProofs(ctx, t.ID, &memberID)
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrTaskProofRequired
+			}
+		}
+	}
+
+	// 4. Persist with history
+	if err := s.repo.TransitionAssignment(ctx, taskID, memberID, a.Status, status, userID, note); err != nil {
+		return err
+	}
+
+	if actor == actorSupervisor && memberID != userID {
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         memberID,
+			Type:           notification.TypeTaskUpdated,
+			Title:          "Task Reopened",
+			Message:        fmt.Sprintf("Your part of \"%s\" has been reopened: %s", t.Title, note),
+			JurisdictionID: t.JurisdictionID,
+		})
+	}
+
+	// 5. Bring the task in line with its assignments
+	return s.syncTaskWithAssignments(ctx, t, userID)
+}
+
+// syncTaskWithAssignments moves a committee task to match its members' progress
+func (s *Service) syncTaskWithAssignments(ctx context.Context, t *models.Task, userID uuid.UUID) error {
+	progress, err := s.repo.GetTaskProgress(ctx, []uuid.UUID{t.ID})
+	if err != nil {
+		return err
+	}
+	p := progress[t.ID]
+	if p == nil {
+		return nil
+	}
+
+	target, note := t.Status, ""
+	switch {
+	case p.Completed == p.Total && t.Status != models.TaskStatusCompleted:
+		target, note = models.TaskStatusCompleted, fmt.Sprintf("All %d members completed", p.Total)
+	case p.Completed < p.Total && t.Status == models.TaskStatusCompleted:
+		target, note = models.TaskStatusInProgress, "Reopened: "+p.Summary
+	case p.Pending < p.Total && t.Status == models.TaskStatusPending:
+		target = models.TaskStatusInProgress
+	}
+	if target == t.Status {
+		return nil
+	}
+
+	if err := s.repo.TransitionTask(ctx, t.ID, t.Status, target, userID, note); err != nil {
+		// Another member's update got there first and has already synced the task
+		if errors.Is(err, ErrTaskStatusChanged) {
+			return nil
+		}
+		return err
+	}
+	if target == models.TaskStatusCompleted {
+		s.notifyTaskTransition(ctx, t, userID, target)
+	}
+	return nil
+}
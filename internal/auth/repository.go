@@ -15,6 +15,24 @@ var (
 	ErrUserNotFound = errors.New("user not found")
 )
 
+// Position ranks used for authorization (positions.rank; lower is higher)
+const (
+	LeaderRank     = 2   // President or Convener (1), General or Member Secretary (2)
+	NoPositionRank = 999 // Users without a committee position
+)
+
+// Authority is what a user's account and committee position entitle them to
+type Authority struct {
+	JurisdictionID *uuid.UUID
+	Rank           int  // Rank of the best position held, not its catalog ID
+	SuperAdmin     bool // Set on the account; no committee position grants it
+}
+
+// IsLeader reports whether the user leads their committee
+func (a *Authority) IsLeader() bool {
+	return a.Rank <= LeaderRank
+}
+
 // Repository defines database operations for authentication
 type Repository struct {
 	db *pgxpool.Pool
@@ -126,16 +144,33 @@ func (r *Repository) Create(ctx context.Context, u *models.User) error {
 	).Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt)
 }
 
-// GetUserAuthDetails retrieves jurisdiction and rank for a user to support ABAC/RBAC
-func (r *Repository) GetUserAuthDetails(ctx context.Context, userID uuid.UUID) (jurisdictionID *uuid.UUID, rank int, err error) {
+// GetUserAuthority retrieves a user's jurisdiction, position rank and Super Admin flag. Rank and
+// jurisdiction come from the highest seat the user holds in an active committee, so they follow
+// membership changes; sub-committee seats grant no authority. Members without a seat keep their
+// home jurisdiction.
+func (r *Repository) GetUserAuthority(ctx context.Context, userID uuid.UUID) (*Authority, error) {
 	query := `
-		SELECT jurisdiction_id, COALESCE(current_position_id, 999)
-		FROM users
-		WHERE id = $1 AND deleted_at IS NULL
+		SELECT COALESCE(seat.jurisdiction_id, u.jurisdiction_id), COALESCE(seat.rank, $2), u.is_super_admin
+		FROM users u
+		LEFT JOIN LATERAL (
+			SELECT c.jurisdiction_id, p.rank
+			FROM committee_members cm
+			JOIN committees c ON cm.committee_id = c.id
+			JOIN positions p ON cm.position_id = p.id
+			WHERE cm.user_id = u.id AND cm.ended_at IS NULL AND cm.is_active = TRUE
+			  AND c.status = 'active' AND c.type <> 'sub_committee' AND c.deleted_at IS NULL
+			ORDER BY p.rank ASC
+			LIMIT 1
+		) seat ON TRUE
+		WHERE u.id = $1 AND u.deleted_at IS NULL
 	`
-	err = r.db.QueryRow(ctx, query, userID).Scan(&jurisdictionID, &rank)
+	var a Authority
+	err := r.db.QueryRow(ctx, query, userID, NoPositionRank).Scan(&a.JurisdictionID, &a.Rank, &a.SuperAdmin)
 	if err == pgx.ErrNoRows {
-		return nil, 999, ErrUserNotFound
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
-	return jurisdictionID, rank, err
+	return &a, nil
 }
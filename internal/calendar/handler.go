@@ -0,0 +1,188 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bjdms/api/internal/middleware"
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/ical"
+	"github.com/bjdms/api/pkg/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Handler handles calendar HTTP endpoints
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new calendar handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Routes returns routes for managing a member's feeds
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/feeds", h.CreateFeed)
+	r.Get("/feeds", h.ListFeeds)
+	r.Delete("/feeds/{id}", h.RevokeFeed)
+
+	return r
+}
+
+// PublicRoutes returns the feed endpoints polled by calendar apps, which cannot send a login
+func (h *Handler) PublicRoutes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/feeds/{token}.ics", h.Feed)
+	r.Get("/jurisdictions/{id}.ics", h.PublicCalendar)
+
+	return r
+}
+
+// CreateFeed handles POST /api/v1/calendar/feeds
+func (h *Handler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name           string     `json:"name"`
+		Scope          string     `json:"scope"`
+		JurisdictionID *uuid.UUID `json:"jurisdiction_id"`
+		IncludeTasks   bool       `json:"include_tasks"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	f := &models.CalendarFeed{
+		UserID:         userID,
+		Name:           req.Name,
+		Scope:          req.Scope,
+		JurisdictionID: req.JurisdictionID,
+		IncludeTasks:   req.IncludeTasks,
+	}
+	if err := h.service.CreateFeed(r.Context(), f); err != nil {
+		writeError(w, err)
+		return
+	}
+	f.URL = fmt.Sprintf("%s/api/v1/public/calendar/feeds/%s.ics", baseURL(r), f.Token)
+
+	response.Created(w, f, "Calendar feed created. Keep the link private; it cannot be shown again.")
+}
+
+// ListFeeds handles GET /api/v1/calendar/feeds
+func (h *Handler) ListFeeds(w http.ResponseWriter, r *http.Request) {
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	list, err := h.service.ListFeeds(r.Context(), userID)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch calendar feeds", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// RevokeFeed handles DELETE /api/v1/calendar/feeds/{id}
+func (h *Handler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid feed ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.RevokeFeed(r.Context(), id, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Calendar feed revoked")
+}
+
+// Feed handles GET /api/v1/public/calendar/feeds/{token}.ics
+func (h *Handler) Feed(w http.ResponseWriter, r *http.Request) {
+	cal, err := h.service.RenderFeed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		// Any failure on a token looks the same, so tokens cannot be probed
+		if errors.Is(err, ErrFeedNotFound) {
+			response.NotFound(w, "Calendar feed not found")
+			return
+		}
+		response.InternalError(w, "Failed to build calendar", "")
+		return
+	}
+	writeCalendar(w, cal, "private")
+}
+
+// PublicCalendar handles GET /api/v1/public/calendar/jurisdictions/{id}.ics
+func (h *Handler) PublicCalendar(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	cal, err := h.service.RenderPublicCalendar(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeCalendar(w, cal, "public")
+}
+
+func writeCalendar(w http.ResponseWriter, cal *ical.Calendar, cache string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", cache, int(RefreshInterval.Seconds()/4)))
+	w.WriteHeader(http.StatusOK)
+	cal.Encode(w)
+}
+
+// baseURL returns the scheme and host the client used to reach the API
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case matchesAny(err, notFoundErrors):
+		response.NotFound(w, err.Error())
+	case matchesAny(err, forbiddenErrors):
+		response.Forbidden(w, err.Error())
+	case matchesAny(err, invalidErrors):
+		response.BadRequest(w, err.Error())
+	default:
+		// Database and other internal failures are not shown to the client
+		response.InternalError(w, "Failed to process calendar request", "")
+	}
+}
+
+// Service errors by response status; anything else is an internal error
+var (
+	notFoundErrors  = []error{ErrFeedNotFound, ErrJurisdictionNotFound}
+	forbiddenErrors = []error{ErrFeedAccess}
+	invalidErrors   = []error{ErrNameTooLong, ErrJurisdictionRequired, ErrInvalidScope, ErrTooManyFeeds}
+)
+
+func matchesAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"feed not found", ErrFeedNotFound, http.StatusNotFound, ErrFeedNotFound.Error()},
+		{"jurisdiction not found", ErrJurisdictionNotFound, http.StatusNotFound, ErrJurisdictionNotFound.Error()},
+		{"no access", ErrFeedAccess, http.StatusForbidden, ErrFeedAccess.Error()},
+		{"too many feeds", fmt.Errorf("%w: at most %d are allowed; revoke one first", ErrTooManyFeeds, MaxFeedsPerUser), http.StatusBadRequest, "revoke one first"},
+		{"validation", ErrInvalidScope, http.StatusBadRequest, "scope must be"},
+		{"database error", errors.New(`ERROR: relation "calendar_feeds" does not exist (SQLSTATE 42P01)`), http.StatusInternalServerError, ""},
+		{"message ending in not found", errors.New("row not found"), http.StatusInternalServerError, ""},
+		{"message starting with only", errors.New("only one connection allowed"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := rec.Body.String()
+			if tt.wantBody != "" && !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body %s does not contain %q", body, tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(body, tt.err.Error()) {
+				t.Errorf("internal error leaked to the client: %s", body)
+			}
+		})
+	}
+}
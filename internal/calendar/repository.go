@@ -0,0 +1,266 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Lookup errors
+var (
+	ErrFeedNotFound         = errors.New("feed not found")
+	ErrJurisdictionNotFound = errors.New("jurisdiction not found")
+)
+
+// eventRow is an event as shown in a feed
+type eventRow struct {
+	ID          uuid.UUID
+	Title       string
+	Description *string
+	Location    *string
+	StartTime   time.Time
+	EndTime     *time.Time
+	Sequence    int
+	Cancelled   bool
+	RSVP        *string // Feed owner's answer, on user feeds
+	UpdatedAt   time.Time
+}
+
+// taskRow is a task due date as shown in a feed
+type taskRow struct {
+	ID          uuid.UUID
+	Title       string
+	Description *string
+	DueDate     time.Time
+	Status      string
+	Sequence    int
+	Cancelled   bool
+	UpdatedAt   time.Time
+}
+
+// Repository handles database operations for calendar feeds
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new calendar repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// FEEDS
+
+const feedColumns = `id, user_id, COALESCE(name, ''), scope, jurisdiction_id, include_tasks, last_used_at, created_at, revoked_at`
+
+func scanFeed(row pgx.Row, f *models.CalendarFeed) error {
+	return row.Scan(&f.ID, &f.UserID, &f.Name, &f.Scope, &f.JurisdictionID, &f.IncludeTasks, &f.LastUsedAt, &f.CreatedAt, &f.RevokedAt)
+}
+
+// CreateFeed stores a feed under the hash of its token
+func (r *Repository) CreateFeed(ctx context.Context, f *models.CalendarFeed, tokenHash string) error {
+	query := `
+		INSERT INTO calendar_feeds (user_id, name, scope, jurisdiction_id, include_tasks, token_hash)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, query, f.UserID, f.Name, f.Scope, f.JurisdictionID, f.IncludeTasks, tokenHash).
+		Scan(&f.ID, &f.CreatedAt)
+}
+
+// ListFeeds returns a member's active feeds
+func (r *Repository) ListFeeds(ctx context.Context, userID uuid.UUID) ([]*models.CalendarFeed, error) {
+	query := `SELECT ` + feedColumns + ` FROM calendar_feeds WHERE user_id = $1 AND revoked_at IS NULL ORDER BY created_at ASC`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.CalendarFeed
+	for rows.Next() {
+		var f models.CalendarFeed
+		if err := scanFeed(rows, &f); err != nil {
+			return nil, err
+		}
+		list = append(list, &f)
+	}
+	return list, rows.Err()
+}
+
+// CountFeeds returns how many active feeds a member has
+func (r *Repository) CountFeeds(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM calendar_feeds WHERE user_id = $1 AND revoked_at IS NULL", userID).Scan(&n)
+	return n, err
+}
+
+// RevokeFeed disables a member's feed
+func (r *Repository) RevokeFeed(ctx context.Context, id, userID uuid.UUID) error {
+	query := `UPDATE calendar_feeds SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrFeedNotFound
+	}
+	return nil
+}
+
+// GetFeedByToken returns the active feed with the given token hash whose owner is still active,
+// and records the access (at most hourly, as clients poll often)
+func (r *Repository) GetFeedByToken(ctx context.Context, tokenHash string) (*models.CalendarFeed, error) {
+	query := `
+		SELECT f.id, f.user_id, COALESCE(f.name, ''), f.scope, f.jurisdiction_id, f.include_tasks, f.last_used_at, f.created_at, f.revoked_at
+		FROM calendar_feeds f
+		JOIN users u ON f.user_id = u.id
+		WHERE f.token_hash = $1 AND f.revoked_at IS NULL AND u.deleted_at IS NULL AND u.is_active = TRUE
+	`
+	var f models.CalendarFeed
+	err := scanFeed(r.db.QueryRow(ctx, query, tokenHash), &f)
+	if err == pgx.ErrNoRows {
+		return nil, ErrFeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	touch := `
+		UPDATE calendar_feeds SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 hour')
+	`
+	if _, err := r.db.Exec(ctx, touch, f.ID); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// ENTRIES
+
+// Events and tasks that were cancelled or deleted stay in feeds for a while as cancelled, so
+// subscribed calendars remove them
+const eventSelect = `
+	SELECT e.id, e.title, e.description, e.location, e.start_time, e.end_time, e.sequence,
+	       e.deleted_at IS NOT NULL, %s, COALESCE(e.updated_at, e.created_at, NOW())
+	FROM events e
+`
+
+const eventWindow = `
+	e.start_time BETWEEN $2 AND $3
+	AND (e.deleted_at IS NULL OR e.deleted_at > NOW() - INTERVAL '30 days')
+`
+
+// ListUserEvents returns the events a member organizes or is invited to and has not declined
+func (r *Repository) ListUserEvents(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*eventRow, error) {
+	query := fmt.Sprintf(eventSelect, "i.rsvp") + `
+		LEFT JOIN event_invitations i ON i.event_id = e.id AND i.user_id = $1
+		WHERE (e.organizer_id = $1 OR (i.id IS NOT NULL AND i.rsvp <> 'no'))
+		  AND ` + eventWindow + `
+		ORDER BY e.start_time ASC
+	`
+	return r.queryEvents(ctx, query, userID, from, to)
+}
+
+// ListJurisdictionEvents returns the events held in a jurisdiction and below it
+func (r *Repository) ListJurisdictionEvents(ctx context.Context, jurisdictionID uuid.UUID, publicOnly bool, from, to time.Time) ([]*eventRow, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT j.id FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+			WHERE j.deleted_at IS NULL
+		)
+	` + fmt.Sprintf(eventSelect, "NULL::varchar") + `
+		WHERE e.jurisdiction_id IN (SELECT id FROM subtree)
+		  AND (NOT $4 OR e.is_public = TRUE)
+		  AND ` + eventWindow + `
+		ORDER BY e.start_time ASC
+	`
+	return r.queryEvents(ctx, query, jurisdictionID, from, to, publicOnly)
+}
+
+func (r *Repository) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*eventRow, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*eventRow
+	for rows.Next() {
+		var e eventRow
+		err := rows.Scan(
+			&e.ID, &e.Title, &e.Description, &e.Location, &e.StartTime, &e.EndTime, &e.Sequence,
+			&e.Cancelled, &e.RSVP, &e.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	return list, rows.Err()
+}
+
+const taskSelect = `
+	SELECT t.id, t.title, t.description, t.due_date, t.status::text, t.sequence,
+	       t.deleted_at IS NOT NULL OR t.status = 'cancelled', COALESCE(t.updated_at, t.created_at, NOW())
+	FROM tasks t
+	WHERE t.due_date BETWEEN $2 AND $3
+	  AND (t.deleted_at IS NULL OR t.deleted_at > NOW() - INTERVAL '30 days')
+`
+
+// ListUserTasks returns the due dates of tasks assigned to a member directly or through a committee
+func (r *Repository) ListUserTasks(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*taskRow, error) {
+	query := taskSelect + `
+		  AND (t.assignee_id = $1 OR EXISTS (
+			SELECT 1 FROM task_assignments ta WHERE ta.task_id = t.id AND ta.user_id = $1
+		  ))
+		ORDER BY t.due_date ASC
+	`
+	return r.queryTasks(ctx, query, userID, from, to)
+}
+
+// ListJurisdictionTasks returns the due dates of tasks in a jurisdiction
+func (r *Repository) ListJurisdictionTasks(ctx context.Context, jurisdictionID uuid.UUID, from, to time.Time) ([]*taskRow, error) {
+	query := taskSelect + `
+		  AND t.jurisdiction_id = $1
+		ORDER BY t.due_date ASC
+	`
+	return r.queryTasks(ctx, query, jurisdictionID, from, to)
+}
+
+func (r *Repository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*taskRow, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*taskRow
+	for rows.Next() {
+		var t taskRow
+		err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.DueDate, &t.Status, &t.Sequence, &t.Cancelled, &t.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &t)
+	}
+	return list, rows.Err()
+}
+
+// GetJurisdictionName returns a jurisdiction's name for feed titles
+func (r *Repository) GetJurisdictionName(ctx context.Context, id uuid.UUID) (string, error) {
+	var name string
+	err := r.db.QueryRow(ctx, "SELECT name FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL", id).Scan(&name)
+	if err == pgx.ErrNoRows {
+		return "", ErrJurisdictionNotFound
+	}
+	return name, err
+}
@@ -0,0 +1,257 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/auth"
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/ical"
+	"github.com/google/uuid"
+)
+
+// Feed settings
+const (
+	MaxFeedsPerUser = 10
+	MaxNameLength   = 100
+	RefreshInterval = time.Hour
+	pastWindow      = 30 * 24 * time.Hour  // How far back entries are published
+	futureWindow    = 365 * 24 * time.Hour // How far ahead entries are published
+	prodID          = "-//BJDMS//Calendar//EN"
+	uidDomain       = "bjdms"
+)
+
+// Feed errors
+var (
+	ErrNameTooLong          = errors.New("name is too long")
+	ErrJurisdictionRequired = errors.New("jurisdiction_id is required for a jurisdiction feed")
+	ErrInvalidScope         = errors.New(`scope must be "user" or "jurisdiction"`)
+	ErrTooManyFeeds         = errors.New("too many calendar feeds")
+	ErrFeedAccess           = errors.New("only members at or above a jurisdiction can subscribe to its calendar")
+)
+
+// JurisdictionChecker resolves jurisdiction hierarchy (implemented by committee.Service)
+type JurisdictionChecker interface {
+	IsChildJurisdiction(ctx context.Context, parentID, targetID uuid.UUID) (bool, error)
+}
+
+// Service handles business logic for calendar feeds
+type Service struct {
+	repo     *Repository
+	authRepo *auth.Repository
+	org      JurisdictionChecker
+}
+
+// NewService creates a new calendar service
+func NewService(repo *Repository, authRepo *auth.Repository, org JurisdictionChecker) *Service {
+	return &Service{repo: repo, authRepo: authRepo, org: org}
+}
+
+// CreateFeed creates a feed and returns it with its token, which is not stored and cannot be
+// shown again
+func (s *Service) CreateFeed(ctx context.Context, f *models.CalendarFeed) error {
+	// 1. Validate
+	f.Name = strings.TrimSpace(f.Name)
+	if len(f.Name) > MaxNameLength {
+		return fmt.Errorf("%w: at most %d characters", ErrNameTooLong, MaxNameLength)
+	}
+	switch f.Scope {
+	case "":
+		f.Scope = models.FeedScopeUser
+		fallthrough
+	case models.FeedScopeUser:
+		f.JurisdictionID = nil
+	case models.FeedScopeJurisdiction:
+		if f.JurisdictionID == nil {
+			return ErrJurisdictionRequired
+		}
+		if err := s.checkJurisdictionAccess(ctx, f.UserID, *f.JurisdictionID); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidScope
+	}
+
+	count, err := s.repo.CountFeeds(ctx, f.UserID)
+	if err != nil {
+		return err
+	}
+	if count >= MaxFeedsPerUser {
+		return fmt.Errorf("%w: at most %d are allowed; revoke one first", ErrTooManyFeeds, MaxFeedsPerUser)
+	}
+
+	// 2. Issue the token
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	if err := s.repo.CreateFeed(ctx, f, hashToken(token)); err != nil {
+		return err
+	}
+	f.Token = token
+	return nil
+}
+
+// ListFeeds returns a member's active feeds
+func (s *Service) ListFeeds(ctx context.Context, userID uuid.UUID) ([]*models.CalendarFeed, error) {
+	return s.repo.ListFeeds(ctx, userID)
+}
+
+// RevokeFeed disables a feed; subscribed calendars stop updating
+func (s *Service) RevokeFeed(ctx context.Context, id, userID uuid.UUID) error {
+	return s.repo.RevokeFeed(ctx, id, userID)
+}
+
+// RenderFeed builds the calendar for a feed token
+func (s *Service) RenderFeed(ctx context.Context, token string) (*ical.Calendar, error) {
+	f, err := s.repo.GetFeedByToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from, to := now.Add(-pastWindow), now.Add(futureWindow)
+	cal := &ical.Calendar{ProdID: prodID, Name: f.Name, Refresh: RefreshInterval}
+
+	var events []*eventRow
+	var tasks []*taskRow
+	switch f.Scope {
+	case models.FeedScopeJurisdiction:
+		// Access may have been lost since the feed was created
+		if err := s.checkJurisdictionAccess(ctx, f.UserID, *f.JurisdictionID); errors.Is(err, ErrFeedAccess) {
+			return nil, ErrFeedNotFound
+		} else if err != nil {
+			return nil, err
+		}
+		if events, err = s.repo.ListJurisdictionEvents(ctx, *f.JurisdictionID, false, from, to); err != nil {
+			return nil, err
+		}
+		if f.IncludeTasks {
+			if tasks, err = s.repo.ListJurisdictionTasks(ctx, *f.JurisdictionID, from, to); err != nil {
+				return nil, err
+			}
+		}
+		if cal.Name == "" {
+			if cal.Name, err = s.repo.GetJurisdictionName(ctx, *f.JurisdictionID); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if events, err = s.repo.ListUserEvents(ctx, f.UserID, from, to); err != nil {
+			return nil, err
+		}
+		if f.IncludeTasks {
+			if tasks, err = s.repo.ListUserTasks(ctx, f.UserID, from, to); err != nil {
+				return nil, err
+			}
+		}
+		if cal.Name == "" {
+			cal.Name = "My Calendar"
+		}
+	}
+
+	appendEvents(cal, events)
+	appendTasks(cal, tasks)
+	return cal, nil
+}
+
+// RenderPublicCalendar builds the unauthenticated calendar of a jurisdiction's public events
+func (s *Service) RenderPublicCalendar(ctx context.Context, jurisdictionID uuid.UUID) (*ical.Calendar, error) {
+	name, err := s.repo.GetJurisdictionName(ctx, jurisdictionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events, err := s.repo.ListJurisdictionEvents(ctx, jurisdictionID, true, now.Add(-pastWindow), now.Add(futureWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &ical.Calendar{ProdID: prodID, Name: name, Refresh: RefreshInterval}
+	appendEvents(cal, events)
+	return cal, nil
+}
+
+// checkJurisdictionAccess allows the Super Admin and members at or above the jurisdiction
+func (s *Service) checkJurisdictionAccess(ctx context.Context, userID, jurisdictionID uuid.UUID) error {
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if authority.SuperAdmin {
+		return nil
+	}
+	if authority.JurisdictionID != nil {
+		ok, err := s.org.IsChildJurisdiction(ctx, *authority.JurisdictionID, jurisdictionID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return ErrFeedAccess
+}
+
+func appendEvents(cal *ical.Calendar, events []*eventRow) {
+	for _, e := range events {
+		ev := &ical.Event{
+			UID:      fmt.Sprintf("event-%s@%s", e.ID, uidDomain),
+			Sequence: e.Sequence,
+			Start:    e.StartTime,
+			End:      e.EndTime,
+			Summary:  e.Title,
+			Status:   ical.StatusConfirmed,
+			Updated:  e.UpdatedAt,
+		}
+		if e.Description != nil {
+			ev.Description = *e.Description
+		}
+		if e.Location != nil {
+			ev.Location = *e.Location
+		}
+		switch {
+		case e.Cancelled:
+			ev.Status = ical.StatusCancelled
+		case e.RSVP != nil && (*e.RSVP == models.RSVPMaybe || *e.RSVP == models.RSVPWaitlisted || *e.RSVP == models.RSVPPending):
+			ev.Status = ical.StatusTentative
+		}
+		cal.Events = append(cal.Events, ev)
+	}
+}
+
+// appendTasks publishes due dates as point-in-time entries
+func appendTasks(cal *ical.Calendar, tasks []*taskRow) {
+	for _, t := range tasks {
+		ev := &ical.Event{
+			UID:      fmt.Sprintf("task-%s@%s", t.ID, uidDomain),
+			Sequence: t.Sequence,
+			Start:    t.DueDate,
+			Summary:  "Due: " + t.Title,
+			Status:   ical.StatusConfirmed,
+			Updated:  t.UpdatedAt,
+		}
+		if t.Description != nil {
+			ev.Description = *t.Description
+		}
+		if t.Cancelled {
+			ev.Status = ical.StatusCancelled
+		}
+		cal.Events = append(cal.Events, ev)
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
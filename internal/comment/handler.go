@@ -0,0 +1,186 @@
+package comment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bjdms/api/internal/middleware"
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// pathEntities maps the record segment of a thread URL to its entity type
+var pathEntities = map[string]string{
+	"tasks":         models.CommentEntityTask,
+	"activities":    models.CommentEntityActivity,
+	"complaints":    models.CommentEntityComplaint,
+	"join-requests": models.CommentEntityJoinRequest,
+}
+
+// Handler handles comment HTTP endpoints
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new comment handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Routes returns routes for comments
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/{entity}/{entity_id}", h.List)
+	r.Post("/{entity}/{entity_id}", h.Create)
+	r.Patch("/{id}", h.Edit)
+	r.Delete("/{id}", h.Delete)
+
+	return r
+}
+
+// List handles GET /api/v1/comments/{entity}/{entity_id}
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	entityType, entityID, ok := parseThread(w, r)
+	if !ok {
+		return
+	}
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+
+	list, err := h.service.ListComments(r.Context(), entityType, entityID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// Create handles POST /api/v1/comments/{entity}/{entity_id}
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	entityType, entityID, ok := parseThread(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Body       string     `json:"body"`
+		ParentID   *uuid.UUID `json:"parent_id"`
+		Visibility string     `json:"visibility"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	authorID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	c := &models.Comment{
+		EntityType: entityType,
+		EntityID:   entityID,
+		ParentID:   req.ParentID,
+		AuthorID:   &authorID,
+		Body:       req.Body,
+		Visibility: req.Visibility,
+	}
+	if err := h.service.AddComment(r.Context(), c); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, c, "Comment posted")
+}
+
+// Edit handles PATCH /api/v1/comments/{id}
+func (h *Handler) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid comment ID")
+		return
+	}
+
+	var req struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	c, err := h.service.EditComment(r.Context(), id, userID, req.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, c, "Comment updated")
+}
+
+// Delete handles DELETE /api/v1/comments/{id}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid comment ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.DeleteComment(r.Context(), id, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Comment deleted")
+}
+
+// parseThread reads the record a thread URL points at, writing the error response if it is invalid
+func parseThread(w http.ResponseWriter, r *http.Request) (string, uuid.UUID, bool) {
+	entityType, ok := pathEntities[chi.URLParam(r, "entity")]
+	if !ok {
+		response.NotFound(w, "Comments are not available on this resource")
+		return "", uuid.Nil, false
+	}
+	entityID, err := uuid.Parse(chi.URLParam(r, "entity_id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid record ID")
+		return "", uuid.Nil, false
+	}
+	return entityType, entityID, true
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case matchesAny(err, notFoundErrors):
+		response.NotFound(w, err.Error())
+	case matchesAny(err, forbiddenErrors):
+		response.Forbidden(w, err.Error())
+	case matchesAny(err, invalidErrors):
+		response.BadRequest(w, err.Error())
+	default:
+		// Database and other internal failures are not shown to the client
+		response.InternalError(w, "Failed to process comment request", "")
+	}
+}
+
+// Service errors by response status; anything else is an internal error
+var (
+	notFoundErrors  = []error{ErrRecordNotFound, ErrCommentNotFound}
+	forbiddenErrors = []error{ErrNotOfficial, ErrNotParticipant, ErrNotAuthor, ErrDeleteAccess}
+	invalidErrors   = []error{
+		ErrUnsupportedEntity, ErrOtherThread, ErrReplyToDeleted, ErrInternalReply, ErrInternalUnsupported,
+		ErrInvalidVisibility, ErrBodyRequired, ErrBodyTooLong, ErrTooManyMentions, ErrEditWindowClosed,
+	}
+)
+
+func matchesAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package comment
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"comment not found", ErrCommentNotFound, http.StatusNotFound, ErrCommentNotFound.Error()},
+		{"record not found", fmt.Errorf("%s: %w", "complaint", ErrRecordNotFound), http.StatusNotFound, "complaint: record not found"},
+		{"not an official", fmt.Errorf("cannot access this join request's comments: %w", ErrNotOfficial), http.StatusForbidden, "cannot access this join request's comments"},
+		{"not a participant", ErrNotParticipant, http.StatusForbidden, ErrNotParticipant.Error()},
+		{"not the author", ErrNotAuthor, http.StatusForbidden, ErrNotAuthor.Error()},
+		{"edit window", ErrEditWindowClosed, http.StatusBadRequest, "minutes of posting"},
+		{"validation", ErrBodyRequired, http.StatusBadRequest, ErrBodyRequired.Error()},
+		{"database error", errors.New(`ERROR: relation "comment_mentions" does not exist (SQLSTATE 42P01)`), http.StatusInternalServerError, ""},
+		{"message ending in not found", errors.New("row not found"), http.StatusInternalServerError, ""},
+		{"message starting with only", errors.New("only one connection allowed"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := rec.Body.String()
+			if tt.wantBody != "" && !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body %s does not contain %q", body, tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(body, tt.err.Error()) {
+				t.Errorf("internal error leaked to the client: %s", body)
+			}
+		})
+	}
+}
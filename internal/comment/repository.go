@@ -0,0 +1,240 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Lookup errors
+var (
+	ErrUnsupportedEntity = errors.New("comments are not supported on this kind of record")
+	ErrRecordNotFound    = errors.New("record not found")
+	ErrCommentNotFound   = errors.New("comment not found")
+)
+
+// Target is the record a comment thread belongs to
+type Target struct {
+	JurisdictionID uuid.UUID
+	OwnerID        *uuid.UUID  // Complainant with an account; they may follow the public thread
+	Excluded       []uuid.UUID // Members a complaint names; its thread is hidden from them
+}
+
+// targetQueries resolve each commentable record to its jurisdiction, owner and excluded users
+var targetQueries = map[string]string{
+	models.CommentEntityTask:     `SELECT jurisdiction_id, NULL::uuid, '{}'::uuid[] FROM tasks WHERE id = $1 AND deleted_at IS NULL`,
+	models.CommentEntityActivity: `SELECT jurisdiction_id, NULL::uuid, '{}'::uuid[] FROM activities WHERE id = $1 AND deleted_at IS NULL`,
+	models.CommentEntityComplaint: `
+		SELECT jurisdiction_id, user_id,
+		       ARRAY(SELECT a.user_id FROM complaint_accused a WHERE a.complaint_id = complaints.id AND a.user_id IS NOT NULL)
+		FROM complaints WHERE id = $1 AND deleted_at IS NULL
+	`,
+	models.CommentEntityJoinRequest: `SELECT jurisdiction_id, NULL::uuid, '{}'::uuid[] FROM join_requests WHERE id = $1`,
+}
+
+// auditQueries record a comment in the record's own audit trail, where it has one
+var auditQueries = map[string]string{
+	models.CommentEntityComplaint: `
+		INSERT INTO complaint_logs (complaint_id, user_id, action, note)
+		VALUES ($1, $2, 'comment', $3)
+	`,
+	models.CommentEntityJoinRequest: `
+		INSERT INTO join_request_logs (request_id, actor_id, action, note)
+		VALUES ($1, $2, 'comment', $3)
+	`,
+}
+
+const commentColumns = `
+	c.id, c.entity_type, c.entity_id, c.parent_id, c.author_id, c.body, c.visibility,
+	c.edited_at, c.created_at, c.updated_at, c.deleted_at, COALESCE(u.full_name, ''),
+	COALESCE((SELECT array_agg(m.user_id ORDER BY m.created_at) FROM comment_mentions m WHERE m.comment_id = c.id), '{}')
+`
+
+// Repository handles database operations for comments
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new comment repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// GetTarget looks up the record a thread is attached to
+func (r *Repository) GetTarget(ctx context.Context, entityType string, entityID uuid.UUID) (*Target, error) {
+	query, ok := targetQueries[entityType]
+	if !ok {
+		return nil, fmt.Errorf("entity type %q: %w", entityType, ErrUnsupportedEntity)
+	}
+	var t Target
+	err := r.db.QueryRow(ctx, query, entityID).Scan(&t.JurisdictionID, &t.OwnerID, &t.Excluded)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%s: %w", entityLabels[entityType], ErrRecordNotFound)
+	}
+	return &t, err
+}
+
+// Create inserts a comment with its mentions and audit entry in a transaction
+func (r *Repository) Create(ctx context.Context, c *models.Comment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Insert the comment
+	query := `
+		INSERT INTO comments (entity_type, entity_id, parent_id, author_id, body, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, c.EntityType, c.EntityID, c.ParentID, c.AuthorID, c.Body, c.Visibility).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// 2. Remember who was mentioned
+	if err := insertMentions(ctx, tx, c.ID, c.Mentions); err != nil {
+		return err
+	}
+
+	// 3. Audit trail of the commented record
+	if audit, ok := auditQueries[c.EntityType]; ok {
+		if _, err := tx.Exec(ctx, audit, c.EntityID, c.AuthorID, c.Body); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByID returns a comment, including deleted ones
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments c LEFT JOIN users u ON c.author_id = u.id WHERE c.id = $1`
+	var c models.Comment
+	err := scanComment(r.db.QueryRow(ctx, query, id), &c)
+	if err == pgx.ErrNoRows {
+		return nil, ErrCommentNotFound
+	}
+	return &c, err
+}
+
+// List returns a record's comments oldest first, optionally including internal ones
+func (r *Repository) List(ctx context.Context, entityType string, entityID uuid.UUID, includeInternal bool) ([]*models.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments c
+		LEFT JOIN users u ON c.author_id = u.id
+		WHERE c.entity_type = $1 AND c.entity_id = $2 AND ($3 OR c.visibility = 'public')
+		ORDER BY c.created_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, entityType, entityID, includeInternal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Comment
+	for rows.Next() {
+		var c models.Comment
+		if err := scanComment(rows, &c); err != nil {
+			return nil, err
+		}
+		list = append(list, &c)
+	}
+	return list, rows.Err()
+}
+
+// UpdateBody replaces a comment's text and adds newly mentioned users
+func (r *Repository) UpdateBody(ctx context.Context, id uuid.UUID, body string, added []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE comments SET body = $2, edited_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	tag, err := tx.Exec(ctx, query, id, body)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCommentNotFound
+	}
+	if err := insertMentions(ctx, tx, id, added); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Delete soft-deletes a comment
+func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE comments SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// FilterActiveUsers returns the given users that exist and are active, in the order given
+func (r *Repository) FilterActiveUsers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query := `
+		SELECT u.id FROM unnest($1::uuid[]) WITH ORDINALITY AS m(id, n)
+		JOIN users u ON u.id = m.id
+		WHERE u.deleted_at IS NULL AND u.is_active = TRUE
+		ORDER BY m.n
+	`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var active []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		active = append(active, id)
+	}
+	return active, rows.Err()
+}
+
+func insertMentions(ctx context.Context, tx pgx.Tx, commentID uuid.UUID, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	query := `
+		INSERT INTO comment_mentions (comment_id, user_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+	_, err := tx.Exec(ctx, query, commentID, userIDs)
+	return err
+}
+
+func scanComment(row pgx.Row, c *models.Comment) error {
+	err := row.Scan(
+		&c.ID, &c.EntityType, &c.EntityID, &c.ParentID, &c.AuthorID, &c.Body, &c.Visibility,
+		&c.EditedAt, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt, &c.AuthorName, &c.Mentions,
+	)
+	c.FromComplainant = c.AuthorID == nil
+	return err
+}
@@ -0,0 +1,369 @@
+package comment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/auth"
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/google/uuid"
+)
+
+// Comment limits
+const (
+	EditWindow    = 15 * time.Minute
+	MaxBodyLength = 5000
+	MaxMentions   = 20
+)
+
+// Comment errors
+var (
+	ErrOtherThread         = errors.New("parent comment belongs to another thread")
+	ErrReplyToDeleted      = errors.New("cannot reply to a deleted comment")
+	ErrInternalReply       = errors.New("replies to an internal comment must be internal")
+	ErrInternalUnsupported = errors.New("internal comments are only allowed on complaints and join requests")
+	ErrInvalidVisibility   = errors.New(`visibility must be "public" or "internal"`)
+	ErrBodyRequired        = errors.New("comment body is required")
+	ErrBodyTooLong         = errors.New("comment body is too long")
+	ErrTooManyMentions     = errors.New("too many mentions")
+	ErrEditWindowClosed    = fmt.Errorf("comments can only be edited within %d minutes of posting", int(EditWindow.Minutes()))
+)
+
+// Access errors
+var (
+	ErrNotOfficial    = errors.New("only officials of the record's jurisdiction can do this")
+	ErrNotParticipant = errors.New("only officials of the complaint's jurisdiction and the complainant can access its comments")
+	ErrNotAuthor      = errors.New("only the author can edit a comment")
+	ErrDeleteAccess   = errors.New("only the author or a Super Admin can delete a comment")
+)
+
+// mentionPattern matches the mention markup inserted by the clients' user picker: @[Full Name](user-id)
+var mentionPattern = regexp.MustCompile(`@\[([^\]\n]+)\]\(([0-9a-fA-F-]{36})\)`)
+
+// entityLabels name each commentable record in messages
+var entityLabels = map[string]string{
+	models.CommentEntityTask:        "task",
+	models.CommentEntityActivity:    "activity",
+	models.CommentEntityComplaint:   "complaint",
+	models.CommentEntityJoinRequest: "join request",
+}
+
+// OfficialChecker tells who handles a jurisdiction's records (implemented by complaint.Service)
+type OfficialChecker interface {
+	IsOfficial(ctx context.Context, userID, jurisdictionID uuid.UUID) (bool, error)
+}
+
+// Service handles business logic for comments
+type Service struct {
+	repo         *Repository
+	notification *notification.Service
+	authRepo     *auth.Repository
+	officials    OfficialChecker
+}
+
+// NewService creates a new comment service
+func NewService(repo *Repository, ns *notification.Service, authRepo *auth.Repository, officials OfficialChecker) *Service {
+	return &Service{repo: repo, notification: ns, authRepo: authRepo, officials: officials}
+}
+
+// ListComments returns a record's discussion as threads, hiding what the user may not see
+func (s *Service) ListComments(ctx context.Context, entityType string, entityID, userID uuid.UUID) ([]*models.Comment, error) {
+	target, err := s.repo.GetTarget(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	official, err := s.checkAccess(ctx, userID, entityType, target)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.repo.List(ctx, entityType, entityID, official)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range list {
+		maskDeleted(c)
+	}
+	return buildThreads(list), nil
+}
+
+// AddComment posts a comment or a reply and notifies the users it mentions
+func (s *Service) AddComment(ctx context.Context, c *models.Comment) error {
+	// 1. Validate the text
+	body, err := validateBody(c.Body)
+	if err != nil {
+		return err
+	}
+	c.Body = body
+
+	// 2. The author must be able to follow the thread
+	target, err := s.repo.GetTarget(ctx, c.EntityType, c.EntityID)
+	if err != nil {
+		return err
+	}
+	official, err := s.checkAccess(ctx, *c.AuthorID, c.EntityType, target)
+	if err != nil {
+		return err
+	}
+
+	// 3. Replies stay in their parent's thread and never expose an internal discussion
+	if c.ParentID != nil {
+		parent, err := s.repo.GetByID(ctx, *c.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.EntityType != c.EntityType || parent.EntityID != c.EntityID {
+			return ErrOtherThread
+		}
+		if parent.DeletedAt != nil {
+			return ErrReplyToDeleted
+		}
+		if parent.Visibility == models.CommentVisibilityInternal {
+			if c.Visibility == "" {
+				c.Visibility = models.CommentVisibilityInternal
+			} else if c.Visibility != models.CommentVisibilityInternal {
+				return ErrInternalReply
+			}
+		}
+	}
+
+	// 4. Visibility; officials discuss complaints internally unless they choose otherwise
+	if c.Visibility == "" {
+		c.Visibility = models.CommentVisibilityPublic
+		if c.EntityType == models.CommentEntityComplaint && official {
+			c.Visibility = models.CommentVisibilityInternal
+		}
+	}
+	switch c.Visibility {
+	case models.CommentVisibilityPublic:
+	case models.CommentVisibilityInternal:
+		if !supportsInternal(c.EntityType) {
+			return ErrInternalUnsupported
+		}
+		if !official {
+			return fmt.Errorf("cannot post internal comments on this %s: %w", entityLabels[c.EntityType], ErrNotOfficial)
+		}
+	default:
+		return ErrInvalidVisibility
+	}
+
+	// 5. Resolve mentions
+	c.Mentions, err = s.resolveMentions(ctx, c.Body, *c.AuthorID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		return err
+	}
+	if stored, err := s.repo.GetByID(ctx, c.ID); err == nil {
+		*c = *stored
+	}
+
+	s.notifyMentions(ctx, c, target, c.Mentions)
+	return nil
+}
+
+// EditComment changes a comment's text. Only the author may edit, and only shortly after posting.
+func (s *Service) EditComment(ctx context.Context, id, userID uuid.UUID, body string) (*models.Comment, error) {
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.DeletedAt != nil {
+		return nil, ErrCommentNotFound
+	}
+	if c.AuthorID == nil || *c.AuthorID != userID {
+		return nil, ErrNotAuthor
+	}
+	if time.Since(c.CreatedAt) > EditWindow {
+		return nil, ErrEditWindowClosed
+	}
+
+	body, err = validateBody(body)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.repo.GetTarget(ctx, c.EntityType, c.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.checkAccess(ctx, userID, c.EntityType, target); err != nil {
+		return nil, err
+	}
+
+	// Only users mentioned for the first time are notified
+	mentions, err := s.resolveMentions(ctx, body, userID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uuid.UUID]bool, len(c.Mentions))
+	for _, m := range c.Mentions {
+		seen[m] = true
+	}
+	var added []uuid.UUID
+	for _, m := range mentions {
+		if !seen[m] {
+			added = append(added, m)
+		}
+	}
+
+	if err := s.repo.UpdateBody(ctx, id, body, added); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(ctx, updated, target, added)
+	return updated, nil
+}
+
+// DeleteComment removes a comment's text; replies stay attached under a placeholder.
+// The author or a Super Admin may delete.
+func (s *Service) DeleteComment(ctx context.Context, id, userID uuid.UUID) error {
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.DeletedAt != nil {
+		return ErrCommentNotFound
+	}
+	if c.AuthorID == nil || *c.AuthorID != userID {
+		authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !authority.SuperAdmin {
+			return ErrDeleteAccess
+		}
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// checkAccess tells whether the user may follow a record's thread, and whether they take part
+// as an official of its jurisdiction. Task and activity threads are open to every member;
+// complaint threads to officials and the complainant; join request threads to officials only.
+// Users a record excludes are told it does not exist.
+func (s *Service) checkAccess(ctx context.Context, userID uuid.UUID, entityType string, target *Target) (bool, error) {
+	for _, id := range target.Excluded {
+		if id == userID {
+			return false, fmt.Errorf("%s: %w", entityLabels[entityType], ErrRecordNotFound)
+		}
+	}
+
+	official, err := s.officials.IsOfficial(ctx, userID, target.JurisdictionID)
+	if err != nil {
+		return false, err
+	}
+
+	switch entityType {
+	case models.CommentEntityComplaint:
+		if !official && (target.OwnerID == nil || *target.OwnerID != userID) {
+			return false, ErrNotParticipant
+		}
+	case models.CommentEntityJoinRequest:
+		if !official {
+			return false, fmt.Errorf("cannot access this join request's comments: %w", ErrNotOfficial)
+		}
+	}
+	return official, nil
+}
+
+// resolveMentions returns the distinct active users mentioned in a body, without the author
+func (s *Service) resolveMentions(ctx context.Context, body string, authorID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		id, err := uuid.Parse(m[2])
+		if err != nil || id == authorID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) > MaxMentions {
+		return nil, fmt.Errorf("%w: a comment can mention at most %d users", ErrTooManyMentions, MaxMentions)
+	}
+	return s.repo.FilterActiveUsers(ctx, ids)
+}
+
+// notifyMentions alerts mentioned users who are allowed to read the comment
+func (s *Service) notifyMentions(ctx context.Context, c *models.Comment, target *Target, userIDs []uuid.UUID) {
+	if len(userIDs) == 0 {
+		return
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"entity_type": c.EntityType,
+		"entity_id":   c.EntityID,
+		"comment_id":  c.ID,
+	})
+
+	for _, userID := range userIDs {
+		official, err := s.checkAccess(ctx, userID, c.EntityType, target)
+		if err != nil || (c.Visibility == models.CommentVisibilityInternal && !official) {
+			continue
+		}
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         userID,
+			Type:           notification.TypeCommentMention,
+			Title:          "You Were Mentioned",
+			Message:        fmt.Sprintf("%s mentioned you in a comment on a %s.", c.AuthorName, entityLabels[c.EntityType]),
+			Data:           data,
+			JurisdictionID: target.JurisdictionID,
+		})
+	}
+}
+
+func validateBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", ErrBodyRequired
+	}
+	if len([]rune(body)) > MaxBodyLength {
+		return "", fmt.Errorf("%w: at most %d characters", ErrBodyTooLong, MaxBodyLength)
+	}
+	return body, nil
+}
+
+func supportsInternal(entityType string) bool {
+	return entityType == models.CommentEntityComplaint || entityType == models.CommentEntityJoinRequest
+}
+
+// maskDeleted withholds the content of a deleted comment
+func maskDeleted(c *models.Comment) {
+	if c.DeletedAt == nil {
+		return
+	}
+	c.IsDeleted = true
+	c.Body = ""
+	c.Mentions = nil
+}
+
+// buildThreads nests replies under their parents; the input is ordered oldest first.
+// A reply whose parent is not visible to the user is shown at the top level.
+func buildThreads(list []*models.Comment) []*models.Comment {
+	byID := make(map[uuid.UUID]*models.Comment, len(list))
+	for _, c := range list {
+		byID[c.ID] = c
+	}
+
+	roots := []*models.Comment{}
+	for _, c := range list {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Replies = append(parent.Replies, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
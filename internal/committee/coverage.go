@@ -0,0 +1,188 @@
+package committee
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Coverage report caching. Every membership or hierarchy change bumps the version key,
+// which orphans all cached reports at once; the TTL bounds staleness of convener overdue status.
+const (
+	coverageVersionKey = "committee:coverage:version"
+	coverageCacheTTL   = 15 * time.Minute
+)
+
+// GetCoverageReport returns the vacancy and coverage report for a jurisdiction subtree.
+// Issues are limited to the given depth below the root (negative for the whole subtree);
+// the summary and per-child totals always cover the full subtree.
+func (s *Service) GetCoverageReport(ctx context.Context, rootID uuid.UUID, depth int) (*models.CoverageReport, error) {
+	report, err := s.coverageReport(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	if depth >= 0 {
+		filtered := make([]*models.CoverageRow, 0, len(report.Issues))
+		for _, row := range report.Issues {
+			if row.Depth <= depth {
+				filtered = append(filtered, row)
+			}
+		}
+		report.Issues = filtered
+	}
+	return report, nil
+}
+
+func (s *Service) coverageReport(ctx context.Context, rootID uuid.UUID) (*models.CoverageReport, error) {
+	key := s.coverageCacheKey(ctx, rootID)
+	if key != "" {
+		if cached, err := s.redis.Get(ctx, key).Bytes(); err == nil {
+			var report models.CoverageReport
+			if err := json.Unmarshal(cached, &report); err == nil {
+				return &report, nil
+			}
+		}
+	}
+
+	rows, err := s.repo.ListCoverageRows(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrJurisdictionNotFound
+	}
+
+	report := buildCoverageReport(rows)
+
+	if key != "" {
+		if payload, err := json.Marshal(report); err == nil {
+			if err := s.redis.Set(ctx, key, payload, coverageCacheTTL).Err(); err != nil {
+				log.Printf("coverage report cache write failed: %v", err)
+			}
+		}
+	}
+	return report, nil
+}
+
+// buildCoverageReport aggregates breadth-first rows (root first) into a report
+func buildCoverageReport(rows []*models.CoverageRow) *models.CoverageReport {
+	report := &models.CoverageReport{
+		Root:        rows[0],
+		GeneratedAt: time.Now(),
+		Summary:     &models.CoverageSummary{},
+		Children:    []*models.CoverageChild{},
+		Issues:      []*models.CoverageRow{},
+	}
+
+	// Map every node to the root child whose subtree contains it
+	branch := make(map[uuid.UUID]*models.CoverageChild)
+	for _, row := range rows {
+		var child *models.CoverageChild
+		switch {
+		case row.Depth == 1:
+			child = &models.CoverageChild{Row: row, Subtree: &models.CoverageSummary{}}
+			report.Children = append(report.Children, child)
+		case row.Depth > 1 && row.ParentID != nil:
+			child = branch[*row.ParentID]
+		}
+		if child != nil {
+			branch[row.JurisdictionID] = child
+			child.Subtree.Add(row)
+		}
+
+		report.Summary.Add(row)
+		if row.HasIssue() {
+			report.Issues = append(report.Issues, row)
+		}
+	}
+	return report
+}
+
+// coverageCacheKey returns the versioned cache key, or "" when caching is unavailable
+func (s *Service) coverageCacheKey(ctx context.Context, rootID uuid.UUID) string {
+	if s.redis == nil {
+		return ""
+	}
+	version, err := s.redis.Get(ctx, coverageVersionKey).Int64()
+	if err != nil && err != redis.Nil {
+		return ""
+	}
+	return fmt.Sprintf("committee:coverage:%d:%s", version, rootID)
+}
+
+// invalidateCoverage drops all cached coverage reports after a membership, hierarchy, position
+// or committee structure change
+func (s *Service) invalidateCoverage(ctx context.Context) {
+	if s.redis == nil {
+		return
+	}
+	if err := s.redis.Incr(ctx, coverageVersionKey).Err(); err != nil {
+		log.Printf("coverage report cache invalidation failed: %v", err)
+	}
+}
+
+// WriteCoverageCSV writes the report's issue rows as CSV
+func WriteCoverageCSV(w io.Writer, report *models.CoverageReport) error {
+	cw := csv.NewWriter(w)
+	header := []string{
+		"jurisdiction_id", "parent_id", "name", "name_bn", "level", "depth",
+		"committee_id", "committee_type", "member_count", "has_active_committee",
+		"missing_president", "missing_general_secretary", "convener_overdue", "overdue_days", "expires_at",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range report.Issues {
+		record := []string{
+			row.JurisdictionID.String(),
+			uuidString(row.ParentID),
+			row.Name,
+			stringValue(row.NameBn),
+			row.Level,
+			strconv.Itoa(row.Depth),
+			uuidString(row.CommitteeID),
+			row.CommitteeType,
+			strconv.Itoa(row.MemberCount),
+			strconv.FormatBool(row.HasActiveCommittee),
+			strconv.FormatBool(row.MissingPresident),
+			strconv.FormatBool(row.MissingSecretary),
+			strconv.FormatBool(row.ConvenerOverdue),
+			strconv.Itoa(row.OverdueDays),
+			"",
+		}
+		if row.ExpiresAt != nil {
+			record[len(record)-1] = row.ExpiresAt.Format("2006-01-02")
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,253 @@
+package committee
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// GeoJSON types accepted for jurisdiction boundaries
+const (
+	GeoTypePolygon           = "Polygon"
+	GeoTypeMultiPolygon      = "MultiPolygon"
+	GeoTypeFeature           = "Feature"
+	GeoTypeFeatureCollection = "FeatureCollection"
+)
+
+// Geometry is a minimal GeoJSON geometry object
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// Feature is a GeoJSON feature
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// FeatureCollection is a GeoJSON feature collection
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+// BBox is an axis-aligned bounding box in WGS84
+type BBox struct {
+	MinLat, MinLng, MaxLat, MaxLng float64
+}
+
+// Contains reports whether the point lies inside the box (inclusive)
+func (b BBox) Contains(lat, lng float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lng >= b.MinLng && lng <= b.MaxLng
+}
+
+// ring is a closed sequence of [lng, lat] positions
+type ring [][2]float64
+
+// polygon is an outer ring followed by optional holes
+type polygon []ring
+
+// Shape is a parsed (multi)polygon ready for spatial tests
+type Shape struct {
+	polygons []polygon
+}
+
+// ParseBoundary parses a GeoJSON Polygon/MultiPolygon geometry, or a Feature wrapping one.
+// Extra coordinate dimensions (altitude) produced by shapefile converters are ignored.
+func ParseBoundary(raw json.RawMessage) (*Shape, json.RawMessage, error) {
+	var probe struct {
+		Type     string    `json:"type"`
+		Geometry *Geometry `json:"geometry"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return nil, nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	var g Geometry
+	switch probe.Type {
+	case GeoTypeFeature:
+		if probe.Geometry == nil {
+			return nil, nil, fmt.Errorf("feature has no geometry")
+		}
+		g = *probe.Geometry
+	case GeoTypePolygon, GeoTypeMultiPolygon:
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return nil, nil, fmt.Errorf("invalid GeoJSON geometry: %w", err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported GeoJSON type %q: expected Polygon, MultiPolygon or Feature", probe.Type)
+	}
+
+	shape, err := parseGeometry(&g)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Store the geometry only, normalised to 2D coordinates
+	normalised, err := json.Marshal(shape.geometry())
+	if err != nil {
+		return nil, nil, err
+	}
+	return shape, normalised, nil
+}
+
+func parseGeometry(g *Geometry) (*Shape, error) {
+	var shape Shape
+	switch g.Type {
+	case GeoTypePolygon:
+		var coords [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("invalid polygon coordinates: %w", err)
+		}
+		p, err := toPolygon(coords)
+		if err != nil {
+			return nil, err
+		}
+		shape.polygons = append(shape.polygons, p)
+	case GeoTypeMultiPolygon:
+		var coords [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("invalid multipolygon coordinates: %w", err)
+		}
+		for _, pc := range coords {
+			p, err := toPolygon(pc)
+			if err != nil {
+				return nil, err
+			}
+			shape.polygons = append(shape.polygons, p)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
+
+	if len(shape.polygons) == 0 {
+		return nil, fmt.Errorf("geometry has no polygons")
+	}
+	return &shape, nil
+}
+
+func toPolygon(coords [][][]float64) (polygon, error) {
+	if len(coords) == 0 {
+		return nil, fmt.Errorf("polygon has no rings")
+	}
+	p := make(polygon, 0, len(coords))
+	for _, rc := range coords {
+		if len(rc) < 4 {
+			return nil, fmt.Errorf("polygon ring must have at least 4 positions")
+		}
+		r := make(ring, 0, len(rc))
+		for _, pos := range rc {
+			if len(pos) < 2 {
+				return nil, fmt.Errorf("position must have longitude and latitude")
+			}
+			lng, lat := pos[0], pos[1]
+			if lng < -180 || lng > 180 || lat < -90 || lat > 90 {
+				return nil, fmt.Errorf("position [%f, %f] is outside WGS84 bounds", lng, lat)
+			}
+			r = append(r, [2]float64{lng, lat})
+		}
+		p = append(p, r)
+	}
+	return p, nil
+}
+
+// geometry returns the shape as a 2D GeoJSON geometry object
+func (s *Shape) geometry() map[string]interface{} {
+	if len(s.polygons) == 1 {
+		return map[string]interface{}{"type": GeoTypePolygon, "coordinates": s.polygons[0]}
+	}
+	return map[string]interface{}{"type": GeoTypeMultiPolygon, "coordinates": s.polygons}
+}
+
+// BBox returns the bounding box of all outer rings
+func (s *Shape) BBox() BBox {
+	b := BBox{MinLat: math.Inf(1), MinLng: math.Inf(1), MaxLat: math.Inf(-1), MaxLng: math.Inf(-1)}
+	for _, p := range s.polygons {
+		for _, pos := range p[0] {
+			b.MinLng = math.Min(b.MinLng, pos[0])
+			b.MaxLng = math.Max(b.MaxLng, pos[0])
+			b.MinLat = math.Min(b.MinLat, pos[1])
+			b.MaxLat = math.Max(b.MaxLat, pos[1])
+		}
+	}
+	return b
+}
+
+// Centroid returns the area-weighted centroid (lat, lng) of the shape.
+// Falls back to the bounding box centre for degenerate geometries.
+func (s *Shape) Centroid() (float64, float64) {
+	var area, cx, cy float64
+	for _, p := range s.polygons {
+		for i, r := range p {
+			a, x, y := ringCentroid(r)
+			if i > 0 {
+				// Holes subtract from the outer ring
+				a = -math.Abs(a)
+			} else {
+				a = math.Abs(a)
+			}
+			area += a
+			cx += x * a
+			cy += y * a
+		}
+	}
+	if area == 0 {
+		b := s.BBox()
+		return (b.MinLat + b.MaxLat) / 2, (b.MinLng + b.MaxLng) / 2
+	}
+	return cy / area, cx / area
+}
+
+// ringCentroid returns the signed area and centroid (x=lng, y=lat) of a ring
+func ringCentroid(r ring) (float64, float64, float64) {
+	var a, cx, cy float64
+	for i := 0; i < len(r)-1; i++ {
+		x0, y0 := r[i][0], r[i][1]
+		x1, y1 := r[i+1][0], r[i+1][1]
+		cross := x0*y1 - x1*y0
+		a += cross
+		cx += (x0 + x1) * cross
+		cy += (y0 + y1) * cross
+	}
+	a /= 2
+	if a == 0 {
+		return 0, 0, 0
+	}
+	return a, cx / (6 * a), cy / (6 * a)
+}
+
+// Contains reports whether the point lies inside the shape (holes excluded)
+func (s *Shape) Contains(lat, lng float64) bool {
+	for _, p := range s.polygons {
+		if !ringContains(p[0], lat, lng) {
+			continue
+		}
+		inHole := false
+		for _, hole := range p[1:] {
+			if ringContains(hole, lat, lng) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains uses the even-odd ray casting rule
+func ringContains(r ring, lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
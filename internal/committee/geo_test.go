@@ -0,0 +1,284 @@
+package committee
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// square returns a closed ring of [lng, lat] positions from (minLng, minLat) to (maxLng, maxLat)
+func square(minLng, minLat, maxLng, maxLat float64) [][]float64 {
+	return [][]float64{
+		{minLng, minLat}, {maxLng, minLat}, {maxLng, maxLat}, {minLng, maxLat}, {minLng, minLat},
+	}
+}
+
+func geoJSON(t *testing.T, v interface{}) json.RawMessage {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func mustParse(t *testing.T, raw json.RawMessage) *Shape {
+	t.Helper()
+	shape, _, err := ParseBoundary(raw)
+	if err != nil {
+		t.Fatalf("ParseBoundary: %v", err)
+	}
+	return shape
+}
+
+func TestParseBoundary(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     interface{}
+		wantErr string
+		want    string // Normalised geometry type
+	}{
+		{
+			name: "polygon",
+			raw:  map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{square(90, 23, 91, 24)}},
+			want: GeoTypePolygon,
+		},
+		{
+			name: "feature wrapping a polygon",
+			raw: map[string]interface{}{
+				"type":       "Feature",
+				"properties": map[string]interface{}{"name": "Dhaka"},
+				"geometry":   map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{square(90, 23, 91, 24)}},
+			},
+			want: GeoTypePolygon,
+		},
+		{
+			name: "multipolygon",
+			raw: map[string]interface{}{"type": "MultiPolygon", "coordinates": [][][][]float64{
+				{square(90, 23, 91, 24)},
+				{square(92, 23, 93, 24)},
+			}},
+			want: GeoTypeMultiPolygon,
+		},
+		{
+			name: "multipolygon with one part collapses to polygon",
+			raw:  map[string]interface{}{"type": "MultiPolygon", "coordinates": [][][][]float64{{square(90, 23, 91, 24)}}},
+			want: GeoTypePolygon,
+		},
+		{
+			name: "altitude is dropped",
+			raw: map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{
+				{90, 23, 10}, {91, 23, 10}, {91, 24, 10}, {90, 24, 10}, {90, 23, 10},
+			}}},
+			want: GeoTypePolygon,
+		},
+		{
+			name:    "feature without geometry",
+			raw:     map[string]interface{}{"type": "Feature", "properties": map[string]interface{}{}},
+			wantErr: "feature has no geometry",
+		},
+		{
+			name:    "unsupported type",
+			raw:     map[string]interface{}{"type": "Point", "coordinates": []float64{90, 23}},
+			wantErr: "unsupported GeoJSON type",
+		},
+		{
+			name:    "ring too short",
+			raw:     map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{{90, 23}, {91, 23}, {90, 23}}}},
+			wantErr: "at least 4 positions",
+		},
+		{
+			name:    "position outside WGS84",
+			raw:     map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{square(179, 23, 181, 24)}},
+			wantErr: "outside WGS84 bounds",
+		},
+		{
+			name:    "position without latitude",
+			raw:     map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{{90}, {91}, {92}, {90}}}},
+			wantErr: "longitude and latitude",
+		},
+		{
+			name:    "polygon without rings",
+			raw:     map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{}},
+			wantErr: "polygon has no rings",
+		},
+		{
+			name:    "empty multipolygon",
+			raw:     map[string]interface{}{"type": "MultiPolygon", "coordinates": [][][][]float64{}},
+			wantErr: "geometry has no polygons",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, normalised, err := ParseBoundary(geoJSON(t, tt.raw))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var g struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			}
+			if err := json.Unmarshal(normalised, &g); err != nil {
+				t.Fatalf("normalised geometry is not JSON: %v", err)
+			}
+			if g.Type != tt.want {
+				t.Errorf("type = %q, want %q", g.Type, tt.want)
+			}
+			if strings.Contains(string(g.Coordinates), "10]") {
+				t.Errorf("coordinates kept the altitude: %s", g.Coordinates)
+			}
+		})
+	}
+}
+
+func TestShapeContains(t *testing.T) {
+	// A 10x10 degree square with a 2x2 hole in the middle
+	withHole := mustParse(t, geoJSON(t, map[string]interface{}{
+		"type":        "Polygon",
+		"coordinates": [][][]float64{square(80, 10, 90, 20), square(84, 14, 86, 16)},
+	}))
+	// Two disjoint squares
+	multi := mustParse(t, geoJSON(t, map[string]interface{}{
+		"type": "MultiPolygon",
+		"coordinates": [][][][]float64{
+			{square(88, 22, 89, 23)},
+			{square(91, 24, 92, 25), square(91.4, 24.4, 91.6, 24.6)},
+		},
+	}))
+	// Concave (U-shaped) polygon: the notch between the arms is outside
+	concave := mustParse(t, geoJSON(t, map[string]interface{}{
+		"type": "Polygon",
+		"coordinates": [][][]float64{{
+			{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 0},
+		}},
+	}))
+	// Squares touching the antimeridian and the poles without crossing them
+	east := mustParse(t, geoJSON(t, map[string]interface{}{
+		"type": "Polygon", "coordinates": [][][]float64{square(170, -10, 180, 10)},
+	}))
+	west := mustParse(t, geoJSON(t, map[string]interface{}{
+		"type": "Polygon", "coordinates": [][][]float64{square(-180, -10, -170, 10)},
+	}))
+	polar := mustParse(t, geoJSON(t, map[string]interface{}{
+		"type": "Polygon", "coordinates": [][][]float64{square(-10, 80, 10, 90)},
+	}))
+
+	tests := []struct {
+		name     string
+		shape    *Shape
+		lat, lng float64
+		want     bool
+	}{
+		{"inside outer ring", withHole, 12, 82, true},
+		{"inside the hole", withHole, 15, 85, false},
+		{"between hole and outer ring", withHole, 15, 83, true},
+		{"outside", withHole, 25, 85, false},
+		{"west of the square", withHole, 15, 79.999, false},
+
+		{"first part of multipolygon", multi, 22.5, 88.5, true},
+		{"second part of multipolygon", multi, 24.2, 91.2, true},
+		{"hole in second part", multi, 24.5, 91.5, false},
+		{"between the parts", multi, 23.5, 90, false},
+
+		{"left arm of U", concave, 2, 0.5, true},
+		{"notch of U", concave, 2, 1.5, false},
+		{"base of U", concave, 0.5, 1.5, true},
+
+		{"east of 170 up to the antimeridian", east, 0, 179.9, true},
+		{"just west of 170", east, 0, 169.9, false},
+		{"other side of the antimeridian is not included", east, 0, -179.9, false},
+		{"west of -170 from the antimeridian", west, 0, -179.9, true},
+		{"east of -170", west, 0, -169.9, false},
+		{"near the pole", polar, 89.9, 0, true},
+		{"south of polar cap", polar, 79.9, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.shape.Contains(tt.lat, tt.lng); got != tt.want {
+				t.Errorf("Contains(%v, %v) = %v, want %v", tt.lat, tt.lng, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShapeBBox(t *testing.T) {
+	shape := mustParse(t, geoJSON(t, map[string]interface{}{
+		"type": "MultiPolygon",
+		"coordinates": [][][][]float64{
+			{square(88, 22, 89, 23)},
+			{square(91, 24, 92, 25)},
+		},
+	}))
+
+	want := BBox{MinLat: 22, MinLng: 88, MaxLat: 25, MaxLng: 92}
+	if got := shape.BBox(); got != want {
+		t.Fatalf("BBox() = %+v, want %+v", got, want)
+	}
+	if !want.Contains(25, 92) || want.Contains(25.1, 92) {
+		t.Error("BBox.Contains should include its edges and nothing beyond")
+	}
+}
+
+func TestShapeCentroid(t *testing.T) {
+	tests := []struct {
+		name             string
+		geometry         interface{}
+		wantLat, wantLng float64
+	}{
+		{
+			name:     "square",
+			geometry: map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{square(0, 0, 2, 2)}},
+			wantLat:  1, wantLng: 1,
+		},
+		{
+			name: "clockwise ring",
+			geometry: map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{
+				{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0},
+			}}},
+			wantLat: 1, wantLng: 1,
+		},
+		{
+			// 4x4 square minus the 2x2 hole in its upper right quarter: area 12
+			name: "hole shifts the centroid away from it",
+			geometry: map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{
+				square(0, 0, 4, 4), square(2, 2, 4, 4),
+			}},
+			wantLat: (16*2 - 4*3) / 12.0, wantLng: (16*2 - 4*3) / 12.0,
+		},
+		{
+			// Equal areas, so the centroid is midway
+			name: "multipolygon weighs parts by area",
+			geometry: map[string]interface{}{"type": "MultiPolygon", "coordinates": [][][][]float64{
+				{square(0, 0, 2, 2)},
+				{square(10, 0, 12, 2)},
+			}},
+			wantLat: 1, wantLng: 6,
+		},
+		{
+			name: "degenerate ring falls back to the bounding box centre",
+			geometry: map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{
+				{0, 0}, {4, 2}, {2, 1}, {0, 0},
+			}}},
+			wantLat: 1, wantLng: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat, lng := mustParse(t, geoJSON(t, tt.geometry)).Centroid()
+			if math.Abs(lat-tt.wantLat) > 1e-9 || math.Abs(lng-tt.wantLng) > 1e-9 {
+				t.Errorf("Centroid() = (%v, %v), want (%v, %v)", lat, lng, tt.wantLat, tt.wantLng)
+			}
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package committee
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/bjdms/api/internal/middleware"
 	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/pdf"
 	"github.com/bjdms/api/pkg/response"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -13,11 +20,12 @@ import (
 // Handler handles HTTP requests for committees and jurisdictions
 type Handler struct {
 	service *Service
+	pdf     *pdf.Renderer
 }
 
 // NewHandler creates a new committee handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, pdfRenderer *pdf.Renderer) *Handler {
+	return &Handler{service: service, pdf: pdfRenderer}
 }
 
 // Routes defines routes for committees and jurisdictions
@@ -28,11 +36,51 @@ func (h *Handler) Routes() chi.Router {
 	r.Post("/jurisdictions", h.CreateJurisdiction)
 	r.Get("/jurisdictions", h.ListJurisdictions)
 
+	// Jurisdiction geography
+	r.Get("/jurisdictions/resolve", h.ResolveLocation)
+	r.Get("/jurisdictions/{id}/geojson", h.ExportGeoJSON)
+
+	// Rosters and org chart
+	r.Get("/jurisdictions/{id}/roster", h.ExportSubtreeRoster)
+	r.Get("/jurisdictions/{id}/org-chart", h.GetOrgChart)
+	r.Get("/committees/{id}/roster", h.ExportCommitteeRoster)
+
+	// Vacancy and coverage report
+	r.Get("/jurisdictions/{id}/coverage", h.GetCoverageReport)
+
+	// Position catalog (read-only; changes go through AdminRoutes)
+	r.Get("/positions", h.ListPositions)
+	r.Get("/committee-structures", h.ListCommitteeStructures)
+
 	// Committees
 	r.Post("/committees", h.CreateCommittee)
 	r.Get("/committees/{id}/members", h.ListMembers)
 	r.Post("/committees/{id}/members", h.AddMember)
 
+	// Sub-committees and working groups (members are managed through /committees/{id}/members)
+	r.Post("/committees/{id}/sub-committees", h.CreateSubCommittee)
+	r.Get("/committees/{id}/sub-committees", h.ListSubCommittees)
+	r.Get("/sub-committees/{id}", h.GetSubCommittee)
+	r.Patch("/sub-committees/{id}", h.UpdateSubCommittee)
+	r.Post("/sub-committees/{id}/dissolve", h.DissolveSubCommittee)
+
+	return r
+}
+
+// AdminRoutes defines routes for managing the position catalog, committee structures and
+// jurisdiction boundaries
+func (h *Handler) AdminRoutes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/jurisdictions/boundaries/import", h.ImportBoundaries)
+	r.Put("/jurisdictions/{id}/boundary", h.SetBoundary)
+	r.Delete("/jurisdictions/{id}/boundary", h.ClearBoundary)
+
+	r.Post("/positions", h.CreatePosition)
+	r.Patch("/positions/{id}", h.UpdatePosition)
+	r.Delete("/positions/{id}", h.DeletePosition)
+	r.Put("/committee-structures/{level_id}/{type}", h.SetCommitteeStructure)
+
 	return r
 }
 
@@ -72,6 +120,140 @@ func (h *Handler) ListJurisdictions(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, list, "")
 }
 
+// SetBoundary handles PUT /jurisdictions/{id}/boundary
+func (h *Handler) SetBoundary(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		response.BadRequest(w, "Invalid GeoJSON body")
+		return
+	}
+
+	j, err := h.service.SetJurisdictionBoundary(r.Context(), id, raw)
+	if err != nil {
+		writeGeoError(w, err, "Failed to save boundary")
+		return
+	}
+
+	response.Success(w, j, "Boundary saved successfully")
+}
+
+// ClearBoundary handles DELETE /jurisdictions/{id}/boundary
+func (h *Handler) ClearBoundary(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	if err := h.service.ClearJurisdictionBoundary(r.Context(), id); err != nil {
+		writeGeoError(w, err, "Failed to clear boundary")
+		return
+	}
+
+	response.Success(w, nil, "Boundary removed")
+}
+
+// ImportBoundaries handles POST /jurisdictions/boundaries/import?match=name&level_id=3
+func (h *Handler) ImportBoundaries(w http.ResponseWriter, r *http.Request) {
+	var fc FeatureCollection
+	if err := json.NewDecoder(r.Body).Decode(&fc); err != nil {
+		response.BadRequest(w, "Invalid GeoJSON FeatureCollection")
+		return
+	}
+
+	var levelID *int
+	if lvl, err := strconv.Atoi(r.URL.Query().Get("level_id")); err == nil {
+		levelID = &lvl
+	}
+
+	result, err := h.service.ImportBoundaries(r.Context(), &fc, r.URL.Query().Get("match"), levelID)
+	if err != nil {
+		writeGeoError(w, err, "Failed to import boundaries")
+		return
+	}
+
+	response.Success(w, result, "Boundary import finished")
+}
+
+// ResolveLocation handles GET /jurisdictions/resolve?lat=..&lng=..
+func (h *Handler) ResolveLocation(w http.ResponseWriter, r *http.Request) {
+	lat, errLat := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lng, errLng := strconv.ParseFloat(r.URL.Query().Get("lng"), 64)
+	if errLat != nil || errLng != nil {
+		response.BadRequest(w, "lat and lng are required")
+		return
+	}
+
+	j, err := h.service.ResolveLocation(r.Context(), lat, lng)
+	if err != nil {
+		writeGeoError(w, err, "Failed to resolve location")
+		return
+	}
+	if j == nil {
+		response.NotFound(w, "No jurisdiction contains this location")
+		return
+	}
+
+	response.Success(w, j, "")
+}
+
+// ExportGeoJSON handles GET /jurisdictions/{id}/geojson
+func (h *Handler) ExportGeoJSON(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	withMetrics := r.URL.Query().Get("metrics") != "false"
+	fc, err := h.service.ExportSubtreeGeoJSON(r.Context(), id, withMetrics)
+	if err != nil {
+		writeGeoError(w, err, "Failed to export boundaries")
+		return
+	}
+
+	// Served as plain GeoJSON so map libraries can consume it directly
+	w.Header().Set("Content-Type", "application/geo+json")
+	json.NewEncoder(w).Encode(fc)
+}
+
+// writeGeoError reports an unknown jurisdiction or invalid geography; anything else is an
+// internal failure and is not shown to the client
+func writeGeoError(w http.ResponseWriter, err error, internal string) {
+	switch {
+	case errors.Is(err, ErrJurisdictionNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrInvalidBoundary), errors.Is(err, ErrOutsideBounds), errors.Is(err, ErrNotFeatureCollection):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalError(w, internal, "")
+	}
+}
+
+// writeMemberError maps membership errors to responses; database and other internal failures
+// are not shown to the client
+func writeMemberError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrMemberAccess), errors.Is(err, ErrSubCommitteeAccess):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrCommitteeNotFound), errors.Is(err, ErrSubCommitteeNotFound),
+		errors.Is(err, ErrJurisdictionNotFound), errors.Is(err, ErrPositionNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrAlreadyMember), errors.Is(err, ErrPositionOccupied), errors.Is(err, ErrSubCommitteeInactive):
+		response.Conflict(w, err.Error())
+	case errors.Is(err, ErrStructureMismatch), errors.Is(err, ErrTermLimit):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalError(w, "Failed to add member", "")
+	}
+}
+
 // CreateCommittee handles POST /committees
 func (h *Handler) CreateCommittee(w http.ResponseWriter, r *http.Request) {
 	var c models.Committee
@@ -104,8 +286,14 @@ func (h *Handler) AddMember(w http.ResponseWriter, r *http.Request) {
 	}
 	m.CommitteeID = committeeID
 
-	if err := h.service.AddMember(r.Context(), &m); err != nil {
-		response.Error(w, http.StatusBadRequest, "member_error", err.Error(), "")
+	actorID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	if err := h.service.AddMember(r.Context(), &m, actorID); err != nil {
+		writeMemberError(w, err)
 		return
 	}
 
@@ -129,3 +317,434 @@ func (h *Handler) ListMembers(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, members, "")
 }
+
+// POSITIONS
+
+// ListPositions handles GET /positions
+func (h *Handler) ListPositions(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListPositions(r.Context())
+	if err != nil {
+		response.InternalError(w, "Failed to fetch positions", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// CreatePosition handles POST /admin/org/positions
+func (h *Handler) CreatePosition(w http.ResponseWriter, r *http.Request) {
+	var p models.Position
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.service.CreatePosition(r.Context(), &p); err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	response.Created(w, p, "Position created successfully")
+}
+
+// UpdatePosition handles PATCH /admin/org/positions/{id}
+func (h *Handler) UpdatePosition(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid position ID")
+		return
+	}
+
+	// Start from the stored position so partial updates keep other fields
+	p, err := h.service.repo.GetPosition(r.Context(), id)
+	if err != nil {
+		response.NotFound(w, "Position not found")
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	p.ID = id
+
+	if err := h.service.UpdatePosition(r.Context(), p); err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	response.Success(w, p, "Position updated successfully")
+}
+
+// DeletePosition handles DELETE /admin/org/positions/{id}
+func (h *Handler) DeletePosition(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid position ID")
+		return
+	}
+
+	if err := h.service.DeletePosition(r.Context(), id); err != nil {
+		response.Conflict(w, err.Error())
+		return
+	}
+
+	response.Success(w, nil, "Position deleted")
+}
+
+// ListCommitteeStructures handles GET /committee-structures?level_id=..
+func (h *Handler) ListCommitteeStructures(w http.ResponseWriter, r *http.Request) {
+	var levelID *int
+	if lvl, err := strconv.Atoi(r.URL.Query().Get("level_id")); err == nil {
+		levelID = &lvl
+	}
+
+	list, err := h.service.ListCommitteeStructures(r.Context(), levelID)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch committee structures", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// SetCommitteeStructure handles PUT /admin/org/committee-structures/{level_id}/{type}
+func (h *Handler) SetCommitteeStructure(w http.ResponseWriter, r *http.Request) {
+	levelID, err := strconv.Atoi(chi.URLParam(r, "level_id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid level ID")
+		return
+	}
+
+	var cs models.CommitteeStructure
+	if err := json.NewDecoder(r.Body).Decode(&cs); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	cs.LevelID = levelID
+	cs.CommitteeType = chi.URLParam(r, "type")
+
+	if id, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		cs.UpdatedBy = &id
+	}
+
+	if err := h.service.SetCommitteeStructure(r.Context(), &cs); err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	response.Success(w, cs, "Committee structure updated successfully")
+}
+
+// ROSTERS
+
+// ExportCommitteeRoster handles GET /committees/{id}/roster?format=pdf|xlsx|html|json
+func (h *Handler) ExportCommitteeRoster(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid committee ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	roster, err := h.service.GetCommitteeRoster(r.Context(), id, userID)
+	if err != nil {
+		writeRosterError(w, err, "Committee not found")
+		return
+	}
+
+	title := fmt.Sprintf("%s Committee Roster", roster.JurisdictionName)
+	h.writeRoster(w, r, title, "committee-roster", []*models.RosterCommittee{roster})
+}
+
+// ExportSubtreeRoster handles GET /jurisdictions/{id}/roster?format=pdf|xlsx|html|json&depth=1
+func (h *Handler) ExportSubtreeRoster(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	rosters, err := h.service.GetSubtreeRoster(r.Context(), id, userID, rosterDepth(r))
+	if err != nil {
+		writeRosterError(w, err, "Jurisdiction not found")
+		return
+	}
+
+	title := "Committee Roster"
+	if len(rosters) > 0 && rosters[0].Depth == 0 {
+		title = fmt.Sprintf("%s Committee Roster", rosters[0].JurisdictionName)
+	}
+	h.writeRoster(w, r, title, "jurisdiction-roster", rosters)
+}
+
+// GetOrgChart handles GET /jurisdictions/{id}/org-chart?depth=1&members=all
+func (h *Handler) GetOrgChart(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	allMembers := r.URL.Query().Get("members") == "all"
+	chart, err := h.service.GetOrgChart(r.Context(), id, userID, rosterDepth(r), allMembers)
+	if err != nil {
+		writeRosterError(w, err, "Jurisdiction not found")
+		return
+	}
+
+	response.Success(w, chart, "")
+}
+
+// GetCoverageReport handles GET /jurisdictions/{id}/coverage?depth=&format=csv
+func (h *Handler) GetCoverageReport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	// Issues cover the whole subtree unless a depth is given
+	depth := -1
+	if v := r.URL.Query().Get("depth"); v != "" {
+		depth, err = strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			response.BadRequest(w, "Invalid depth")
+			return
+		}
+	}
+
+	report, err := h.service.GetCoverageReport(r.Context(), id, depth)
+	if err != nil {
+		if errors.Is(err, ErrJurisdictionNotFound) {
+			response.NotFound(w, err.Error())
+			return
+		}
+		response.InternalError(w, "Failed to build coverage report", "")
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="coverage-%s.csv"`, id))
+		if err := WriteCoverageCSV(w, report); err != nil {
+			response.InternalError(w, "Failed to write CSV", "")
+		}
+		return
+	}
+
+	response.Success(w, report, "")
+}
+
+func (h *Handler) writeRoster(w http.ResponseWriter, r *http.Request, title, filename string, rosters []*models.RosterCommittee) {
+	switch r.URL.Query().Get("format") {
+	case "xlsx":
+		var buf bytes.Buffer
+		if err := WriteRosterXLSX(&buf, rosters); err != nil {
+			response.InternalError(w, "Failed to generate spreadsheet", "")
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.xlsx"`, filename))
+		w.Write(buf.Bytes())
+
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		WriteRosterHTML(w, title, rosters)
+
+	case "pdf":
+		var html bytes.Buffer
+		if err := WriteRosterHTML(&html, title, rosters); err != nil {
+			response.InternalError(w, "Failed to generate roster", "")
+			return
+		}
+		doc, err := h.pdf.Render(r.Context(), html.Bytes())
+		if err == pdf.ErrRendererUnavailable {
+			response.Error(w, http.StatusNotImplemented, "pdf_unavailable", "PDF export is not available on this server; use format=html and print instead", "")
+			return
+		}
+		if err != nil {
+			response.InternalError(w, "Failed to generate PDF", "")
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, filename))
+		w.Write(doc)
+
+	default:
+		response.Success(w, rosters, "")
+	}
+}
+
+func rosterDepth(r *http.Request) int {
+	depth, err := strconv.Atoi(r.URL.Query().Get("depth"))
+	if err != nil || depth < 0 {
+		return DefaultRosterDepth
+	}
+	if depth > MaxRosterDepth {
+		return MaxRosterDepth
+	}
+	return depth
+}
+
+// writeRosterError reports a refused roster or org chart request; anything else means the
+// committee or jurisdiction does not exist
+func writeRosterError(w http.ResponseWriter, err error, notFound string) {
+	if errors.Is(err, ErrRosterAccess) {
+		response.Forbidden(w, err.Error())
+		return
+	}
+	response.NotFound(w, notFound)
+}
+
+// CreateSubCommittee handles POST /committees/{id}/sub-committees
+func (h *Handler) CreateSubCommittee(w http.ResponseWriter, r *http.Request) {
+	parentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid committee ID")
+		return
+	}
+
+	var c models.Committee
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	actorID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "User not authenticated")
+		return
+	}
+	c.CreatedBy = &actorID
+
+	if err := h.service.CreateSubCommittee(r.Context(), parentID, actorID, &c); err != nil {
+		writeSubCommitteeError(w, err)
+		return
+	}
+
+	response.Created(w, c, "Sub-committee formed successfully")
+}
+
+// ListSubCommittees handles GET /committees/{id}/sub-committees?include_inactive=true
+func (h *Handler) ListSubCommittees(w http.ResponseWriter, r *http.Request) {
+	parentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid committee ID")
+		return
+	}
+
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+	list, err := h.service.ListSubCommittees(r.Context(), parentID, includeInactive)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch sub-committees", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// GetSubCommittee handles GET /sub-committees/{id}
+func (h *Handler) GetSubCommittee(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid sub-committee ID")
+		return
+	}
+
+	c, members, err := h.service.GetSubCommittee(r.Context(), id)
+	if err != nil {
+		response.NotFound(w, "Sub-committee not found")
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"sub_committee": c,
+		"members":       members,
+	}, "")
+}
+
+// UpdateSubCommittee handles PATCH /sub-committees/{id}
+func (h *Handler) UpdateSubCommittee(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid sub-committee ID")
+		return
+	}
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "User not authenticated")
+		return
+	}
+
+	// Start from the stored record so partial updates keep other fields
+	c, _, err := h.service.GetSubCommittee(r.Context(), id)
+	if err != nil {
+		response.NotFound(w, "Sub-committee not found")
+		return
+	}
+	var body struct {
+		Name      *string    `json:"name"`
+		Mandate   *string    `json:"mandate"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if body.Name != nil {
+		c.Name = body.Name
+	}
+	if body.Mandate != nil {
+		c.Mandate = body.Mandate
+	}
+	if body.ExpiresAt != nil {
+		c.ExpiresAt = body.ExpiresAt
+	}
+
+	if err := h.service.UpdateSubCommittee(r.Context(), userID, c); err != nil {
+		writeSubCommitteeError(w, err)
+		return
+	}
+
+	response.Success(w, c, "Sub-committee updated successfully")
+}
+
+// DissolveSubCommittee handles POST /sub-committees/{id}/dissolve
+func (h *Handler) DissolveSubCommittee(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid sub-committee ID")
+		return
+	}
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "User not authenticated")
+		return
+	}
+
+	if err := h.service.DissolveSubCommittee(r.Context(), id, userID); err != nil {
+		writeSubCommitteeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Sub-committee dissolved")
+}
+
+// writeSubCommitteeError maps sub-committee errors to responses; anything else is an internal
+// failure and is not shown to the client
+func writeSubCommitteeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrSubCommitteeAccess):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrCommitteeNotFound), errors.Is(err, ErrSubCommitteeNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrSubCommitteeInactive):
+		response.Conflict(w, err.Error())
+	case errors.Is(err, ErrNestedSubCommittee), errors.Is(err, ErrParentInactive),
+		errors.Is(err, ErrSubCommitteeNameRequired), errors.Is(err, ErrExpiryInPast):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalError(w, "Failed to process sub-committee request", "")
+	}
+}
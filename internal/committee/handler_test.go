@@ -0,0 +1,102 @@
+package committee
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteGeoError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"jurisdiction not found", ErrJurisdictionNotFound, http.StatusNotFound, ErrJurisdictionNotFound.Error()},
+		{"invalid boundary", fmt.Errorf("%w: polygon has no rings", ErrInvalidBoundary), http.StatusBadRequest, "polygon has no rings"},
+		{"outside bounds", ErrOutsideBounds, http.StatusBadRequest, ErrOutsideBounds.Error()},
+		{"database error", errors.New(`ERROR: column "boundary" does not exist (SQLSTATE 42703)`), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeGeoError(rec, tt.err, "Failed to save boundary")
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := rec.Body.String()
+			if tt.wantBody != "" && !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body %s does not contain %q", body, tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(body, tt.err.Error()) {
+				t.Errorf("internal error leaked to the client: %s", body)
+			}
+		})
+	}
+}
+
+func TestWriteSubCommitteeError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"no access", ErrSubCommitteeAccess, http.StatusForbidden},
+		{"parent not found", ErrCommitteeNotFound, http.StatusNotFound},
+		{"not a sub-committee", ErrSubCommitteeNotFound, http.StatusNotFound},
+		{"already dissolved", fmt.Errorf("cannot dissolve a %s sub-committee: %w", "dissolved", ErrSubCommitteeInactive), http.StatusConflict},
+		{"validation", ErrSubCommitteeNameRequired, http.StatusBadRequest},
+		{"database error", errors.New("conn closed"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeSubCommitteeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(rec.Body.String(), tt.err.Error()) {
+				t.Errorf("internal error leaked to the client: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriteMemberError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"not a manager", ErrMemberAccess, http.StatusForbidden},
+		{"not a sub-committee manager", ErrSubCommitteeAccess, http.StatusForbidden},
+		{"committee not found", ErrCommitteeNotFound, http.StatusNotFound},
+		{"already a member", ErrAlreadyMember, http.StatusConflict},
+		{"seat taken", fmt.Errorf("%w: the position of President is already occupied in this committee", ErrPositionOccupied), http.StatusConflict},
+		{"structure", fmt.Errorf("%w: position 9 is not allowed in Full committees at level 3", ErrStructureMismatch), http.StatusBadRequest},
+		{"term limit", fmt.Errorf("%w: user has already served 2 term(s)", ErrTermLimit), http.StatusBadRequest},
+		{"database error", errors.New(`ERROR: insert or update on table "committee_members" violates foreign key constraint (SQLSTATE 23503)`), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeMemberError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(rec.Body.String(), tt.err.Error()) {
+				t.Errorf("internal error leaked to the client: %s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package committee
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,15 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Lookup errors
+var (
+	ErrJurisdictionNotFound = errors.New("jurisdiction not found")
+	ErrCommitteeNotFound    = errors.New("committee not found")
+	ErrSubCommitteeNotFound = errors.New("sub-committee not found")
+	ErrSubCommitteeInactive = errors.New("sub-committee is no longer active")
+	ErrPositionNotFound     = errors.New("position not found")
+)
+
 // Repository handles database operations for committees and jurisdictions
 type Repository struct {
 	db *pgxpool.Pool
@@ -38,16 +48,16 @@ func (r *Repository) CreateJurisdiction(ctx context.Context, j *models.Jurisdict
 // GetJurisdiction retrieves a jurisdiction by ID
 func (r *Repository) GetJurisdiction(ctx context.Context, id uuid.UUID) (*models.Jurisdiction, error) {
 	query := `
-		SELECT id, level_id, parent_id, name, name_bn, is_urban, population, created_at, updated_at
+		SELECT id, level_id, parent_id, name, name_bn, is_urban, population, centroid_lat, centroid_lng, created_at, updated_at
 		FROM jurisdictions
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 	var j models.Jurisdiction
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&j.ID, &j.LevelID, &j.ParentID, &j.Name, &j.NameBn, &j.IsUrban, &j.Population, &j.CreatedAt, &j.UpdatedAt,
+		&j.ID, &j.LevelID, &j.ParentID, &j.Name, &j.NameBn, &j.IsUrban, &j.Population, &j.CentroidLat, &j.CentroidLng, &j.CreatedAt, &j.UpdatedAt,
 	)
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("jurisdiction not found")
+		return nil, ErrJurisdictionNotFound
 	}
 	return &j, err
 }
@@ -55,7 +65,7 @@ func (r *Repository) GetJurisdiction(ctx context.Context, id uuid.UUID) (*models
 // ListJurisdictions returns all jurisdictions at a specific level or parent
 func (r *Repository) ListJurisdictions(ctx context.Context, levelID *int, parentID *uuid.UUID) ([]*models.Jurisdiction, error) {
 	query := `
-		SELECT id, level_id, parent_id, name, name_bn, is_urban, population, created_at, updated_at
+		SELECT id, level_id, parent_id, name, name_bn, is_urban, population, centroid_lat, centroid_lng, created_at, updated_at
 		FROM jurisdictions
 		WHERE deleted_at IS NULL
 	`
@@ -80,7 +90,78 @@ func (r *Repository) ListJurisdictions(ctx context.Context, levelID *int, parent
 	var list []*models.Jurisdiction
 	for rows.Next() {
 		var j models.Jurisdiction
-		err := rows.Scan(&j.ID, &j.LevelID, &j.ParentID, &j.Name, &j.NameBn, &j.IsUrban, &j.Population, &j.CreatedAt, &j.UpdatedAt)
+		err := rows.Scan(&j.ID, &j.LevelID, &j.ParentID, &j.Name, &j.NameBn, &j.IsUrban, &j.Population, &j.CentroidLat, &j.CentroidLng, &j.CreatedAt, &j.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &j)
+	}
+	return list, nil
+}
+
+// GEOGRAPHY
+
+// SetJurisdictionBoundary stores a normalised GeoJSON boundary with its derived centroid and bounding box
+func (r *Repository) SetJurisdictionBoundary(ctx context.Context, id uuid.UUID, boundary []byte, centroidLat, centroidLng float64, bbox BBox) error {
+	query := `
+		UPDATE jurisdictions
+		SET boundary = $1, centroid_lat = $2, centroid_lng = $3,
+		    bbox_min_lat = $4, bbox_min_lng = $5, bbox_max_lat = $6, bbox_max_lng = $7,
+		    updated_at = NOW()
+		WHERE id = $8 AND deleted_at IS NULL
+	`
+	res, err := r.db.Exec(ctx, query, boundary, centroidLat, centroidLng, bbox.MinLat, bbox.MinLng, bbox.MaxLat, bbox.MaxLng, id)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrJurisdictionNotFound
+	}
+	return nil
+}
+
+// ClearJurisdictionBoundary removes all stored geography for a jurisdiction
+func (r *Repository) ClearJurisdictionBoundary(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE jurisdictions
+		SET boundary = NULL, centroid_lat = NULL, centroid_lng = NULL,
+		    bbox_min_lat = NULL, bbox_min_lng = NULL, bbox_max_lat = NULL, bbox_max_lng = NULL,
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	res, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrJurisdictionNotFound
+	}
+	return nil
+}
+
+// FindJurisdictionsByName looks up jurisdictions by English or Bangla name, optionally at a level
+func (r *Repository) FindJurisdictionsByName(ctx context.Context, name string, levelID *int) ([]*models.Jurisdiction, error) {
+	query := `
+		SELECT id, level_id, parent_id, name, name_bn, is_urban, population, centroid_lat, centroid_lng, created_at, updated_at
+		FROM jurisdictions
+		WHERE deleted_at IS NULL AND (LOWER(name) = LOWER($1) OR name_bn = $1)
+	`
+	args := []interface{}{name}
+	if levelID != nil {
+		args = append(args, *levelID)
+		query += fmt.Sprintf(" AND level_id = $%d", len(args))
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Jurisdiction
+	for rows.Next() {
+		var j models.Jurisdiction
+		err := rows.Scan(&j.ID, &j.LevelID, &j.ParentID, &j.Name, &j.NameBn, &j.IsUrban, &j.Population, &j.CentroidLat, &j.CentroidLng, &j.CreatedAt, &j.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -89,17 +170,119 @@ func (r *Repository) ListJurisdictions(ctx context.Context, levelID *int, parent
 	return list, nil
 }
 
+// ListJurisdictionsByPoint returns jurisdictions whose bounding box contains the point.
+// Callers must still perform an exact point-in-polygon test on the returned boundaries.
+func (r *Repository) ListJurisdictionsByPoint(ctx context.Context, lat, lng float64) ([]*models.Jurisdiction, error) {
+	query := `
+		SELECT j.id, j.level_id, j.parent_id, j.name, j.name_bn, j.is_urban, j.population,
+		       j.centroid_lat, j.centroid_lng, j.created_at, j.updated_at, j.boundary, jl.rank
+		FROM jurisdictions j
+		JOIN jurisdiction_levels jl ON j.level_id = jl.id
+		WHERE j.boundary IS NOT NULL AND j.deleted_at IS NULL
+		  AND $1 BETWEEN j.bbox_min_lat AND j.bbox_max_lat
+		  AND $2 BETWEEN j.bbox_min_lng AND j.bbox_max_lng
+		ORDER BY jl.rank DESC
+	`
+	return r.scanGeoJurisdictions(ctx, query, lat, lng)
+}
+
+// ListSubtreeWithBoundaries returns a jurisdiction and all its descendants including boundaries
+func (r *Repository) ListSubtreeWithBoundaries(ctx context.Context, rootID uuid.UUID) ([]*models.Jurisdiction, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT j.id FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+			WHERE j.deleted_at IS NULL
+		)
+		SELECT j.id, j.level_id, j.parent_id, j.name, j.name_bn, j.is_urban, j.population,
+		       j.centroid_lat, j.centroid_lng, j.created_at, j.updated_at, j.boundary, jl.rank
+		FROM jurisdictions j
+		JOIN subtree s ON j.id = s.id
+		JOIN jurisdiction_levels jl ON j.level_id = jl.id
+		ORDER BY jl.rank ASC, j.name ASC
+	`
+	return r.scanGeoJurisdictions(ctx, query, rootID)
+}
+
+func (r *Repository) scanGeoJurisdictions(ctx context.Context, query string, args ...interface{}) ([]*models.Jurisdiction, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Jurisdiction
+	for rows.Next() {
+		var j models.Jurisdiction
+		err := rows.Scan(
+			&j.ID, &j.LevelID, &j.ParentID, &j.Name, &j.NameBn, &j.IsUrban, &j.Population,
+			&j.CentroidLat, &j.CentroidLng, &j.CreatedAt, &j.UpdatedAt, &j.Boundary, &j.LevelRank,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &j)
+	}
+	return list, nil
+}
+
+// GetSubtreeMetrics returns organisational metrics for every jurisdiction in a subtree
+func (r *Repository) GetSubtreeMetrics(ctx context.Context, rootID uuid.UUID) (map[uuid.UUID]*models.JurisdictionMetrics, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT j.id FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+			WHERE j.deleted_at IS NULL
+		)
+		SELECT s.id,
+		       c.id IS NOT NULL AS has_active_committee,
+		       COALESCE(c.type::text, '') AS committee_type,
+		       (SELECT COUNT(*) FROM committee_members cm
+		        WHERE cm.committee_id = c.id AND cm.ended_at IS NULL) AS member_count,
+		       (SELECT COUNT(*) FROM activities a
+		        WHERE a.jurisdiction_id = s.id AND a.deleted_at IS NULL
+		          AND a.activity_date >= NOW() - INTERVAL '30 days') AS activity_count,
+		       (SELECT COUNT(*) FROM complaints co
+		        WHERE co.jurisdiction_id = s.id AND co.deleted_at IS NULL
+		          AND co.status NOT IN ('closed', 'rejected')) AS open_complaints,
+		       (SELECT COUNT(*) FROM jurisdictions ch
+		        WHERE ch.parent_id = s.id AND ch.deleted_at IS NULL) AS child_count
+		FROM subtree s
+		LEFT JOIN committees c ON c.jurisdiction_id = s.id AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+	`
+	rows, err := r.db.Query(ctx, query, rootID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	metrics := make(map[uuid.UUID]*models.JurisdictionMetrics)
+	for rows.Next() {
+		var id uuid.UUID
+		var m models.JurisdictionMetrics
+		if err := rows.Scan(&id, &m.HasActiveCommittee, &m.CommitteeType, &m.MemberCount, &m.ActivityCount30d, &m.OpenComplaints, &m.ChildCount); err != nil {
+			return nil, err
+		}
+		metrics[id] = &m
+	}
+	return metrics, nil
+}
+
 // COMMITTEES
 
 // CreateCommittee inserts a new committee record
 func (r *Repository) CreateCommittee(ctx context.Context, c *models.Committee) error {
 	query := `
-		INSERT INTO committees (jurisdiction_id, type, status, formed_at, expires_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO committees (jurisdiction_id, type, status, formed_at, expires_at, parent_committee_id, name, mandate, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at
 	`
 	return r.db.QueryRow(ctx, query,
-		c.JurisdictionID, c.Type, c.Status, c.FormedAt, c.ExpiresAt,
+		c.JurisdictionID, c.Type, c.Status, c.FormedAt, c.ExpiresAt, c.ParentCommitteeID, c.Name, c.Mandate, c.CreatedBy,
 	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
 }
 
@@ -115,7 +298,7 @@ func (r *Repository) GetActiveCommittee(ctx context.Context, jurisdictionID uuid
 	query := `
 		SELECT id, jurisdiction_id, type, status, formed_at, expires_at, created_at, updated_at
 		FROM committees
-		WHERE jurisdiction_id = $1 AND status = 'active' AND deleted_at IS NULL
+		WHERE jurisdiction_id = $1 AND status = 'active' AND deleted_at IS NULL AND parent_committee_id IS NULL
 	`
 	var c models.Committee
 	err := r.db.QueryRow(ctx, query, jurisdictionID).Scan(
@@ -127,6 +310,247 @@ func (r *Repository) GetActiveCommittee(ctx context.Context, jurisdictionID uuid
 	return &c, err
 }
 
+// POSITIONS
+
+// ListPositions returns the position catalog ordered by rank
+func (r *Repository) ListPositions(ctx context.Context) ([]*models.Position, error) {
+	query := `
+		SELECT id, name, COALESCE(name_bn, ''), rank, COALESCE(committee_type, 'Both'), COALESCE(description, ''), max_terms
+		FROM positions
+		ORDER BY rank ASC, name ASC
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Position
+	for rows.Next() {
+		var p models.Position
+		if err := rows.Scan(&p.ID, &p.Name, &p.NameBn, &p.Rank, &p.CommitteeType, &p.Description, &p.MaxTerms); err != nil {
+			return nil, err
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+// GetPosition retrieves a position by ID
+func (r *Repository) GetPosition(ctx context.Context, id int) (*models.Position, error) {
+	query := `
+		SELECT id, name, COALESCE(name_bn, ''), rank, COALESCE(committee_type, 'Both'), COALESCE(description, ''), max_terms
+		FROM positions
+		WHERE id = $1
+	`
+	var p models.Position
+	err := r.db.QueryRow(ctx, query, id).Scan(&p.ID, &p.Name, &p.NameBn, &p.Rank, &p.CommitteeType, &p.Description, &p.MaxTerms)
+	if err == pgx.ErrNoRows {
+		return nil, ErrPositionNotFound
+	}
+	return &p, err
+}
+
+// CreatePosition inserts a new position into the catalog
+func (r *Repository) CreatePosition(ctx context.Context, p *models.Position) error {
+	query := `
+		INSERT INTO positions (name, name_bn, rank, committee_type, description, max_terms)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	return r.db.QueryRow(ctx, query, p.Name, p.NameBn, p.Rank, p.CommitteeType, p.Description, p.MaxTerms).Scan(&p.ID)
+}
+
+// UpdatePosition updates a position's names, rank and applicability
+func (r *Repository) UpdatePosition(ctx context.Context, p *models.Position) error {
+	query := `
+		UPDATE positions
+		SET name = $1, name_bn = $2, rank = $3, committee_type = $4, description = $5, max_terms = $6
+		WHERE id = $7
+	`
+	res, err := r.db.Exec(ctx, query, p.Name, p.NameBn, p.Rank, p.CommitteeType, p.Description, p.MaxTerms, p.ID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrPositionNotFound
+	}
+	return nil
+}
+
+// DeletePosition removes a position that has never been held
+func (r *Repository) DeletePosition(ctx context.Context, id int) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM positions WHERE id = $1`, id)
+	return err
+}
+
+// CountPositionHolders returns how many memberships (current or past) reference a position
+func (r *Repository) CountPositionHolders(ctx context.Context, id int) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM committee_members WHERE position_id = $1`, id).Scan(&count)
+	return count, err
+}
+
+// CountPositionTerms returns how many distinct committees of a jurisdiction the user has held a position in.
+// Proposed committees other than the excluded one are ignored, since they never served a term.
+func (r *Repository) CountPositionTerms(ctx context.Context, userID uuid.UUID, positionID int, jurisdictionID uuid.UUID, excludeCommitteeID *uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT cm.committee_id)
+		FROM committee_members cm
+		JOIN committees c ON cm.committee_id = c.id
+		WHERE cm.user_id = $1 AND cm.position_id = $2 AND c.jurisdiction_id = $3
+		  AND c.status <> 'proposed' AND c.deleted_at IS NULL
+		  AND ($4::uuid IS NULL OR c.id <> $4)
+	`
+	var count int
+	err := r.db.QueryRow(ctx, query, userID, positionID, jurisdictionID, excludeCommitteeID).Scan(&count)
+	return count, err
+}
+
+// ListCommitteeStructures returns size limits and position quotas, optionally for one level
+func (r *Repository) ListCommitteeStructures(ctx context.Context, levelID *int) ([]*models.CommitteeStructure, error) {
+	query := `
+		SELECT level_id, committee_type, max_members, updated_by, updated_at
+		FROM committee_structures
+	`
+	args := []interface{}{}
+	if levelID != nil {
+		args = append(args, *levelID)
+		query += " WHERE level_id = $1"
+	}
+	query += " ORDER BY level_id ASC, committee_type ASC"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.CommitteeStructure
+	for rows.Next() {
+		var cs models.CommitteeStructure
+		if err := rows.Scan(&cs.LevelID, &cs.CommitteeType, &cs.MaxMembers, &cs.UpdatedBy, &cs.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, &cs)
+	}
+	rows.Close()
+
+	for _, cs := range list {
+		cs.Positions, err = r.ListPositionQuotas(ctx, cs.LevelID, cs.CommitteeType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// GetCommitteeStructure returns the configuration for a level and committee type
+func (r *Repository) GetCommitteeStructure(ctx context.Context, levelID int, committeeType string) (*models.CommitteeStructure, error) {
+	query := `
+		SELECT level_id, committee_type, max_members, updated_by, updated_at
+		FROM committee_structures
+		WHERE level_id = $1 AND committee_type = $2
+	`
+	var cs models.CommitteeStructure
+	err := r.db.QueryRow(ctx, query, levelID, committeeType).Scan(&cs.LevelID, &cs.CommitteeType, &cs.MaxMembers, &cs.UpdatedBy, &cs.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cs.Positions, err = r.ListPositionQuotas(ctx, levelID, committeeType)
+	if err != nil {
+		return nil, err
+	}
+	return &cs, nil
+}
+
+// ListPositionQuotas returns the allowed positions for a level and committee type
+func (r *Repository) ListPositionQuotas(ctx context.Context, levelID int, committeeType string) ([]*models.PositionQuota, error) {
+	query := `
+		SELECT q.level_id, q.committee_type, q.position_id, q.max_seats,
+		       p.name, COALESCE(p.name_bn, ''), p.rank
+		FROM committee_position_quotas q
+		JOIN positions p ON q.position_id = p.id
+		WHERE q.level_id = $1 AND q.committee_type = $2
+		ORDER BY p.rank ASC, p.name ASC
+	`
+	rows, err := r.db.Query(ctx, query, levelID, committeeType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*models.PositionQuota{}
+	for rows.Next() {
+		var q models.PositionQuota
+		if err := rows.Scan(&q.LevelID, &q.CommitteeType, &q.PositionID, &q.MaxSeats, &q.PositionName, &q.PositionNameBn, &q.PositionRank); err != nil {
+			return nil, err
+		}
+		list = append(list, &q)
+	}
+	return list, nil
+}
+
+// GetPositionQuota returns the quota for a position, or nil if the position is not allowed
+func (r *Repository) GetPositionQuota(ctx context.Context, levelID int, committeeType string, positionID int) (*models.PositionQuota, error) {
+	query := `
+		SELECT q.level_id, q.committee_type, q.position_id, q.max_seats,
+		       p.name, COALESCE(p.name_bn, ''), p.rank
+		FROM committee_position_quotas q
+		JOIN positions p ON q.position_id = p.id
+		WHERE q.level_id = $1 AND q.committee_type = $2 AND q.position_id = $3
+	`
+	var q models.PositionQuota
+	err := r.db.QueryRow(ctx, query, levelID, committeeType, positionID).Scan(
+		&q.LevelID, &q.CommitteeType, &q.PositionID, &q.MaxSeats, &q.PositionName, &q.PositionNameBn, &q.PositionRank,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return &q, err
+}
+
+// ReplaceCommitteeStructure upserts the size limit and replaces all position quotas in a transaction
+func (r *Repository) ReplaceCommitteeStructure(ctx context.Context, cs *models.CommitteeStructure) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	upsert := `
+		INSERT INTO committee_structures (level_id, committee_type, max_members, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (level_id, committee_type)
+		DO UPDATE SET max_members = EXCLUDED.max_members, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at
+	`
+	if err := tx.QueryRow(ctx, upsert, cs.LevelID, cs.CommitteeType, cs.MaxMembers, cs.UpdatedBy).Scan(&cs.UpdatedAt); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM committee_position_quotas WHERE level_id = $1 AND committee_type = $2`, cs.LevelID, cs.CommitteeType)
+	if err != nil {
+		return err
+	}
+
+	insert := `
+		INSERT INTO committee_position_quotas (level_id, committee_type, position_id, max_seats, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, q := range cs.Positions {
+		if _, err := tx.Exec(ctx, insert, cs.LevelID, cs.CommitteeType, q.PositionID, q.MaxSeats, cs.UpdatedBy); err != nil {
+			return fmt.Errorf("failed to save quota for position %d: %w", q.PositionID, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 // MEMBERS
 
 // AddMember adds a user to a committee with a position
@@ -146,12 +570,13 @@ func (r *Repository) AddMember(ctx context.Context, m *models.CommitteeMember) e
 func (r *Repository) GetCommitteeMembers(ctx context.Context, committeeID uuid.UUID) ([]*models.CommitteeMember, error) {
 	query := `
 		SELECT cm.id, cm.committee_id, cm.user_id, cm.position_id, cm.joined_at, cm.ended_at, cm.is_active,
-		       u.full_name as user_name, p.name as position_name, p.rank as position_rank
+		       u.full_name as user_name, u.full_name_bn,
+		       p.name as position_name, COALESCE(p.name_bn, '') as position_name_bn, p.rank as position_rank
 		FROM committee_members cm
 		JOIN users u ON cm.user_id = u.id
 		JOIN positions p ON cm.position_id = p.id
 		WHERE cm.committee_id = $1 AND cm.ended_at IS NULL
-		ORDER BY p.rank ASC
+		ORDER BY p.rank ASC, cm.joined_at ASC
 	`
 	rows, err := r.db.Query(ctx, query, committeeID)
 	if err != nil {
@@ -164,7 +589,8 @@ func (r *Repository) GetCommitteeMembers(ctx context.Context, committeeID uuid.U
 		var m models.CommitteeMember
 		err := rows.Scan(
 			&m.ID, &m.CommitteeID, &m.UserID, &m.PositionID, &m.JoinedAt, &m.EndedAt, &m.IsActive,
-			&m.UserName, &m.PositionName, &m.PositionRank,
+			&m.UserName, &m.UserNameBn,
+			&m.PositionName, &m.PositionNameBn, &m.PositionRank,
 		)
 		if err != nil {
 			return nil, err
@@ -173,3 +599,292 @@ func (r *Repository) GetCommitteeMembers(ctx context.Context, committeeID uuid.U
 	}
 	return members, nil
 }
+
+// ListRosterMembers returns the current members of several committees with their contact
+// details and photos, rank-ordered and keyed by committee. Only roster exports use it.
+func (r *Repository) ListRosterMembers(ctx context.Context, committeeIDs []uuid.UUID) (map[uuid.UUID][]*models.CommitteeMember, error) {
+	members := make(map[uuid.UUID][]*models.CommitteeMember)
+	if len(committeeIDs) == 0 {
+		return members, nil
+	}
+	query := `
+		SELECT cm.id, cm.committee_id, cm.user_id, cm.position_id, cm.joined_at, cm.ended_at, cm.is_active,
+		       u.full_name as user_name, u.full_name_bn, u.phone, u.photo_url,
+		       p.name as position_name, COALESCE(p.name_bn, '') as position_name_bn, p.rank as position_rank
+		FROM committee_members cm
+		JOIN users u ON cm.user_id = u.id
+		JOIN positions p ON cm.position_id = p.id
+		WHERE cm.committee_id = ANY($1) AND cm.ended_at IS NULL
+		ORDER BY cm.committee_id, p.rank ASC, cm.joined_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, committeeIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.CommitteeMember
+		err := rows.Scan(
+			&m.ID, &m.CommitteeID, &m.UserID, &m.PositionID, &m.JoinedAt, &m.EndedAt, &m.IsActive,
+			&m.UserName, &m.UserNameBn, &m.Phone, &m.PhotoURL,
+			&m.PositionName, &m.PositionNameBn, &m.PositionRank,
+		)
+		if err != nil {
+			return nil, err
+		}
+		members[m.CommitteeID] = append(members[m.CommitteeID], &m)
+	}
+	return members, rows.Err()
+}
+
+// GetCommittee retrieves a committee by ID
+func (r *Repository) GetCommittee(ctx context.Context, id uuid.UUID) (*models.Committee, error) {
+	query := `
+		SELECT id, jurisdiction_id, type, status, formed_at, expires_at, approved_by, created_at, updated_at,
+		       parent_committee_id, name, mandate, created_by
+		FROM committees
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	var c models.Committee
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&c.ID, &c.JurisdictionID, &c.Type, &c.Status, &c.FormedAt, &c.ExpiresAt, &c.ApprovedBy, &c.CreatedAt, &c.UpdatedAt,
+		&c.ParentCommitteeID, &c.Name, &c.Mandate, &c.CreatedBy,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrCommitteeNotFound
+	}
+	return &c, err
+}
+
+// ROSTER
+
+// rosterSubtreeQuery walks a jurisdiction subtree and joins each node's active committee (if any)
+const rosterSubtreeQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id, 0 AS depth FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT j.id, s.depth + 1 FROM jurisdictions j
+		INNER JOIN subtree s ON j.parent_id = s.id
+		WHERE j.deleted_at IS NULL
+	)
+	SELECT j.id, j.level_id, j.parent_id, j.name, j.name_bn, jl.name, s.depth,
+	       c.id, c.type, c.status, c.formed_at, c.expires_at, c.approved_by, c.created_at, c.updated_at
+	FROM subtree s
+	JOIN jurisdictions j ON j.id = s.id
+	JOIN jurisdiction_levels jl ON j.level_id = jl.id
+	LEFT JOIN committees c ON c.jurisdiction_id = j.id AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+	ORDER BY s.depth ASC, jl.rank ASC, j.name ASC
+`
+
+// RosterNode is a row of the roster subtree walk
+type RosterNode struct {
+	Jurisdiction models.Jurisdiction
+	LevelName    string
+	Depth        int
+	Committee    *models.Committee
+}
+
+// ListRosterSubtree returns every jurisdiction in a subtree with its active committee
+func (r *Repository) ListRosterSubtree(ctx context.Context, rootID uuid.UUID) ([]*RosterNode, error) {
+	rows, err := r.db.Query(ctx, rosterSubtreeQuery, rootID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*RosterNode
+	for rows.Next() {
+		var n RosterNode
+		var cID *uuid.UUID
+		var cType, cStatus *string
+		var cCreated, cUpdated *time.Time
+		var c models.Committee
+		err := rows.Scan(
+			&n.Jurisdiction.ID, &n.Jurisdiction.LevelID, &n.Jurisdiction.ParentID, &n.Jurisdiction.Name, &n.Jurisdiction.NameBn,
+			&n.LevelName, &n.Depth,
+			&cID, &cType, &cStatus, &c.FormedAt, &c.ExpiresAt, &c.ApprovedBy, &cCreated, &cUpdated,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if cID != nil {
+			c.ID = *cID
+			c.JurisdictionID = n.Jurisdiction.ID
+			c.Type = *cType
+			c.Status = *cStatus
+			c.CreatedAt, c.UpdatedAt = *cCreated, *cUpdated
+			n.Committee = &c
+		}
+		list = append(list, &n)
+	}
+	return list, nil
+}
+
+// GetJurisdictionLevelName returns the level name of a jurisdiction
+func (r *Repository) GetJurisdictionLevelName(ctx context.Context, jurisdictionID uuid.UUID) (string, error) {
+	var name string
+	err := r.db.QueryRow(ctx, `
+		SELECT jl.name FROM jurisdictions j
+		JOIN jurisdiction_levels jl ON j.level_id = jl.id
+		WHERE j.id = $1
+	`, jurisdictionID).Scan(&name)
+	return name, err
+}
+
+// COVERAGE
+
+// ListCoverageRows walks a jurisdiction subtree and reports committee coverage for every node.
+// The head and secretary posts are the rank 1 and rank 2 positions (President / General Secretary,
+// or their Convener / Member Secretary equivalents).
+func (r *Repository) ListCoverageRows(ctx context.Context, rootID uuid.UUID) ([]*models.CoverageRow, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT j.id, s.depth + 1 FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+			WHERE j.deleted_at IS NULL
+		)
+		SELECT j.id, j.parent_id, j.name, j.name_bn, jl.name, s.depth,
+		       c.id, COALESCE(c.type::text, ''), c.expires_at,
+		       (SELECT COUNT(*) FROM committee_members cm
+		        WHERE cm.committee_id = c.id AND cm.ended_at IS NULL) AS member_count,
+		       EXISTS(SELECT 1 FROM committee_members cm JOIN positions p ON cm.position_id = p.id
+		              WHERE cm.committee_id = c.id AND cm.ended_at IS NULL AND p.rank = 1) AS has_head,
+		       EXISTS(SELECT 1 FROM committee_members cm JOIN positions p ON cm.position_id = p.id
+		              WHERE cm.committee_id = c.id AND cm.ended_at IS NULL AND p.rank = 2) AS has_secretary
+		FROM subtree s
+		JOIN jurisdictions j ON j.id = s.id
+		JOIN jurisdiction_levels jl ON j.level_id = jl.id
+		LEFT JOIN committees c ON c.jurisdiction_id = j.id AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+		ORDER BY s.depth ASC, jl.rank ASC, j.name ASC
+	`
+	rows, err := r.db.Query(ctx, query, rootID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var list []*models.CoverageRow
+	for rows.Next() {
+		var row models.CoverageRow
+		var hasHead, hasSecretary bool
+		err := rows.Scan(
+			&row.JurisdictionID, &row.ParentID, &row.Name, &row.NameBn, &row.Level, &row.Depth,
+			&row.CommitteeID, &row.CommitteeType, &row.ExpiresAt, &row.MemberCount, &hasHead, &hasSecretary,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		row.HasActiveCommittee = row.CommitteeID != nil
+		if row.HasActiveCommittee {
+			row.MissingPresident = !hasHead
+			row.MissingSecretary = !hasSecretary
+			if row.CommitteeType == models.TypeConvener && row.ExpiresAt != nil && row.ExpiresAt.Before(now) {
+				row.ConvenerOverdue = true
+				row.OverdueDays = int(now.Sub(*row.ExpiresAt).Hours() / 24)
+			}
+		}
+		list = append(list, &row)
+	}
+	return list, nil
+}
+
+// SUB-COMMITTEES
+
+// ListSubCommittees returns the sub-committees of a committee, active ones first
+func (r *Repository) ListSubCommittees(ctx context.Context, parentID uuid.UUID, includeInactive bool) ([]*models.Committee, error) {
+	query := `
+		SELECT c.id, c.jurisdiction_id, c.type, c.status, c.formed_at, c.expires_at, c.approved_by, c.created_at, c.updated_at,
+		       c.parent_committee_id, c.name, c.mandate, c.created_by,
+		       (SELECT COUNT(*) FROM committee_members cm WHERE cm.committee_id = c.id AND cm.ended_at IS NULL)
+		FROM committees c
+		WHERE c.parent_committee_id = $1 AND c.deleted_at IS NULL
+	`
+	if !includeInactive {
+		query += " AND c.status = 'active'"
+	}
+	query += " ORDER BY (c.status = 'active') DESC, c.created_at DESC"
+
+	rows, err := r.db.Query(ctx, query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Committee
+	for rows.Next() {
+		var c models.Committee
+		err := rows.Scan(
+			&c.ID, &c.JurisdictionID, &c.Type, &c.Status, &c.FormedAt, &c.ExpiresAt, &c.ApprovedBy, &c.CreatedAt, &c.UpdatedAt,
+			&c.ParentCommitteeID, &c.Name, &c.Mandate, &c.CreatedBy, &c.MemberCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &c)
+	}
+	return list, nil
+}
+
+// UpdateSubCommittee updates a sub-committee's name, mandate and end date
+func (r *Repository) UpdateSubCommittee(ctx context.Context, c *models.Committee) error {
+	query := `
+		UPDATE committees
+		SET name = $1, mandate = $2, expires_at = $3, updated_at = NOW()
+		WHERE id = $4 AND parent_committee_id IS NOT NULL AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(ctx, query, c.Name, c.Mandate, c.ExpiresAt, c.ID).Scan(&c.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrSubCommitteeNotFound
+	}
+	return err
+}
+
+// DissolveSubCommittee marks an active working group dissolved and closes its open memberships in one transaction
+func (r *Repository) DissolveSubCommittee(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE committees SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND parent_committee_id IS NOT NULL AND status = $3 AND deleted_at IS NULL
+	`, models.StatusDissolved, id, models.StatusActive)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSubCommitteeInactive
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE committee_members SET ended_at = NOW(), is_active = FALSE
+		WHERE committee_id = $1 AND ended_at IS NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// IsCommitteeLeader reports whether a user currently holds a leading position (by rank) on the committee
+func (r *Repository) IsCommitteeLeader(ctx context.Context, committeeID, userID uuid.UUID, maxRank int) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM committee_members cm
+			JOIN positions p ON cm.position_id = p.id
+			WHERE cm.committee_id = $1 AND cm.user_id = $2 AND cm.ended_at IS NULL AND cm.is_active = TRUE
+			  AND p.rank <= $3
+		)
+	`
+	var ok bool
+	err := r.db.QueryRow(ctx, query, committeeID, userID, maxRank).Scan(&ok)
+	return ok, err
+}
@@ -0,0 +1,281 @@
+package committee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/bjdms/api/internal/auth"
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/xlsx"
+	"github.com/google/uuid"
+)
+
+// Roster export limits
+const (
+	DefaultRosterDepth = 1
+	MaxRosterDepth     = 6
+)
+
+// ErrRosterAccess is returned when the caller may not see a jurisdiction's rosters
+var ErrRosterAccess = errors.New("only committee officials at or above the jurisdiction can view its rosters")
+
+// GetCommitteeRoster returns a committee with its jurisdiction context and rank-ordered members
+func (s *Service) GetCommitteeRoster(ctx context.Context, committeeID, userID uuid.UUID) (*models.RosterCommittee, error) {
+	c, err := s.repo.GetCommittee(ctx, committeeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRosterAccess(ctx, userID, c.JurisdictionID); err != nil {
+		return nil, err
+	}
+
+	j, err := s.repo.GetJurisdiction(ctx, c.JurisdictionID)
+	if err != nil {
+		return nil, err
+	}
+
+	levelName, err := s.repo.GetJurisdictionLevelName(ctx, j.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListRosterMembers(ctx, []uuid.UUID{committeeID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RosterCommittee{
+		Committee:          c,
+		JurisdictionName:   j.Name,
+		JurisdictionNameBn: j.NameBn,
+		LevelName:          levelName,
+		Members:            members[committeeID],
+	}, nil
+}
+
+// GetSubtreeRoster returns the active committee rosters of a jurisdiction and its descendants
+func (s *Service) GetSubtreeRoster(ctx context.Context, rootID, userID uuid.UUID, depth int) ([]*models.RosterCommittee, error) {
+	nodes, members, err := s.rosterSubtree(ctx, rootID, userID, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	var rosters []*models.RosterCommittee
+	for _, n := range nodes {
+		if n.Committee == nil {
+			continue
+		}
+		rosters = append(rosters, &models.RosterCommittee{
+			Committee:          n.Committee,
+			JurisdictionName:   n.Jurisdiction.Name,
+			JurisdictionNameBn: n.Jurisdiction.NameBn,
+			LevelName:          n.LevelName,
+			Depth:              n.Depth,
+			Members:            members[n.Committee.ID],
+		})
+	}
+	return rosters, nil
+}
+
+// GetOrgChart builds a jurisdiction tree with each node's active committee leadership
+func (s *Service) GetOrgChart(ctx context.Context, rootID, userID uuid.UUID, depth int, allMembers bool) (*models.OrgChartNode, error) {
+	nodes, members, err := s.rosterSubtree(ctx, rootID, userID, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows arrive breadth-first, so every parent is indexed before its children
+	index := make(map[uuid.UUID]*models.OrgChartNode)
+	var root *models.OrgChartNode
+	for _, n := range nodes {
+		node := &models.OrgChartNode{
+			ID:        n.Jurisdiction.ID,
+			Name:      n.Jurisdiction.Name,
+			NameBn:    n.Jurisdiction.NameBn,
+			Level:     n.LevelName,
+			LevelID:   n.Jurisdiction.LevelID,
+			Committee: n.Committee,
+		}
+
+		if n.Committee != nil {
+			for _, m := range members[n.Committee.ID] {
+				// Only leaders are shown on the org chart unless all members are requested
+				if allMembers || m.PositionRank <= auth.LeaderRank {
+					node.Members = append(node.Members, m)
+				}
+			}
+		}
+
+		index[node.ID] = node
+		if n.Depth == 0 {
+			root = node
+			continue
+		}
+		if n.Jurisdiction.ParentID != nil {
+			if parent, ok := index[*n.Jurisdiction.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+			}
+		}
+	}
+
+	return root, nil
+}
+
+// rosterSubtree returns the jurisdictions of a subtree down to depth, after checking the caller
+// may see them, with the members of their committees loaded in one query
+func (s *Service) rosterSubtree(ctx context.Context, rootID, userID uuid.UUID, depth int) ([]*RosterNode, map[uuid.UUID][]*models.CommitteeMember, error) {
+	if err := s.checkRosterAccess(ctx, userID, rootID); err != nil {
+		return nil, nil, err
+	}
+	all, err := s.repo.ListRosterSubtree(ctx, rootID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(all) == 0 {
+		return nil, nil, ErrJurisdictionNotFound
+	}
+
+	var nodes []*RosterNode
+	var committeeIDs []uuid.UUID
+	for _, n := range all {
+		if n.Depth > depth {
+			continue
+		}
+		nodes = append(nodes, n)
+		if n.Committee != nil {
+			committeeIDs = append(committeeIDs, n.Committee.ID)
+		}
+	}
+
+	members, err := s.repo.ListRosterMembers(ctx, committeeIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nodes, members, nil
+}
+
+// checkRosterAccess allows the Super Admin, and holders of a committee position whose
+// jurisdiction is the given one or above it. Rosters carry members' phone numbers.
+func (s *Service) checkRosterAccess(ctx context.Context, userID, jurisdictionID uuid.UUID) error {
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if authority.SuperAdmin {
+		return nil
+	}
+	if authority.JurisdictionID != nil && authority.Rank != auth.NoPositionRank {
+		ok, err := s.IsChildJurisdiction(ctx, *authority.JurisdictionID, jurisdictionID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return ErrRosterAccess
+}
+
+// WriteRosterXLSX writes rosters as a workbook with one sheet per committee
+func WriteRosterXLSX(w io.Writer, rosters []*models.RosterCommittee) error {
+	wb := xlsx.New()
+
+	for _, rc := range rosters {
+		sheet := wb.AddSheet(rc.JurisdictionName)
+		sheet.SetColumnWidths(6, 28, 28, 30, 30, 18, 14)
+
+		sheet.AddHeader(fmt.Sprintf("%s (%s) - %s committee", rc.JurisdictionName, rc.LevelName, rc.Committee.Type))
+		sheet.AddRow("Status", rc.Committee.Status, "Formed", rc.Committee.FormedAt, "Expires", rc.Committee.ExpiresAt)
+		sheet.AddRow()
+		sheet.AddHeader("#", "Position", "পদবি", "Name", "নাম", "Phone", "Joined")
+
+		for i, m := range rc.Members {
+			sheet.AddRow(i+1, m.PositionName, m.PositionNameBn, m.UserName, m.UserNameBn, m.Phone, m.JoinedAt)
+		}
+	}
+
+	if len(rosters) == 0 {
+		wb.AddSheet("Roster").AddRow("No active committees found")
+	}
+
+	return wb.Write(w)
+}
+
+// WriteRosterHTML writes a print-ready roster document used directly or as PDF source
+func WriteRosterHTML(w io.Writer, title string, rosters []*models.RosterCommittee) error {
+	return rosterTemplate.Execute(w, map[string]interface{}{
+		"Title":       title,
+		"GeneratedAt": time.Now(),
+		"Rosters":     rosters,
+	})
+}
+
+var rosterTemplate = template.Must(template.New("roster").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+	"date": func(t interface{}) string {
+		switch v := t.(type) {
+		case time.Time:
+			return v.Format("02 Jan 2006")
+		case *time.Time:
+			if v != nil {
+				return v.Format("02 Jan 2006")
+			}
+		}
+		return "-"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="bn">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+	@page { size: A4; margin: 14mm 12mm; }
+	body { font-family: "Noto Sans Bengali", "Noto Sans", "SolaimanLipi", sans-serif; font-size: 11pt; color: #111; }
+	h1 { font-size: 16pt; margin: 0 0 4px; }
+	.meta { color: #555; font-size: 9pt; margin-bottom: 16px; }
+	section { page-break-after: always; }
+	section:last-child { page-break-after: auto; }
+	h2 { font-size: 13pt; margin: 0; }
+	.sub { color: #444; font-size: 10pt; margin: 2px 0 10px; }
+	table { width: 100%; border-collapse: collapse; }
+	th, td { border: 1px solid #999; padding: 4px 6px; vertical-align: middle; text-align: left; }
+	th { background: #eee; }
+	td.photo { width: 44px; text-align: center; }
+	td.photo img { width: 40px; height: 48px; object-fit: cover; }
+	.bn { display: block; color: #333; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">Generated {{date .GeneratedAt}}</div>
+{{range .Rosters}}
+<section>
+	<h2>{{.JurisdictionName}}{{with .JurisdictionNameBn}} / {{.}}{{end}}</h2>
+	<div class="sub">{{.LevelName}} &middot; {{.Committee.Type}} committee &middot; {{.Committee.Status}} &middot; formed {{date .Committee.FormedAt}} &middot; expires {{date .Committee.ExpiresAt}}</div>
+	<table>
+		<thead><tr><th>#</th><th>Photo</th><th>Position / পদবি</th><th>Name / নাম</th><th>Phone</th></tr></thead>
+		<tbody>
+		{{range $i, $m := .Members}}
+			<tr>
+				<td>{{inc $i}}</td>
+				<td class="photo">{{with $m.PhotoURL}}<img src="{{.}}" alt="">{{end}}</td>
+				<td>{{$m.PositionName}}<span class="bn">{{$m.PositionNameBn}}</span></td>
+				<td>{{$m.UserName}}{{with $m.UserNameBn}}<span class="bn">{{.}}</span>{{end}}</td>
+				<td>{{$m.Phone}}</td>
+			</tr>
+		{{else}}
+			<tr><td colspan="5">No members</td></tr>
+		{{end}}
+		</tbody>
+	</table>
+</section>
+{{else}}
+<p>No active committees found.</p>
+{{end}}
+</body>
+</html>
+`))
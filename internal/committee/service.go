@@ -2,21 +2,28 @@ package committee
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/bjdms/api/internal/auth"
 	"github.com/bjdms/api/internal/models"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
 // Service defines business logic for committees and jurisdictions
 type Service struct {
-	repo *Repository
+	repo     *Repository
+	authRepo *auth.Repository
+	redis    *redis.Client
 }
 
-// NewService creates a new committee service
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+// NewService creates a new committee service. The redis client is optional and only used for report caching.
+func NewService(repo *Repository, authRepo *auth.Repository, rdb *redis.Client) *Service {
+	return &Service{repo: repo, authRepo: authRepo, redis: rdb}
 }
 
 // JURISDICTIONS
@@ -42,7 +49,11 @@ func (s *Service) CreateJurisdiction(ctx context.Context, j *models.Jurisdiction
 		j.ParentID = nil
 	}
 
-	return s.repo.CreateJurisdiction(ctx, j)
+	if err := s.repo.CreateJurisdiction(ctx, j); err != nil {
+		return err
+	}
+	s.invalidateCoverage(ctx)
+	return nil
 }
 
 // ListJurisdictionTree returns jurisdictions under a parent
@@ -50,22 +61,268 @@ func (s *Service) ListJurisdictionTree(ctx context.Context, parentID *uuid.UUID)
 	return s.repo.ListJurisdictions(ctx, nil, parentID)
 }
 
+// GEOGRAPHY
+
+// Geography errors
+var (
+	ErrInvalidBoundary      = errors.New("invalid boundary")
+	ErrOutsideBounds        = errors.New("coordinates are outside WGS84 bounds")
+	ErrNotFeatureCollection = errors.New("expected a GeoJSON FeatureCollection")
+)
+
+// BoundaryImportResult summarises a bulk GeoJSON import
+type BoundaryImportResult struct {
+	Imported int                   `json:"imported"`
+	Failed   []BoundaryImportError `json:"failed,omitempty"`
+}
+
+// BoundaryImportError describes a feature that could not be imported
+type BoundaryImportError struct {
+	Index int    `json:"index"`
+	Key   string `json:"key,omitempty"`
+	Error string `json:"error"`
+}
+
+// SetJurisdictionBoundary validates and stores a GeoJSON boundary for a jurisdiction
+func (s *Service) SetJurisdictionBoundary(ctx context.Context, id uuid.UUID, raw json.RawMessage) (*models.Jurisdiction, error) {
+	shape, normalised, err := ParseBoundary(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBoundary, err)
+	}
+
+	lat, lng := shape.Centroid()
+	if err := s.repo.SetJurisdictionBoundary(ctx, id, normalised, lat, lng, shape.BBox()); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetJurisdiction(ctx, id)
+}
+
+// ClearJurisdictionBoundary removes the stored boundary of a jurisdiction
+func (s *Service) ClearJurisdictionBoundary(ctx context.Context, id uuid.UUID) error {
+	return s.repo.ClearJurisdictionBoundary(ctx, id)
+}
+
+// ImportBoundaries stores boundaries from a FeatureCollection (e.g. ogr2ogr output of a shapefile).
+// Each feature is matched to a jurisdiction by the given property: a UUID property is matched by ID,
+// anything else by English or Bangla name, optionally restricted to a level.
+func (s *Service) ImportBoundaries(ctx context.Context, fc *FeatureCollection, matchProperty string, levelID *int) (*BoundaryImportResult, error) {
+	if fc.Type != GeoTypeFeatureCollection {
+		return nil, ErrNotFeatureCollection
+	}
+	if matchProperty == "" {
+		matchProperty = "jurisdiction_id"
+	}
+
+	result := &BoundaryImportResult{}
+	for i, f := range fc.Features {
+		key := fmt.Sprintf("%v", f.Properties[matchProperty])
+		if f.Properties[matchProperty] == nil {
+			result.Failed = append(result.Failed, BoundaryImportError{Index: i, Error: fmt.Sprintf("missing property %q", matchProperty)})
+			continue
+		}
+		if f.Geometry == nil {
+			result.Failed = append(result.Failed, BoundaryImportError{Index: i, Key: key, Error: "feature has no geometry"})
+			continue
+		}
+
+		id, err := s.matchJurisdiction(ctx, key, levelID)
+		if err != nil {
+			result.Failed = append(result.Failed, BoundaryImportError{Index: i, Key: key, Error: err.Error()})
+			continue
+		}
+
+		raw, _ := json.Marshal(f.Geometry)
+		if _, err := s.SetJurisdictionBoundary(ctx, id, raw); err != nil {
+			result.Failed = append(result.Failed, BoundaryImportError{Index: i, Key: key, Error: err.Error()})
+			continue
+		}
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+func (s *Service) matchJurisdiction(ctx context.Context, key string, levelID *int) (uuid.UUID, error) {
+	if id, err := uuid.Parse(key); err == nil {
+		return id, nil
+	}
+
+	matches, err := s.repo.FindJurisdictionsByName(ctx, strings.TrimSpace(key), levelID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	switch len(matches) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("no jurisdiction named %q", key)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return uuid.Nil, fmt.Errorf("name %q is ambiguous (%d matches); specify level_id or match by jurisdiction_id", key, len(matches))
+	}
+}
+
+// ResolveLocation returns the deepest jurisdiction whose boundary contains the given point
+func (s *Service) ResolveLocation(ctx context.Context, lat, lng float64) (*models.Jurisdiction, error) {
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, ErrOutsideBounds
+	}
+
+	// Candidates come ordered from the deepest level up
+	candidates, err := s.repo.ListJurisdictionsByPoint(ctx, lat, lng)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, j := range candidates {
+		shape, _, err := ParseBoundary(j.Boundary)
+		if err != nil {
+			continue
+		}
+		if shape.Contains(lat, lng) {
+			j.Boundary = nil
+			return j, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ExportSubtreeGeoJSON builds a FeatureCollection for a jurisdiction subtree with metrics attached
+func (s *Service) ExportSubtreeGeoJSON(ctx context.Context, rootID uuid.UUID, withMetrics bool) (*FeatureCollection, error) {
+	list, err := s.repo.ListSubtreeWithBoundaries(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrJurisdictionNotFound
+	}
+
+	var metrics map[uuid.UUID]*models.JurisdictionMetrics
+	if withMetrics {
+		metrics, err = s.repo.GetSubtreeMetrics(ctx, rootID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fc := &FeatureCollection{Type: GeoTypeFeatureCollection, Features: []*Feature{}}
+	for _, j := range list {
+		props := map[string]interface{}{
+			"id":        j.ID,
+			"level_id":  j.LevelID,
+			"parent_id": j.ParentID,
+			"name":      j.Name,
+			"name_bn":   j.NameBn,
+			"is_urban":  j.IsUrban,
+		}
+		if j.CentroidLat != nil && j.CentroidLng != nil {
+			props["centroid"] = []float64{*j.CentroidLng, *j.CentroidLat}
+		}
+		if m, ok := metrics[j.ID]; ok {
+			props["metrics"] = m
+		}
+
+		f := &Feature{Type: GeoTypeFeature, ID: j.ID, Properties: props}
+		if len(j.Boundary) > 0 {
+			var g Geometry
+			if err := json.Unmarshal(j.Boundary, &g); err == nil {
+				f.Geometry = &g
+			}
+		}
+		fc.Features = append(fc.Features, f)
+	}
+
+	return fc, nil
+}
+
 // COMMITTEES
 
 // CreateCommittee handles committee creation logic
 func (s *Service) CreateCommittee(ctx context.Context, c *models.Committee) error {
-	// 1. Verify jurisdiction exists
-	if _, err := s.repo.GetJurisdiction(ctx, c.JurisdictionID); err != nil {
-		return fmt.Errorf("jurisdiction not found: %w", err)
+	if err := s.prepareCommittee(ctx, c, false); err != nil {
+		return err
+	}
+	return s.repo.CreateCommittee(ctx, c)
+}
+
+// Seating errors
+var (
+	ErrStructureMismatch = errors.New("members do not fit the committee structure")
+	ErrTermLimit         = errors.New("term limit reached")
+	ErrAlreadyMember     = errors.New("user is already a member of this committee")
+	ErrPositionOccupied  = errors.New("position is already filled")
+	ErrMemberAccess      = errors.New("only the committee's leaders or leaders above its jurisdiction can add members")
+)
+
+// ValidateSuccessorCommittee prepares a proposed committee that will replace the jurisdiction's
+// active committee once activated (e.g. from election results), and checks that all the
+// given members fit its structure, seat quotas and term limits. Nothing is stored, so a caller
+// can then insert the committee and its members together.
+func (s *Service) ValidateSuccessorCommittee(ctx context.Context, c *models.Committee, members []*models.CommitteeMember) error {
+	c.Status = models.StatusProposed
+	if err := s.prepareCommittee(ctx, c, true); err != nil {
+		return err
 	}
 
-	// 2. Check for existing active committee
-	active, err := s.repo.GetActiveCommittee(ctx, c.JurisdictionID)
+	j, err := s.repo.GetJurisdiction(ctx, c.JurisdictionID)
+	if err != nil {
+		return err
+	}
+	structure, err := s.repo.GetCommitteeStructure(ctx, j.LevelID, c.Type)
 	if err != nil {
 		return err
 	}
-	if active != nil {
-		return fmt.Errorf("an active committee already exists for this jurisdiction")
+	if structure == nil {
+		return fmt.Errorf("%w: no committee structure is configured for %s committees at level %d", ErrStructureMismatch, c.Type, j.LevelID)
+	}
+	if structure.MaxMembers != nil && len(members) > *structure.MaxMembers {
+		return fmt.Errorf("%w: %s committee at level %d cannot exceed %d members", ErrStructureMismatch, c.Type, j.LevelID, *structure.MaxMembers)
+	}
+
+	seated := make(map[uuid.UUID]bool)
+	held := make(map[int]int)
+	for _, m := range members {
+		if seated[m.UserID] {
+			return fmt.Errorf("%w: user %s is seated more than once", ErrStructureMismatch, m.UserID)
+		}
+		seated[m.UserID] = true
+
+		quota, err := s.repo.GetPositionQuota(ctx, j.LevelID, c.Type, m.PositionID)
+		if err != nil {
+			return err
+		}
+		if quota == nil {
+			return fmt.Errorf("%w: position %d is not allowed in %s committees at level %d", ErrStructureMismatch, m.PositionID, c.Type, j.LevelID)
+		}
+		held[m.PositionID]++
+		if quota.MaxSeats != nil && held[m.PositionID] > *quota.MaxSeats {
+			return fmt.Errorf("%w: %s has only %d seat(s) in this committee", ErrStructureMismatch, quota.PositionName, *quota.MaxSeats)
+		}
+
+		if err := s.CheckTermLimit(ctx, m.UserID, m.PositionID, c.JurisdictionID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareCommittee checks a new committee's jurisdiction and sets its default status and expiry
+func (s *Service) prepareCommittee(ctx context.Context, c *models.Committee, successor bool) error {
+	// 1. Verify jurisdiction exists
+	if _, err := s.repo.GetJurisdiction(ctx, c.JurisdictionID); err != nil {
+		return fmt.Errorf("jurisdiction not found: %w", err)
+	}
+
+	// 2. Check for existing active committee (successors replace it on activation)
+	if !successor {
+		active, err := s.repo.GetActiveCommittee(ctx, c.JurisdictionID)
+		if err != nil {
+			return err
+		}
+		if active != nil {
+			return fmt.Errorf("an active committee already exists for this jurisdiction")
+		}
 	}
 
 	// 3. Set default status and expiry
@@ -83,68 +340,234 @@ func (s *Service) CreateCommittee(ctx context.Context, c *models.Committee) erro
 		c.ExpiresAt = &expiry
 	}
 
-	return s.repo.CreateCommittee(ctx, c)
+	return nil
 }
 
-// AddMember adds a member to a committee with size and position constraints
-func (s *Service) AddMember(ctx context.Context, m *models.CommitteeMember) error {
+// AddMember adds a member to a committee, enforcing the configured size limit and position quotas.
+// Leaders of the committee or of a jurisdiction above it may add members; sub-committee members
+// are managed by the parent committee's leadership.
+func (s *Service) AddMember(ctx context.Context, m *models.CommitteeMember, actorID uuid.UUID) error {
 	// 1. Get committee and jurisdiction details
-	query := `
-		SELECT c.id, c.type, c.status, j.level_id 
-		FROM committees c 
-		JOIN jurisdictions j ON c.jurisdiction_id = j.id 
-		WHERE c.id = $1
-	`
-	var cType, cStatus string
-	var levelID int
-	err := s.repo.db.QueryRow(ctx, query, m.CommitteeID).Scan(&m.CommitteeID, &cType, &cStatus, &levelID)
+	c, err := s.repo.GetCommittee(ctx, m.CommitteeID)
 	if err != nil {
-		return fmt.Errorf("committee not found: %w", err)
+		return err
 	}
 
-	// 2. Load existing members
+	// Sub-committees are not bound by level structures
+	if c.IsSubCommittee() {
+		if err := s.checkParentAccess(ctx, actorID, c); err != nil {
+			return err
+		}
+		return s.addSubCommitteeMember(ctx, m, c.Status)
+	}
+
+	manager, err := s.isCommitteeManager(ctx, actorID, c)
+	if err != nil {
+		return err
+	}
+	if !manager {
+		return ErrMemberAccess
+	}
+
+	j, err := s.repo.GetJurisdiction(ctx, c.JurisdictionID)
+	if err != nil {
+		return err
+	}
+	cType, levelID, jurisdictionID := c.Type, j.LevelID, c.JurisdictionID
+
+	// 2. Load the configured structure for this level and committee type
+	structure, err := s.repo.GetCommitteeStructure(ctx, levelID, cType)
+	if err != nil {
+		return err
+	}
+	if structure == nil {
+		return fmt.Errorf("%w: no committee structure is configured for %s committees at level %d", ErrStructureMismatch, cType, levelID)
+	}
+
+	quota, err := s.repo.GetPositionQuota(ctx, levelID, cType, m.PositionID)
+	if err != nil {
+		return err
+	}
+	if quota == nil {
+		return fmt.Errorf("%w: position %d is not allowed in %s committees at level %d", ErrStructureMismatch, m.PositionID, cType, levelID)
+	}
+
+	// 3. Load existing members
 	members, err := s.repo.GetCommitteeMembers(ctx, m.CommitteeID)
 	if err != nil {
 		return err
 	}
 
-	// 3. Size constraints
-	maxSize := 151 // Default for District Full
-	if cType == models.TypeConvener {
-		maxSize = 11
-		if len(members) >= maxSize {
-			return fmt.Errorf("convener committee cannot exceed %d members", maxSize)
+	// 4. Size constraints
+	if structure.MaxMembers != nil && len(members) >= *structure.MaxMembers {
+		return fmt.Errorf("%w: %s committee at level %d cannot exceed %d members", ErrStructureMismatch, cType, levelID, *structure.MaxMembers)
+	}
+
+	// 5. Duplication and seat quota
+	held := 0
+	for _, member := range members {
+		if member.UserID == m.UserID {
+			return ErrAlreadyMember
 		}
-	} else {
-		// Full Committee limits by level (as per docs)
-		switch levelID {
-		case 3: maxSize = 151 // District
-		case 4: maxSize = 101 // Upazila/Municipality
-		case 5: maxSize = 71  // Union/Ward
-		case 6: maxSize = 31  // Ward
-		default: maxSize = 151 // Central/Division
+		if member.PositionID == m.PositionID {
+			held++
 		}
-		if len(members) >= maxSize {
-			return fmt.Errorf("full committee at level %d cannot exceed %d members", levelID, maxSize)
+	}
+	if quota.MaxSeats != nil && held >= *quota.MaxSeats {
+		if *quota.MaxSeats == 1 {
+			return fmt.Errorf("%w: the position of %s is already occupied in this committee", ErrPositionOccupied, quota.PositionName)
 		}
+		return fmt.Errorf("%w: all %d seats for %s are already filled in this committee", ErrPositionOccupied, *quota.MaxSeats, quota.PositionName)
 	}
 
-	// 4. Position uniqueness and duplication
-	for _, member := range members {
-		if member.UserID == m.UserID {
-			return fmt.Errorf("user is already a member of this committee")
-		}
-		
-		// Only 1 President, General Secretary, Convener, Member Secretary
-		if m.PositionID == member.PositionID {
-			// Check if this position is unique (Rank 1 or 2 usually)
-			if m.PositionRank <= 2 {
-				return fmt.Errorf("this position is already occupied in this committee")
+	// 6. Term limits from tenure history
+	if err := s.CheckTermLimit(ctx, m.UserID, m.PositionID, jurisdictionID, &m.CommitteeID); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddMember(ctx, m); err != nil {
+		return err
+	}
+	s.invalidateCoverage(ctx)
+	return nil
+}
+
+// POSITIONS
+
+// ListPositions returns the position catalog
+func (s *Service) ListPositions(ctx context.Context) ([]*models.Position, error) {
+	return s.repo.ListPositions(ctx)
+}
+
+// CreatePosition adds a new position to the catalog
+func (s *Service) CreatePosition(ctx context.Context, p *models.Position) error {
+	if err := validatePosition(p); err != nil {
+		return err
+	}
+	return s.repo.CreatePosition(ctx, p)
+}
+
+// UpdatePosition changes a position's names, rank or applicability
+func (s *Service) UpdatePosition(ctx context.Context, p *models.Position) error {
+	if err := validatePosition(p); err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePosition(ctx, p); err != nil {
+		return err
+	}
+	s.invalidateCoverage(ctx)
+	return nil
+}
+
+// DeletePosition removes a position that no member has ever held
+func (s *Service) DeletePosition(ctx context.Context, id int) error {
+	count, err := s.repo.CountPositionHolders(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("position is referenced by %d memberships and cannot be deleted", count)
+	}
+	if err := s.repo.DeletePosition(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateCoverage(ctx)
+	return nil
+}
+
+// CheckTermLimit returns an error if holding the position in this jurisdiction would exceed its term limit.
+// The committee being staffed (if any) is excluded so re-adding to it is not counted twice.
+func (s *Service) CheckTermLimit(ctx context.Context, userID uuid.UUID, positionID int, jurisdictionID uuid.UUID, committeeID *uuid.UUID) error {
+	p, err := s.repo.GetPosition(ctx, positionID)
+	if err != nil {
+		return err
+	}
+	if p.MaxTerms == nil {
+		return nil
+	}
+
+	terms, err := s.repo.CountPositionTerms(ctx, userID, positionID, jurisdictionID, committeeID)
+	if err != nil {
+		return err
+	}
+	if terms >= *p.MaxTerms {
+		return fmt.Errorf("%w: user has already served %d term(s) as %s in this jurisdiction (limit %d)", ErrTermLimit, terms, p.Name, *p.MaxTerms)
+	}
+	return nil
+}
+
+func validatePosition(p *models.Position) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("position name is required")
+	}
+	if p.Rank < 1 {
+		return fmt.Errorf("position rank must be a positive number")
+	}
+	if p.MaxTerms != nil && *p.MaxTerms < 1 {
+		return fmt.Errorf("max_terms must be a positive number")
+	}
+	switch p.CommitteeType {
+	case "":
+		p.CommitteeType = models.PositionTypeBoth
+	case models.PositionTypeFull, models.PositionTypeConvener, models.PositionTypeSubCommittee, models.PositionTypeBoth:
+	default:
+		return fmt.Errorf("committee_type must be Full, Convener, SubCommittee or Both")
+	}
+	return nil
+}
+
+// ListCommitteeStructures returns committee size limits and position quotas
+func (s *Service) ListCommitteeStructures(ctx context.Context, levelID *int) ([]*models.CommitteeStructure, error) {
+	return s.repo.ListCommitteeStructures(ctx, levelID)
+}
+
+// SetCommitteeStructure replaces the size limit and allowed positions for a level and committee type
+func (s *Service) SetCommitteeStructure(ctx context.Context, cs *models.CommitteeStructure) error {
+	if cs.CommitteeType != models.TypeFull && cs.CommitteeType != models.TypeConvener {
+		return fmt.Errorf("committee type must be %s or %s", models.TypeFull, models.TypeConvener)
+	}
+	if cs.MaxMembers != nil && *cs.MaxMembers < 1 {
+		return fmt.Errorf("max_members must be positive")
+	}
+
+	// Validate positions and make sure fixed seats fit into the committee
+	seen := make(map[int]bool)
+	fixedSeats := 0
+	for _, q := range cs.Positions {
+		if seen[q.PositionID] {
+			return fmt.Errorf("position %d is listed more than once", q.PositionID)
+		}
+		seen[q.PositionID] = true
+
+		p, err := s.repo.GetPosition(ctx, q.PositionID)
+		if err != nil {
+			return fmt.Errorf("position %d: %w", q.PositionID, err)
+		}
+		if p.CommitteeType != models.PositionTypeBoth && !strings.EqualFold(p.CommitteeType, cs.CommitteeType) {
+			return fmt.Errorf("position %s is only valid in %s committees", p.Name, p.CommitteeType)
+		}
+		if q.MaxSeats != nil {
+			if *q.MaxSeats < 1 {
+				return fmt.Errorf("max_seats for %s must be positive", p.Name)
 			}
+			fixedSeats += *q.MaxSeats
 		}
 	}
+	if cs.MaxMembers != nil && fixedSeats > *cs.MaxMembers {
+		return fmt.Errorf("position quotas (%d seats) exceed the committee size of %d", fixedSeats, *cs.MaxMembers)
+	}
 
-	return s.repo.AddMember(ctx, m)
+	if err := s.repo.ReplaceCommitteeStructure(ctx, cs); err != nil {
+		return err
+	}
+	s.invalidateCoverage(ctx)
+	return nil
+}
+
+// IsCommitteeLeader reports whether a user currently holds a leading position (up to
+// auth.LeaderRank) on the committee
+func (s *Service) IsCommitteeLeader(ctx context.Context, committeeID, userID uuid.UUID) (bool, error) {
+	return s.repo.IsCommitteeLeader(ctx, committeeID, userID, auth.LeaderRank)
 }
 
 // IsChildJurisdiction checks if targetID is a sub-unit of parentID (recursive)
@@ -189,7 +612,7 @@ func (s *Service) ActivateCommittee(ctx context.Context, id uuid.UUID, approvedB
 	}
 	defer tx.Rollback(ctx)
 
-	// 3. Dissolve existing active committee in this jurisdiction
+	// 3. Dissolve existing active committee in this jurisdiction (its sub-committees are wound up with it)
 	dissolveQuery := `UPDATE committees SET status = 'dissolved', updated_at = NOW() WHERE jurisdiction_id = $1 AND status = 'active'`
 	_, err = tx.Exec(ctx, dissolveQuery, c.JurisdictionID)
 	if err != nil {
@@ -203,5 +626,196 @@ func (s *Service) ActivateCommittee(ctx context.Context, id uuid.UUID, approvedB
 		return fmt.Errorf("failed to activate committee: %w", err)
 	}
 
-	return tx.Commit(ctx)
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	s.invalidateCoverage(ctx)
+	return nil
+}
+
+// SUB-COMMITTEES
+
+// Sub-committee errors
+var (
+	ErrSubCommitteeAccess       = errors.New("only the parent committee's leaders or leaders above its jurisdiction can manage sub-committees")
+	ErrNestedSubCommittee       = errors.New("sub-committees cannot be nested")
+	ErrParentInactive           = errors.New("sub-committees can only be formed under an active committee")
+	ErrSubCommitteeNameRequired = errors.New("sub-committee name is required")
+	ErrExpiryInPast             = errors.New("end date must be in the future")
+)
+
+// CreateSubCommittee forms a working group under an active committee. Sub-committees are active
+// immediately, share the parent's jurisdiction and cannot be nested.
+func (s *Service) CreateSubCommittee(ctx context.Context, parentID, userID uuid.UUID, c *models.Committee) error {
+	// 1. Parent must be an active main committee
+	parent, err := s.repo.GetCommittee(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if parent.IsSubCommittee() {
+		return ErrNestedSubCommittee
+	}
+	if parent.Status != models.StatusActive {
+		return ErrParentInactive
+	}
+
+	// 2. Only the parent's leadership or leaders above it may form working groups
+	if err := s.checkSubCommitteeAccess(ctx, userID, parent); err != nil {
+		return err
+	}
+
+	// 3. Validate mandate details
+	if c.Name == nil || strings.TrimSpace(*c.Name) == "" {
+		return ErrSubCommitteeNameRequired
+	}
+	if c.ExpiresAt != nil && !c.ExpiresAt.After(time.Now()) {
+		return ErrExpiryInPast
+	}
+
+	now := time.Now()
+	c.ParentCommitteeID = &parent.ID
+	c.JurisdictionID = parent.JurisdictionID
+	c.Type = models.TypeSubCommittee
+	c.Status = models.StatusActive
+	c.FormedAt = &now
+	return s.repo.CreateCommittee(ctx, c)
+}
+
+// ListSubCommittees returns a committee's working groups
+func (s *Service) ListSubCommittees(ctx context.Context, parentID uuid.UUID, includeInactive bool) ([]*models.Committee, error) {
+	return s.repo.ListSubCommittees(ctx, parentID, includeInactive)
+}
+
+// GetSubCommittee retrieves a sub-committee with its members
+func (s *Service) GetSubCommittee(ctx context.Context, id uuid.UUID) (*models.Committee, []*models.CommitteeMember, error) {
+	c, err := s.repo.GetCommittee(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !c.IsSubCommittee() {
+		return nil, nil, ErrSubCommitteeNotFound
+	}
+	members, err := s.repo.GetCommitteeMembers(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, members, nil
+}
+
+// UpdateSubCommittee changes a sub-committee's name, mandate or end date
+func (s *Service) UpdateSubCommittee(ctx context.Context, userID uuid.UUID, c *models.Committee) error {
+	if err := s.checkParentAccess(ctx, userID, c); err != nil {
+		return err
+	}
+	if c.Name == nil || strings.TrimSpace(*c.Name) == "" {
+		return ErrSubCommitteeNameRequired
+	}
+	if c.Status != models.StatusActive {
+		return ErrSubCommitteeInactive
+	}
+	return s.repo.UpdateSubCommittee(ctx, c)
+}
+
+// DissolveSubCommittee winds up a working group and ends its memberships
+func (s *Service) DissolveSubCommittee(ctx context.Context, id, userID uuid.UUID) error {
+	c, err := s.repo.GetCommittee(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !c.IsSubCommittee() {
+		return ErrSubCommitteeNotFound
+	}
+	if err := s.checkParentAccess(ctx, userID, c); err != nil {
+		return err
+	}
+	if c.Status != models.StatusActive {
+		return fmt.Errorf("cannot dissolve a %s sub-committee: %w", c.Status, ErrSubCommitteeInactive)
+	}
+
+	return s.repo.DissolveSubCommittee(ctx, id)
+}
+
+// checkParentAccess loads a sub-committee's parent and applies checkSubCommitteeAccess to it
+func (s *Service) checkParentAccess(ctx context.Context, userID uuid.UUID, c *models.Committee) error {
+	if c.ParentCommitteeID == nil {
+		return ErrSubCommitteeNotFound
+	}
+	parent, err := s.repo.GetCommittee(ctx, *c.ParentCommitteeID)
+	if err != nil {
+		return err
+	}
+	return s.checkSubCommitteeAccess(ctx, userID, parent)
+}
+
+// checkSubCommitteeAccess allows Super Admins, leaders of the parent committee and
+// leaders whose jurisdiction sits above the parent's
+func (s *Service) checkSubCommitteeAccess(ctx context.Context, userID uuid.UUID, parent *models.Committee) error {
+	manager, err := s.isCommitteeManager(ctx, userID, parent)
+	if err != nil {
+		return err
+	}
+	if !manager {
+		return ErrSubCommitteeAccess
+	}
+	return nil
+}
+
+// isCommitteeManager reports whether the user is a Super Admin, a leader of the committee or
+// a leader whose jurisdiction sits above the committee's
+func (s *Service) isCommitteeManager(ctx context.Context, userID uuid.UUID, c *models.Committee) (bool, error) {
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if authority.SuperAdmin {
+		return true, nil
+	}
+
+	leader, err := s.repo.IsCommitteeLeader(ctx, c.ID, userID, auth.LeaderRank)
+	if err != nil {
+		return false, err
+	}
+	if leader {
+		return true, nil
+	}
+
+	if authority.IsLeader() && authority.JurisdictionID != nil && *authority.JurisdictionID != c.JurisdictionID {
+		return s.IsChildJurisdiction(ctx, *authority.JurisdictionID, c.JurisdictionID)
+	}
+	return false, nil
+}
+
+// addSubCommitteeMember seats a member in a working group. Lead positions (up to auth.LeaderRank) hold one seat each.
+func (s *Service) addSubCommitteeMember(ctx context.Context, m *models.CommitteeMember, status string) error {
+	if status != models.StatusActive {
+		return fmt.Errorf("cannot add members: %w", ErrSubCommitteeInactive)
+	}
+
+	// 1. Position must be usable in sub-committees
+	p, err := s.repo.GetPosition(ctx, m.PositionID)
+	if err != nil {
+		return err
+	}
+	if p.CommitteeType != models.PositionTypeSubCommittee && p.CommitteeType != models.PositionTypeBoth {
+		return fmt.Errorf("%w: position %s cannot be held in a sub-committee", ErrStructureMismatch, p.Name)
+	}
+
+	// 2. Duplication and lead seats
+	members, err := s.repo.GetCommitteeMembers(ctx, m.CommitteeID)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if member.EndedAt != nil {
+			continue
+		}
+		if member.UserID == m.UserID {
+			return ErrAlreadyMember
+		}
+		if member.PositionID == m.PositionID && p.Rank <= auth.LeaderRank {
+			return fmt.Errorf("%w: the position of %s is already occupied in this sub-committee", ErrPositionOccupied, p.Name)
+		}
+	}
+
+	return s.repo.AddMember(ctx, m)
 }
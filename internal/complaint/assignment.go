@@ -0,0 +1,241 @@
+package complaint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/google/uuid"
+)
+
+// Assignment and routing errors
+var (
+	ErrNotLeader          = errors.New("only committee leaders at or above the complaint's jurisdiction can do this")
+	ErrAssignClosed       = errors.New("a closed or rejected complaint cannot be assigned")
+	ErrAssignAccused      = errors.New("a complaint cannot be assigned to a member it names")
+	ErrAlreadyAssigned    = errors.New("complaint is already assigned to this official")
+	ErrAssigneeNotOfficer = errors.New("complaints can only be assigned to active committee officers")
+	ErrAssigneeOutOfReach = errors.New("complaints can only be assigned to officers at or above the complaint's jurisdiction")
+	ErrOfficerRequired    = errors.New("officer_id is required to route to a complaint officer")
+	ErrPositionsRequired  = errors.New("position_ids is required for round-robin routing")
+	ErrUnknownRoutingMode = fmt.Errorf("mode must be %q, %q or %q", models.RoutingManual, models.RoutingOfficer, models.RoutingRoundRobin)
+)
+
+// AssignComplaint hands a complaint to an official, or moves it to another one. The assignee
+// must be an active committee officer at or above the complaint's jurisdiction.
+func (s *Service) AssignComplaint(ctx context.Context, id, actorID, assigneeID uuid.UUID, note string) (*models.Complaint, error) {
+	// 1. Validate
+	note = strings.TrimSpace(note)
+	if len(note) > MaxNoteLength {
+		return nil, ErrNoteTooLong
+	}
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkConflict(ctx, c, actorID); err != nil {
+		return nil, err
+	}
+	if err := s.checkLeaderAccess(ctx, actorID, c.JurisdictionID, "assign complaints"); err != nil {
+		return nil, err
+	}
+	s.auditOpened(c, actorID)
+	if c.Status == models.ComplaintStatusClosed || c.Status == models.ComplaintStatusRejected {
+		return nil, ErrAssignClosed
+	}
+	if err := s.checkAssignee(ctx, assigneeID, c.JurisdictionID); err != nil {
+		return nil, err
+	}
+	if len(s.excludeAccused(ctx, c, []uuid.UUID{assigneeID})) == 0 {
+		return nil, ErrAssignAccused
+	}
+
+	// 2. Assign
+	previous, err := s.repo.Assign(ctx, id, assigneeID, &actorID, note)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Tell the new and previous assignees
+	if assigneeID != actorID {
+		s.notifyAssignee(ctx, c, assigneeID)
+	}
+	if previous != nil && *previous != actorID {
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         *previous,
+			Type:           notification.TypeComplaintAssign,
+			Title:          "Complaint Reassigned",
+			Message:        fmt.Sprintf("Complaint %s has been reassigned to another official.", c.TrackingID),
+			JurisdictionID: c.JurisdictionID,
+		})
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetRouting returns a jurisdiction's routing rule; jurisdictions without one are manual
+func (s *Service) GetRouting(ctx context.Context, jurisdictionID, userID uuid.UUID) (*models.ComplaintRouting, error) {
+	if err := s.checkAccess(ctx, userID, jurisdictionID); err != nil {
+		return nil, err
+	}
+	rt, err := s.repo.GetRouting(ctx, jurisdictionID)
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		rt = &models.ComplaintRouting{JurisdictionID: jurisdictionID, Mode: models.RoutingManual, PositionIDs: []int{}}
+	}
+	return rt, nil
+}
+
+// UpdateRouting sets how new complaints in a jurisdiction are assigned
+func (s *Service) UpdateRouting(ctx context.Context, userID uuid.UUID, rt *models.ComplaintRouting) error {
+	if err := s.checkLeaderAccess(ctx, userID, rt.JurisdictionID, "change complaint routing"); err != nil {
+		return err
+	}
+	if rt.PositionIDs == nil {
+		rt.PositionIDs = []int{}
+	}
+
+	switch rt.Mode {
+	case models.RoutingManual:
+		rt.OfficerID = nil
+	case models.RoutingOfficer:
+		if rt.OfficerID == nil {
+			return ErrOfficerRequired
+		}
+		if err := s.checkAssignee(ctx, *rt.OfficerID, rt.JurisdictionID); err != nil {
+			return err
+		}
+	case models.RoutingRoundRobin:
+		if len(rt.PositionIDs) == 0 {
+			return ErrPositionsRequired
+		}
+		rt.OfficerID = nil
+	default:
+		return ErrUnknownRoutingMode
+	}
+
+	rt.UpdatedBy = &userID
+	return s.repo.UpsertRouting(ctx, rt)
+}
+
+// routeComplaint assigns a new complaint according to the rule of the jurisdiction whose queue
+// it is in. A designated officer who is no longer available is replaced by the round-robin
+// pool, if one is set. Complaints that cannot be routed are announced to the nearest committee
+// leaders instead. Members the complaint names are never picked or alerted.
+func (s *Service) routeComplaint(ctx context.Context, c *models.Complaint) {
+	queue := c.JurisdictionID
+	if c.EscalatedToID != nil {
+		queue = *c.EscalatedToID
+	}
+
+	assigneeID, err := s.pickAssignee(ctx, queue)
+	if err != nil {
+		log.Printf("complaints: routing %s failed: %v", c.TrackingID, err)
+	}
+	if assigneeID != nil && len(s.excludeAccused(ctx, c, []uuid.UUID{*assigneeID})) == 0 {
+		assigneeID = nil
+	}
+
+	if assigneeID != nil {
+		if _, err := s.repo.Assign(ctx, c.ID, *assigneeID, nil, "Assigned automatically"); err != nil {
+			log.Printf("complaints: assigning %s failed: %v", c.TrackingID, err)
+		} else {
+			c.AssignedToID = assigneeID
+			s.notifyAssignee(ctx, c, *assigneeID)
+			return
+		}
+	}
+
+	leaders, err := s.repo.ListLeaderIDs(ctx, queue)
+	if err != nil {
+		log.Printf("complaints: no leaders alerted for %s: %v", c.TrackingID, err)
+		return
+	}
+	for _, id := range s.excludeAccused(ctx, c, leaders) {
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         id,
+			Type:           notification.TypeComplaintAlert,
+			Title:          "New Complaint Filed",
+			Message:        fmt.Sprintf("Tracking ID: %s. A new complaint has been submitted and needs to be assigned.", c.TrackingID),
+			JurisdictionID: queue,
+		})
+	}
+}
+
+func (s *Service) pickAssignee(ctx context.Context, jurisdictionID uuid.UUID) (*uuid.UUID, error) {
+	rt, err := s.repo.GetRouting(ctx, jurisdictionID)
+	if err != nil || rt == nil {
+		return nil, err
+	}
+
+	switch rt.Mode {
+	case models.RoutingOfficer:
+		if err := s.checkAssignee(ctx, *rt.OfficerID, jurisdictionID); err == nil {
+			return rt.OfficerID, nil
+		}
+		if len(rt.PositionIDs) > 0 {
+			return s.repo.NextRoundRobin(ctx, jurisdictionID, rt.PositionIDs)
+		}
+	case models.RoutingRoundRobin:
+		return s.repo.NextRoundRobin(ctx, jurisdictionID, rt.PositionIDs)
+	}
+	return nil, nil
+}
+
+// checkAssignee makes sure an official can take a complaint: an active committee officer
+// whose jurisdiction is the complaint's or above it
+func (s *Service) checkAssignee(ctx context.Context, assigneeID, jurisdictionID uuid.UUID) error {
+	ok, err := s.repo.IsActiveOfficer(ctx, assigneeID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAssigneeNotOfficer
+	}
+	official, err := s.IsOfficial(ctx, assigneeID, jurisdictionID)
+	if err != nil {
+		return err
+	}
+	if !official {
+		return ErrAssigneeOutOfReach
+	}
+	return nil
+}
+
+// checkLeaderAccess allows the Super Admin and committee leaders (positions up to auth.LeaderRank)
+// at or above the jurisdiction
+func (s *Service) checkLeaderAccess(ctx context.Context, userID, jurisdictionID uuid.UUID, action string) error {
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if authority.SuperAdmin {
+		return nil
+	}
+	if authority.JurisdictionID != nil && authority.IsLeader() {
+		ok, err := s.org.IsChildJurisdiction(ctx, *authority.JurisdictionID, jurisdictionID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot %s: %w", action, ErrNotLeader)
+}
+
+func (s *Service) notifyAssignee(ctx context.Context, c *models.Complaint, assigneeID uuid.UUID) {
+	s.notification.Create(ctx, &notification.Notification{
+		UserID:         assigneeID,
+		Type:           notification.TypeComplaintAssign,
+		Title:          "Complaint Assigned",
+		Message:        fmt.Sprintf("Complaint %s (\"%s\") has been assigned to you.", c.TrackingID, c.Subject),
+		JurisdictionID: c.JurisdictionID,
+	})
+}
@@ -0,0 +1,188 @@
+package complaint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Accused errors
+var (
+	ErrAccusedNameTooLong = fmt.Errorf("accused name must be at most %d characters", MaxComplainantLength)
+	ErrPositionNotFound   = errors.New("accused position not found")
+)
+
+// Audit actions on conflict-of-interest complaints
+const (
+	AuditComplaintAccessed = "complaint_accessed"
+	AuditComplaintDenied   = "complaint_access_denied"
+)
+
+// resolveAccused matches the members and positions a complaint names against the committee of
+// its jurisdiction. When any of them sits on that committee the complaint is a conflict of
+// interest and goes to the parent jurisdiction's queue instead.
+func (s *Service) resolveAccused(ctx context.Context, c *models.Complaint) error {
+	var resolved []*models.ComplaintAccused
+	seen := make(map[uuid.UUID]bool)
+
+	for _, claim := range c.Accused {
+		name := ""
+		if claim.Name != nil {
+			name = strings.TrimSpace(*claim.Name)
+		}
+		if len(name) > MaxComplainantLength {
+			return ErrAccusedNameTooLong
+		}
+		if name == "" && claim.PositionID == nil {
+			continue
+		}
+		if claim.PositionID != nil {
+			posName, err := s.repo.GetPositionName(ctx, *claim.PositionID)
+			if err != nil {
+				return err
+			}
+			if posName == "" {
+				return ErrPositionNotFound
+			}
+		}
+
+		members, err := s.repo.FindCommitteeMembers(ctx, c.JurisdictionID, claim.PositionID, name)
+		if err != nil {
+			return err
+		}
+		var namePtr *string
+		if name != "" {
+			namePtr = &name
+		}
+
+		// Nobody on the committee matches; keep what the complainant said
+		if len(members) == 0 {
+			resolved = append(resolved, &models.ComplaintAccused{PositionID: claim.PositionID, Name: namePtr})
+			continue
+		}
+		for _, m := range members {
+			if seen[*m.UserID] {
+				continue
+			}
+			seen[*m.UserID] = true
+			m.Name = namePtr
+			resolved = append(resolved, m)
+		}
+	}
+	c.Accused = resolved
+
+	if len(seen) == 0 {
+		return nil
+	}
+	c.ConflictOfInterest = true
+	parentID, parentName, err := s.repo.GetParentJurisdiction(ctx, c.JurisdictionID)
+	if err != nil {
+		return err
+	}
+	// At the top of the hierarchy there is nobody above; the complaint stays, hidden from the accused
+	c.EscalatedToID = parentID
+	c.EscalatedToName = parentName
+	return nil
+}
+
+// checkConflict hides a conflict-of-interest complaint from the members it names, as if it did
+// not exist, and audits the attempt. Callers audit a successful opening with auditOpened once
+// their own access checks have passed too.
+func (s *Service) checkConflict(ctx context.Context, c *models.Complaint, userID uuid.UUID) error {
+	if !c.ConflictOfInterest {
+		return nil
+	}
+	accused, err := s.isAccused(ctx, c, userID)
+	if err != nil {
+		return err
+	}
+	if accused {
+		s.auditAccess(c, userID, AuditComplaintDenied)
+		return ErrComplaintNotFound
+	}
+	return nil
+}
+
+// auditOpened records that a user passed every check to open a conflict-of-interest complaint
+func (s *Service) auditOpened(c *models.Complaint, userID uuid.UUID) {
+	if c.ConflictOfInterest {
+		s.auditAccess(c, userID, AuditComplaintAccessed)
+	}
+}
+
+func (s *Service) isAccused(ctx context.Context, c *models.Complaint, userID uuid.UUID) (bool, error) {
+	ids, err := s.repo.ListAccusedUserIDs(ctx, c.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range ids {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// excludeAccused drops the members a complaint names from a list of users to alert. If they
+// cannot be looked up nobody is alerted, rather than risk telling the accused.
+func (s *Service) excludeAccused(ctx context.Context, c *models.Complaint, ids []uuid.UUID) []uuid.UUID {
+	if !c.ConflictOfInterest || len(ids) == 0 {
+		return ids
+	}
+	accused, err := s.repo.ListAccusedUserIDs(ctx, c.ID)
+	if err != nil {
+		log.Printf("complaints: accused members of %s unknown, alerts withheld: %v", c.TrackingID, err)
+		return nil
+	}
+	skip := make(map[uuid.UUID]bool, len(accused))
+	for _, id := range accused {
+		skip[id] = true
+	}
+
+	var list []uuid.UUID
+	for _, id := range ids {
+		if !skip[id] {
+			list = append(list, id)
+		}
+	}
+	return list
+}
+
+// auditAccess records an attempt to open a conflict-of-interest complaint
+func (s *Service) auditAccess(c *models.Complaint, userID uuid.UUID, action string) {
+	entry := &models.AuditLog{
+		UserID:   &userID,
+		Action:   action,
+		Entity:   "complaint",
+		EntityID: &c.ID,
+		Metadata: map[string]interface{}{
+			"tracking_id": c.TrackingID,
+			"reason":      "conflict_of_interest",
+		},
+	}
+
+	// Async log to not block request
+	go func() {
+		if err := s.authRepo.CreateAuditLog(context.Background(), entry); err != nil {
+			log.Printf("complaints: audit of %s failed: %v", c.TrackingID, err)
+		}
+	}()
+}
+
+// ListCommitteePositions returns the positions held in a jurisdiction's committee, so a
+// complainant can name one without knowing who holds it
+func (s *Service) ListCommitteePositions(ctx context.Context, jurisdictionID uuid.UUID) ([]*models.Position, error) {
+	ok, err := s.repo.JurisdictionExists(ctx, jurisdictionID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrJurisdictionNotFound
+	}
+	return s.repo.ListCommitteePositions(ctx, jurisdictionID)
+}
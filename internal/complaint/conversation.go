@@ -0,0 +1,110 @@
+package complaint
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/google/uuid"
+)
+
+// MaxMessageLength limits a complainant's message, as comments are limited
+const MaxMessageLength = 5000
+
+// Message errors
+var (
+	ErrMessageRequired = errors.New("message body is required")
+	ErrMessageTooLong  = fmt.Errorf("message must be at most %d characters", MaxMessageLength)
+	ErrMessagesClosed  = errors.New("messages cannot be added to a closed or rejected complaint")
+)
+
+// AuthenticateComplainant returns a complaint to whoever holds both its tracking ID and its
+// secret. A wrong secret looks the same as an unknown tracking ID.
+func (s *Service) AuthenticateComplainant(ctx context.Context, trackingID, secret string) (*models.Complaint, error) {
+	if !ValidTrackingID(trackingID) || secret == "" {
+		return nil, ErrComplaintNotFound
+	}
+	return s.repo.GetByTrackingIDAndSecret(ctx, trackingID, hashSecret(secret))
+}
+
+// ListMessages returns the public thread of a complaint the complainant has opened
+func (s *Service) ListMessages(ctx context.Context, c *models.Complaint) ([]*models.ComplaintMessage, error) {
+	return s.repo.ListPublicMessages(ctx, c.ID)
+}
+
+// PostMessage adds the complainant's message to the complaint's public thread and tells the
+// officials handling it
+func (s *Service) PostMessage(ctx context.Context, c *models.Complaint, body string) (*models.ComplaintMessage, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrMessageRequired
+	}
+	if len([]rune(body)) > MaxMessageLength {
+		return nil, ErrMessageTooLong
+	}
+	if c.Status == models.ComplaintStatusClosed || c.Status == models.ComplaintStatusRejected {
+		return nil, ErrMessagesClosed
+	}
+
+	m := &models.ComplaintMessage{Body: body}
+	if err := s.repo.CreateComplainantMessage(ctx, c.ID, m); err != nil {
+		return nil, err
+	}
+
+	s.notifyHandlers(ctx, c)
+	return m, nil
+}
+
+// notifyHandlers tells the assignee, or without one the leaders of the queue holding the
+// complaint, that the complainant wrote
+func (s *Service) notifyHandlers(ctx context.Context, c *models.Complaint) {
+	queue := c.JurisdictionID
+	if c.EscalatedToID != nil {
+		queue = *c.EscalatedToID
+	}
+
+	var recipients []uuid.UUID
+	if c.AssignedToID != nil {
+		recipients = []uuid.UUID{*c.AssignedToID}
+	} else {
+		leaders, err := s.repo.ListLeaderIDs(ctx, queue)
+		if err != nil {
+			log.Printf("complaints: nobody told of a message on %s: %v", c.TrackingID, err)
+			return
+		}
+		recipients = leaders
+	}
+
+	for _, id := range s.excludeAccused(ctx, c, recipients) {
+		s.notification.Create(ctx, &notification.Notification{
+			UserID:         id,
+			Type:           notification.TypeComplaintReply,
+			Title:          "Complainant Replied",
+			Message:        fmt.Sprintf("The complainant has added a message to complaint %s.", c.TrackingID),
+			JurisdictionID: queue,
+		})
+	}
+}
+
+// newAccessSecret issues a complaint secret, returning it and the hash to store
+func newAccessSecret() (string, string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(buf)
+	return secret, hashSecret(secret), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
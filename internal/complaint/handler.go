@@ -2,80 +2,161 @@ package complaint
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/bjdms/api/internal/abuse"
 	"github.com/bjdms/api/internal/middleware"
 	"github.com/bjdms/api/internal/models"
 	"github.com/bjdms/api/pkg/response"
+	"github.com/bjdms/api/pkg/storage"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
+// Public endpoint limits, counted per client IP
+const (
+	submitLimit        = 10 // Complaints per submitWindow
+	submitWindow       = 24 * time.Hour
+	lookupLimit        = 30 // Status checks per lookupWindow
+	lookupWindow       = 15 * time.Minute
+	uploadLimit        = 20 // Evidence uploads per submitWindow
+	messageLimit       = 20 // Complainant messages per submitWindow
+	maxSubmissionBytes = 64 << 10
+)
+
+// secretHeader carries the complainant's secret, so it stays out of URLs and access logs
+const secretHeader = "X-Complaint-Secret"
+
 // Handler handles HTTP requests for complaints
 type Handler struct {
-	service *Service
+	service      *Service
+	uploader     *storage.Uploader
+	anonUploader *storage.Uploader
+
+	// Kept on the handler so the counts survive remounting the public router
+	submitLimiter  *middleware.RateLimiter
+	lookupLimiter  *middleware.RateLimiter
+	uploadLimiter  *middleware.RateLimiter
+	messageLimiter *middleware.RateLimiter
 }
 
 // NewHandler creates a new complaint handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, uploader *storage.Uploader) *Handler {
+	return &Handler{
+		service:        service,
+		uploader:       uploader,
+		anonUploader:   uploader.WithTypes(AnonymousEvidenceTypes...),
+		submitLimiter:  middleware.NewRateLimiter(submitLimit, submitWindow),
+		lookupLimiter:  middleware.NewRateLimiter(lookupLimit, lookupWindow),
+		uploadLimiter:  middleware.NewRateLimiter(uploadLimit, submitWindow),
+		messageLimiter: middleware.NewRateLimiter(messageLimit, submitWindow),
+	}
 }
 
-// Routes defines routes for complaints
-func (h *Handler) Routes() chi.Router {
+// PublicRoutes returns the anonymous complainant's endpoints. They are rate limited per IP here,
+// so they are safe to mount without authentication. The tracking ID alone shows the status;
+// the conversation and evidence also need the secret issued on submission.
+func (h *Handler) PublicRoutes() chi.Router {
 	r := chi.NewRouter()
 
-	// Public Routes
-	r.Group(func(r chi.Router) {
-		r.Post("/public", h.SubmitAnonymous)
-		r.Get("/status/{tracking_id}", h.CheckStatus)
-	})
+	r.With(h.submitLimiter.Limit).Post("/submit", h.SubmitAnonymous)
+	r.With(h.lookupLimiter.Limit).Get("/status/{tracking_id}", h.CheckStatus)
+	r.With(h.lookupLimiter.Limit).Get("/status/{tracking_id}/conversation", h.ViewConversation)
+	r.With(h.messageLimiter.Limit).Post("/status/{tracking_id}/messages", h.PostMessage)
+	r.With(h.uploadLimiter.Limit).Post("/status/{tracking_id}/evidence", h.UploadPublicEvidence)
+	r.With(h.lookupLimiter.Limit).Get("/positions/{jurisdiction_id}", h.ListPositions)
 
-	// Protected Management Routes
-	r.Group(func(r chi.Router) {
-		// Middleware is applied in main.go (Auth + Jurisdiction ABAC)
-		r.Get("/", h.ListComplaints)
-		r.Get("/{tracking_id}", h.GetDetailed)
-		r.Patch("/{id}/status", h.UpdateStatus)
-	})
+	return r
+}
+
+// InternalRoutes returns the complaint management endpoints. They must be mounted behind
+// AuthMiddleware; requests without a user are rejected regardless.
+func (h *Handler) InternalRoutes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(requireUser)
+
+	r.Get("/", h.ListComplaints)
+	r.Get("/{tracking_id}", h.GetDetailed)
+	r.Patch("/{id}/status", h.UpdateStatus)
+	r.Post("/{id}/assign", h.Assign)
+	r.Post("/{id}/moderation", h.Moderate)
+	r.Post("/{id}/evidence", h.UploadEvidence)
+	r.Get("/{id}/evidence", h.ListEvidence)
+
+	// SLA compliance
+	r.Get("/sla", h.SLAStats)
+
+	// Submissions held as suspected spam
+	r.Get("/moderation", h.ListFlagged)
+
+	// Auto-routing rules
+	r.Get("/routing/{jurisdiction_id}", h.GetRouting)
+	r.Put("/routing/{jurisdiction_id}", h.UpdateRouting)
 
 	return r
 }
 
-// SubmitAnonymous handles POST /api/v1/complaints/public
+// requireUser rejects requests that did not pass authentication
+func requireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := uuid.Parse(middleware.GetUserID(r.Context())); err != nil {
+			response.Unauthorized(w, "Authentication required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SubmitAnonymous handles POST /api/v1/public/complaints/submit
 func (h *Handler) SubmitAnonymous(w http.ResponseWriter, r *http.Request) {
-	var c models.Complaint
-	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+	// Only the fields a complainant may set are read; status, assignment and notes are not
+	var req struct {
+		JurisdictionID    uuid.UUID `json:"jurisdiction_id"`
+		Subject           string    `json:"subject"`
+		Description       string    `json:"description"`
+		AccusedName       *string   `json:"accused_name"`        // Member the complaint is about, if any
+		AccusedPositionID *int      `json:"accused_position_id"` // Or their position, from ListPositions
+		abuse.Form
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxSubmissionBytes)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.BadRequest(w, "Invalid request body")
 		return
 	}
 
-	// For public submissions, ensure it's anonymous unless we'll allow auth later
-	c.IsAnonymous = true
-	c.UserID = nil
+	// Public submissions are always anonymous
+	c := models.Complaint{
+		JurisdictionID: req.JurisdictionID,
+		IsAnonymous:    true,
+		Subject:        req.Subject,
+		Description:    req.Description,
+	}
+	if req.AccusedName != nil || req.AccusedPositionID != nil {
+		c.Accused = []*models.ComplaintAccused{{Name: req.AccusedName, PositionID: req.AccusedPositionID}}
+	}
 
-	// Get IP for the service to hash
-	ip := r.RemoteAddr
+	// Get IP for the service to fingerprint
+	ip := middleware.ClientIP(r)
 
-	if err := h.service.SubmitComplaint(r.Context(), &c, ip); err != nil {
-		response.InternalError(w, "Failed to submit complaint", "")
+	if err := h.service.SubmitComplaint(r.Context(), &c, ip, req.Form); err != nil {
+		writeError(w, err)
 		return
 	}
 
 	response.Created(w, map[string]string{
 		"tracking_id": c.TrackingID,
+		"secret":      c.AccessSecret,
 		"status":      c.Status,
-	}, "Complaint submitted successfully. Please save your tracking ID.")
+	}, "Complaint submitted successfully. Please save your tracking ID and secret; the secret cannot be recovered.")
 }
 
-// CheckStatus handles GET /api/v1/complaints/status/{tracking_id}
+// CheckStatus handles GET /api/v1/public/complaints/status/{tracking_id}
 func (h *Handler) CheckStatus(w http.ResponseWriter, r *http.Request) {
-	trackingID := chi.URLParam(r, "tracking_id")
-	if trackingID == "" {
-		response.BadRequest(w, "Tracking ID is required")
-		return
-	}
+	trackingID := strings.ToUpper(chi.URLParam(r, "tracking_id"))
 
 	c, err := h.service.GetComplaintStatus(r.Context(), trackingID)
 	if err != nil {
@@ -83,18 +164,100 @@ func (h *Handler) CheckStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// We only return public info
-	pubInfo := map[string]interface{}{
+	// The tracking ID alone only reveals the status
+	response.Success(w, map[string]interface{}{
+		"tracking_id": c.TrackingID,
+		"status":      c.Status,
+		"created_at":  c.CreatedAt,
+	}, "")
+}
+
+// ViewConversation handles GET /api/v1/public/complaints/status/{tracking_id}/conversation,
+// the complainant's view of the complaint with the officials' public replies
+func (h *Handler) ViewConversation(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := h.service.ListMessages(r.Context(), c)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch messages", "")
+		return
+	}
+	if messages == nil {
+		messages = []*models.ComplaintMessage{}
+	}
+
+	view := map[string]interface{}{
 		"tracking_id": c.TrackingID,
 		"status":      c.Status,
 		"created_at":  c.CreatedAt,
 		"subject":     c.Subject,
+		"messages":    messages,
 	}
 	if c.ResolutionNotes != nil {
-		pubInfo["resolution_notes"] = *c.ResolutionNotes
+		view["resolution_notes"] = *c.ResolutionNotes
 	}
+	response.Success(w, view, "")
+}
 
-	response.Success(w, pubInfo, "")
+// PostMessage handles POST /api/v1/public/complaints/status/{tracking_id}/messages
+func (h *Handler) PostMessage(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Body string `json:"body"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxSubmissionBytes)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	m, err := h.service.PostMessage(r.Context(), c, req.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, m, "Message sent")
+}
+
+// authenticate resolves the complaint for a request carrying its tracking ID and secret
+func (h *Handler) authenticate(w http.ResponseWriter, r *http.Request) (*models.Complaint, bool) {
+	trackingID := strings.ToUpper(chi.URLParam(r, "tracking_id"))
+	c, err := h.service.AuthenticateComplainant(r.Context(), trackingID, r.Header.Get(secretHeader))
+	if err != nil {
+		response.NotFound(w, "Complaint not found")
+		return nil, false
+	}
+	return c, true
+}
+
+// ListPositions handles GET /api/v1/public/complaints/positions/{jurisdiction_id}, the positions
+// a complainant can name as accused. Only positions are listed, never who holds them.
+func (h *Handler) ListPositions(w http.ResponseWriter, r *http.Request) {
+	jurisID, err := uuid.Parse(chi.URLParam(r, "jurisdiction_id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	list, err := h.service.ListCommitteePositions(r.Context(), jurisID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	positions := make([]map[string]interface{}, 0, len(list))
+	for _, p := range list {
+		positions = append(positions, map[string]interface{}{"id": p.ID, "name": p.Name, "name_bn": p.NameBn})
+	}
+	response.Success(w, positions, "")
 }
 
 // ListComplaints handles GET /api/v1/complaints
@@ -110,13 +273,14 @@ func (h *Handler) ListComplaints(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
 	status := r.URL.Query().Get("status")
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
 
-	list, err := h.service.ListJurisdictionComplaints(r.Context(), jurisID, status, page, pageSize)
+	list, err := h.service.ListJurisdictionComplaints(r.Context(), jurisID, userID, status, page, pageSize)
 	if err != nil {
-		response.InternalError(w, "Failed to fetch complaints", "")
+		writeError(w, err)
 		return
 	}
 
@@ -125,10 +289,12 @@ func (h *Handler) ListComplaints(w http.ResponseWriter, r *http.Request) {
 
 // GetDetailed handles GET /api/v1/complaints/{tracking_id}
 func (h *Handler) GetDetailed(w http.ResponseWriter, r *http.Request) {
-	trackingID := chi.URLParam(r, "tracking_id")
-	c, err := h.service.repo.GetByTrackingID(r.Context(), trackingID)
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	trackingID := strings.ToUpper(chi.URLParam(r, "tracking_id"))
+
+	c, err := h.service.GetComplaint(r.Context(), trackingID, userID)
 	if err != nil {
-		response.NotFound(w, "Complaint not found")
+		writeError(w, err)
 		return
 	}
 
@@ -156,10 +322,290 @@ func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.UpdateComplaintStatus(r.Context(), id, userID, req.Status, req.Note); err != nil {
-		response.InternalError(w, "Failed to update status", "")
+	c, err := h.service.UpdateComplaintStatus(r.Context(), id, userID, req.Status, req.Note)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
-	response.Success(w, nil, "Status updated successfully")
+	response.Success(w, c, "Status updated successfully")
+}
+
+// Assign handles POST /api/v1/complaints/{id}/assign; it also reassigns
+func (h *Handler) Assign(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid complaint ID")
+		return
+	}
+
+	var req struct {
+		AssigneeID uuid.UUID `json:"assignee_id"`
+		Note       string    `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AssigneeID == uuid.Nil {
+		response.BadRequest(w, "assignee_id is required")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	c, err := h.service.AssignComplaint(r.Context(), id, userID, req.AssigneeID, req.Note)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, c, "Complaint assigned")
+}
+
+// ListFlagged handles GET /api/v1/complaints/moderation?jurisdiction_id=
+func (h *Handler) ListFlagged(w http.ResponseWriter, r *http.Request) {
+	jurisID, err := uuid.Parse(r.URL.Query().Get("jurisdiction_id"))
+	if err != nil {
+		response.BadRequest(w, "jurisdiction_id is required")
+		return
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	list, err := h.service.ListFlagged(r.Context(), jurisID, userID, page, pageSize)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// Moderate handles POST /api/v1/complaints/{id}/moderation with a decision of "approve" or "spam"
+func (h *Handler) Moderate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid complaint ID")
+		return
+	}
+
+	var req struct {
+		Decision string `json:"decision"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	c, err := h.service.ModerateComplaint(r.Context(), id, userID, req.Decision)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, c, "Moderation decision recorded")
+}
+
+// GetRouting handles GET /api/v1/complaints/routing/{jurisdiction_id}
+func (h *Handler) GetRouting(w http.ResponseWriter, r *http.Request) {
+	jurisID, err := uuid.Parse(chi.URLParam(r, "jurisdiction_id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	rt, err := h.service.GetRouting(r.Context(), jurisID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, rt, "")
+}
+
+// UpdateRouting handles PUT /api/v1/complaints/routing/{jurisdiction_id}
+func (h *Handler) UpdateRouting(w http.ResponseWriter, r *http.Request) {
+	jurisID, err := uuid.Parse(chi.URLParam(r, "jurisdiction_id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid jurisdiction ID")
+		return
+	}
+
+	var rt models.ComplaintRouting
+	if err := json.NewDecoder(r.Body).Decode(&rt); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	rt.JurisdictionID = jurisID
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.UpdateRouting(r.Context(), userID, &rt); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, rt, "Complaint routing updated")
+}
+
+// SLAStats handles GET /api/v1/complaints/sla?jurisdiction_id=&from=&to=
+// (dates as YYYY-MM-DD; the last 30 days by default)
+func (h *Handler) SLAStats(w http.ResponseWriter, r *http.Request) {
+	jurisID, err := uuid.Parse(r.URL.Query().Get("jurisdiction_id"))
+	if err != nil {
+		response.BadRequest(w, "jurisdiction_id is required")
+		return
+	}
+
+	to := time.Now()
+	if v := r.URL.Query().Get("to"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.BadRequest(w, "Invalid to date, expected YYYY-MM-DD")
+			return
+		}
+		to = d.AddDate(0, 0, 1) // Inclusive
+	}
+	from := to.AddDate(0, 0, -30)
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			response.BadRequest(w, "Invalid from date, expected YYYY-MM-DD")
+			return
+		}
+	}
+
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	list, err := h.service.GetSLAStats(r.Context(), jurisID, userID, from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// UploadPublicEvidence handles POST /api/v1/public/complaints/status/{tracking_id}/evidence.
+// The tracking ID and secret are the complainant's only credentials, so evidence is stored
+// without an uploader.
+func (h *Handler) UploadPublicEvidence(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	h.acceptEvidence(w, r, c, nil)
+}
+
+// UploadEvidence handles POST /api/v1/complaints/{id}/evidence
+func (h *Handler) UploadEvidence(w http.ResponseWriter, r *http.Request) {
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid complaint ID")
+		return
+	}
+
+	c, err := h.service.GetComplaintByID(r.Context(), id, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	h.acceptEvidence(w, r, c, &userID)
+}
+
+func (h *Handler) acceptEvidence(w http.ResponseWriter, r *http.Request, c *models.Complaint, uploadedBy *uuid.UUID) {
+	// Check the complaint is open before accepting any bytes
+	if err := h.service.CanAttachEvidence(r.Context(), c, 1); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Anonymous evidence is limited to images whose metadata the media pipeline removes
+	uploader := h.uploader
+	if uploadedBy == nil || c.IsAnonymous {
+		uploader = h.anonUploader
+	}
+	files, err := uploader.FromRequest(w, r, "files")
+	if err != nil {
+		if status := storage.UploadError(err); status != http.StatusInternalServerError {
+			response.Error(w, status, "upload_rejected", err.Error(), "")
+			return
+		}
+		response.InternalError(w, "Failed to store upload", "")
+		return
+	}
+
+	list, err := h.service.AttachEvidence(r.Context(), c, uploadedBy, files)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Anonymous uploaders only learn how many files were accepted
+	if uploadedBy == nil {
+		response.Created(w, map[string]int{"files": len(list)}, "Evidence uploaded successfully")
+		return
+	}
+	response.Created(w, list, "Evidence uploaded successfully")
+}
+
+// ListEvidence handles GET /api/v1/complaints/{id}/evidence
+func (h *Handler) ListEvidence(w http.ResponseWriter, r *http.Request) {
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid complaint ID")
+		return
+	}
+
+	list, err := h.service.ListEvidence(r.Context(), id, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	if status := StatusError(err); status != 0 {
+		response.Error(w, status, "invalid_status_change", err.Error(), "")
+		return
+	}
+	if status := abuse.StatusError(err); status != 0 {
+		response.Error(w, status, "submission_refused", err.Error(), "")
+		return
+	}
+	switch {
+	case matchesAny(err, notFoundErrors):
+		response.NotFound(w, err.Error())
+	case matchesAny(err, forbiddenErrors):
+		response.Forbidden(w, err.Error())
+	case matchesAny(err, conflictErrors):
+		response.Conflict(w, err.Error())
+	case matchesAny(err, invalidErrors):
+		response.BadRequest(w, err.Error())
+	default:
+		// Database and other internal failures are not shown to the client
+		response.InternalError(w, "Failed to process complaint request", "")
+	}
+}
+
+// Service errors by response status; anything else is an internal error
+var (
+	notFoundErrors  = []error{ErrComplaintNotFound, ErrJurisdictionNotFound, ErrPositionNotFound}
+	forbiddenErrors = []error{ErrNotOfficial, ErrNotLeader}
+	conflictErrors  = []error{ErrAlreadyAssigned, ErrNotAwaitingModeration, ErrEvidenceClosed, ErrMessagesClosed, ErrAssignClosed}
+	invalidErrors   = []error{
+		ErrSubjectRequired, ErrSubjectTooLong, ErrDescriptionTooLong, ErrComplainantTooLong, ErrJurisdictionRequired,
+		ErrAccusedNameTooLong, ErrTooMuchEvidence, ErrMessageRequired, ErrMessageTooLong, ErrUnknownDecision,
+		ErrAssignAccused, ErrAssigneeNotOfficer, ErrAssigneeOutOfReach, ErrOfficerRequired, ErrPositionsRequired,
+		ErrUnknownRoutingMode, ErrInvalidRange, ErrRangeTooLong,
+	}
+)
+
+func matchesAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,135 @@
+package complaint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bjdms/api/internal/auth"
+	"github.com/bjdms/api/internal/middleware"
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	testComplaintID    = "7d1f3f5e-2b9a-4c35-9a51-0f6d8c3e2a10"
+	testJurisdictionID = "3b8e2c61-5f0a-4d7e-8c29-1a6f4e9d0b52"
+	testUserID         = "c4a9d2e7-8b13-4f60-a5d8-2e7b9c1f3a46"
+)
+
+// memberAuthority reports every user as a committee member without a position
+type memberAuthority struct{}
+
+func (memberAuthority) GetUserAuthority(ctx context.Context, userID uuid.UUID) (*auth.Authority, error) {
+	jurisdictionID := uuid.MustParse(testJurisdictionID)
+	return &auth.Authority{JurisdictionID: &jurisdictionID, Rank: auth.NoPositionRank}, nil
+}
+
+func (memberAuthority) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return nil
+}
+
+func newTestHandler() *Handler {
+	return NewHandler(&Service{}, storage.NewUploader(nil, 0, 0))
+}
+
+// internalRequests are management endpoints that must never answer without a signed-in user
+var internalRequests = []struct {
+	name, method, path string
+}{
+	{"list", http.MethodGet, "/"},
+	{"detail", http.MethodGet, "/C-2025-ABCDEF"},
+	{"status update", http.MethodPatch, "/" + testComplaintID + "/status"},
+	{"assign", http.MethodPost, "/" + testComplaintID + "/assign"},
+	{"evidence", http.MethodGet, "/" + testComplaintID + "/evidence"},
+	{"sla", http.MethodGet, "/sla"},
+	{"moderation queue", http.MethodGet, "/moderation"},
+	{"routing", http.MethodGet, "/routing/" + testComplaintID},
+}
+
+func TestInternalRoutesRequireUser(t *testing.T) {
+	router := newTestHandler().InternalRoutes()
+
+	for _, tt := range internalRequests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{"status":"closed"}`))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+func TestListRequiresOfficial(t *testing.T) {
+	h := NewHandler(&Service{authRepo: memberAuthority{}}, storage.NewUploader(nil, 0, 0))
+	router := h.InternalRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, "/?jurisdiction_id="+testJurisdictionID, nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, testUserID))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("list by a member without a position = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestPublicRoutesHideInternalPaths(t *testing.T) {
+	router := newTestHandler().PublicRoutes()
+
+	for _, tt := range internalRequests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("%s %s = %d on the public router, want %d", tt.method, tt.path, rec.Code, http.StatusNotFound)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"complaint not found", ErrComplaintNotFound, http.StatusNotFound, ErrComplaintNotFound.Error()},
+		{"leader action", fmt.Errorf("cannot assign complaints: %w", ErrNotLeader), http.StatusForbidden, "cannot assign complaints"},
+		{"not an official", ErrNotOfficial, http.StatusForbidden, ErrNotOfficial.Error()},
+		{"already assigned", ErrAlreadyAssigned, http.StatusConflict, ErrAlreadyAssigned.Error()},
+		{"validation", ErrSubjectTooLong, http.StatusBadRequest, ErrSubjectTooLong.Error()},
+		{"status change", fmt.Errorf("%w: closed to received", ErrInvalidTransition), http.StatusConflict, "closed to received"},
+		{"database error", errors.New(`ERROR: relation "complaints" does not exist (SQLSTATE 42P01)`), http.StatusInternalServerError, ""},
+		{"message ending in not found", errors.New("row not found"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := rec.Body.String()
+			if tt.wantBody != "" && !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body %s does not contain %q", body, tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(body, tt.err.Error()) {
+				t.Errorf("internal error leaked to the client: %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package complaint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Moderation errors
+var (
+	ErrUnknownDecision       = fmt.Errorf("decision must be %q or %q", models.ModerationApprove, models.ModerationReject)
+	ErrNotAwaitingModeration = errors.New("complaint is not awaiting moderation")
+)
+
+// ListFlagged returns the complaints in a jurisdiction's queue that screening held for
+// moderation, to committee leaders at or above it
+func (s *Service) ListFlagged(ctx context.Context, jurisdictionID, userID uuid.UUID, page, pageSize int) ([]*models.Complaint, error) {
+	if err := s.checkLeaderAccess(ctx, userID, jurisdictionID, "moderate complaints"); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 20
+	}
+	return s.repo.ListFlagged(ctx, jurisdictionID, userID, pageSize, (page-1)*pageSize)
+}
+
+// ModerateComplaint clears a flagged complaint, which is then routed like any new one, or
+// confirms it as spam, which hides it for good
+func (s *Service) ModerateComplaint(ctx context.Context, id, userID uuid.UUID, decision string) (*models.Complaint, error) {
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkConflict(ctx, c, userID); err != nil {
+		return nil, err
+	}
+	if err := s.checkLeaderAccess(ctx, userID, c.JurisdictionID, "moderate complaints"); err != nil {
+		return nil, err
+	}
+	s.auditOpened(c, userID)
+
+	var moderation, note string
+	switch decision {
+	case models.ModerationApprove:
+		moderation, note = models.ModerationClear, "Cleared by a moderator"
+	case models.ModerationReject:
+		moderation, note = models.ModerationSpam, "Confirmed as spam"
+	default:
+		return nil, ErrUnknownDecision
+	}
+	if err := s.repo.Moderate(ctx, id, userID, moderation, note); err != nil {
+		return nil, err
+	}
+
+	c, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if moderation == models.ModerationClear {
+		s.routeComplaint(ctx, c)
+	}
+	return c, nil
+}
@@ -3,7 +3,9 @@ package complaint
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/bjdms/api/internal/auth"
 	"github.com/bjdms/api/internal/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -20,61 +22,133 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
-// CreateComplaint inserts a new complaint
+// CreateComplaint inserts a new complaint with the members it accuses in a transaction. A
+// conflict-of-interest complaint starts in the queue of c.EscalatedToID.
 func (r *Repository) CreateComplaint(ctx context.Context, c *models.Complaint) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Insert the complaint
 	query := `
 		INSERT INTO complaints (
 			tracking_id, user_id, jurisdiction_id, is_anonymous, 
 			complainant_name, complainant_contact, subject, description, 
-			status, anonymous_ip_hash
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, created_at, updated_at
+			status, anonymous_ip_hash, conflict_of_interest, escalated_to_id,
+			escalated_at, access_secret_hash, moderation_status, moderation_reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CASE WHEN $12::uuid IS NULL THEN NULL ELSE NOW() END, $13, $14, $15)
+		RETURNING id, created_at, updated_at, status_changed_at, escalated_at
 	`
-	return r.db.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		c.TrackingID, c.UserID, c.JurisdictionID, c.IsAnonymous,
 		c.ComplainantName, c.ComplainantContact, c.Subject, c.Description,
-		c.Status, c.AnonymousIPHash,
-	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+		c.Status, c.AnonymousIPHash, c.ConflictOfInterest, c.EscalatedToID, c.AccessSecretHash,
+		c.ModerationStatus, c.ModerationReason,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt, &c.StatusChangedAt, &c.EscalatedAt)
+	if err != nil {
+		return err
+	}
+
+	// 2. Record who it is about
+	for _, a := range c.Accused {
+		a.ComplaintID = c.ID
+		err = tx.QueryRow(ctx, `
+			INSERT INTO complaint_accused (complaint_id, user_id, position_id, name, in_committee)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at
+		`, c.ID, a.UserID, a.PositionID, a.Name, a.InCommittee).Scan(&a.ID, &a.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 3. Log the routing past the jurisdiction's own committee
+	if c.EscalatedToID != nil {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO complaint_logs (complaint_id, action, old_status, new_status, note)
+			VALUES ($1, $2, $3, $3, 'Names a member of the jurisdiction''s committee; routed to the parent jurisdiction')
+		`, c.ID, models.ComplaintActionEscalated, c.Status)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
-// GetByTrackingID retrieves a complaint by its human-readable tracking ID
-func (r *Repository) GetByTrackingID(ctx context.Context, trackingID string) (*models.Complaint, error) {
-	query := `
-		SELECT c.id, c.tracking_id, c.user_id, c.jurisdiction_id, c.is_anonymous, 
-		       c.complainant_name, c.complainant_contact, c.subject, c.description, 
-		       c.status, c.assigned_to_id, c.resolution_notes, c.created_at, c.updated_at,
-		       j.name as jurisdiction_name
-		FROM complaints c
-		JOIN jurisdictions j ON c.jurisdiction_id = j.id
-		WHERE c.tracking_id = $1 AND c.deleted_at IS NULL
-	`
-	var c models.Complaint
-	err := r.db.QueryRow(ctx, query, trackingID).Scan(
+// JurisdictionExists reports whether complaints can be filed against a jurisdiction
+func (r *Repository) JurisdictionExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL)", id).Scan(&ok)
+	return ok, err
+}
+
+// complaintSelect reads complaints with the joined names; callers add the WHERE clause
+const complaintSelect = `
+	SELECT c.id, c.tracking_id, c.user_id, c.jurisdiction_id, c.is_anonymous,
+	       c.complainant_name, c.complainant_contact, c.subject, c.description,
+	       c.status, c.assigned_to_id, c.assigned_at, c.resolution_notes, c.closed_at, c.created_at, c.updated_at,
+	       c.status_changed_at, c.escalation_level, c.escalated_to_id, c.escalated_at, c.conflict_of_interest,
+	       c.moderation_status, c.moderation_reason,
+	       j.name as jurisdiction_name, COALESCE(u.full_name, '') as assigned_to_name,
+	       COALESCE(ej.name, '') as escalated_to_name
+	FROM complaints c
+	JOIN jurisdictions j ON c.jurisdiction_id = j.id
+	LEFT JOIN users u ON c.assigned_to_id = u.id
+	LEFT JOIN jurisdictions ej ON c.escalated_to_id = ej.id
+`
+
+func scanComplaint(row pgx.Row, c *models.Complaint) error {
+	return row.Scan(
 		&c.ID, &c.TrackingID, &c.UserID, &c.JurisdictionID, &c.IsAnonymous,
 		&c.ComplainantName, &c.ComplainantContact, &c.Subject, &c.Description,
-		&c.Status, &c.AssignedToID, &c.ResolutionNotes, &c.CreatedAt, &c.UpdatedAt,
-		&c.JurisdictionName,
+		&c.Status, &c.AssignedToID, &c.AssignedAt, &c.ResolutionNotes, &c.ClosedAt, &c.CreatedAt, &c.UpdatedAt,
+		&c.StatusChangedAt, &c.EscalationLevel, &c.EscalatedToID, &c.EscalatedAt, &c.ConflictOfInterest,
+		&c.ModerationStatus, &c.ModerationReason,
+		&c.JurisdictionName, &c.AssignedToName, &c.EscalatedToName,
 	)
+}
+
+// GetByTrackingID retrieves a complaint by its human-readable tracking ID
+func (r *Repository) GetByTrackingID(ctx context.Context, trackingID string) (*models.Complaint, error) {
+	return r.getComplaint(ctx, complaintSelect+" WHERE c.tracking_id = $1 AND c.deleted_at IS NULL", trackingID)
+}
+
+// GetByTrackingIDAndSecret retrieves a complaint by its tracking ID and the hash of its secret
+func (r *Repository) GetByTrackingIDAndSecret(ctx context.Context, trackingID, secretHash string) (*models.Complaint, error) {
+	return r.getComplaint(ctx, complaintSelect+" WHERE c.tracking_id = $1 AND c.access_secret_hash = $2 AND c.deleted_at IS NULL", trackingID, secretHash)
+}
+
+// GetByID retrieves a complaint by its ID
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*models.Complaint, error) {
+	return r.getComplaint(ctx, complaintSelect+" WHERE c.id = $1 AND c.deleted_at IS NULL", id)
+}
+
+func (r *Repository) getComplaint(ctx context.Context, query string, args ...interface{}) (*models.Complaint, error) {
+	var c models.Complaint
+	err := scanComplaint(r.db.QueryRow(ctx, query, args...), &c)
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("complaint not found")
+		return nil, ErrComplaintNotFound
 	}
 	return &c, err
 }
 
-// ListComplaints returns complaints filtered by jurisdiction and status
-func (r *Repository) ListComplaints(ctx context.Context, jurisdictionID uuid.UUID, status string, limit, offset int) ([]*models.Complaint, error) {
-	query := `
-		SELECT c.id, c.tracking_id, c.user_id, c.jurisdiction_id, c.is_anonymous, 
-		       c.complainant_name, c.complainant_contact, c.subject, c.description, 
-		       c.status, c.assigned_to_id, c.resolution_notes, c.created_at, c.updated_at,
-		       j.name as jurisdiction_name, u.full_name as assigned_to_name
-		FROM complaints c
-		JOIN jurisdictions j ON c.jurisdiction_id = j.id
-		LEFT JOIN users u ON c.assigned_to_id = u.id
-		WHERE c.jurisdiction_id = $1 AND c.deleted_at IS NULL
-	`
-	args := []interface{}{jurisdictionID}
-	
+// inQueue matches the complaints in the queue of jurisdiction $1: its own and those escalated to
+// it from below. A conflict-of-interest complaint routed to a parent leaves its own jurisdiction's
+// queue; only one with nobody above stays there.
+const inQueue = `((c.jurisdiction_id = $1 AND (NOT c.conflict_of_interest OR c.escalated_to_id IS NULL)) OR c.escalated_to_id = $1)`
+
+// ListComplaints returns complaints filtered by jurisdiction and status. A jurisdiction's queue
+// includes complaints escalated to it from below; complaints naming viewerID, and those held
+// for moderation or found to be spam, are left out.
+func (r *Repository) ListComplaints(ctx context.Context, jurisdictionID, viewerID uuid.UUID, status string, limit, offset int) ([]*models.Complaint, error) {
+	query := complaintSelect + ` WHERE ` + inQueue + ` AND c.deleted_at IS NULL
+		AND c.moderation_status = 'clear'
+		AND NOT EXISTS (SELECT 1 FROM complaint_accused a WHERE a.complaint_id = c.id AND a.user_id = $2)`
+	args := []interface{}{jurisdictionID, viewerID}
+
 	if status != "" {
 		args = append(args, status)
 		query += fmt.Sprintf(" AND c.status = $%d", len(args))
@@ -83,6 +157,10 @@ func (r *Repository) ListComplaints(ctx context.Context, jurisdictionID uuid.UUI
 	query += fmt.Sprintf(" ORDER BY c.created_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 	args = append(args, limit, offset)
 
+	return r.listComplaints(ctx, query, args...)
+}
+
+func (r *Repository) listComplaints(ctx context.Context, query string, args ...interface{}) ([]*models.Complaint, error) {
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -92,47 +170,404 @@ func (r *Repository) ListComplaints(ctx context.Context, jurisdictionID uuid.UUI
 	var list []*models.Complaint
 	for rows.Next() {
 		var c models.Complaint
+		if err := scanComplaint(rows, &c); err != nil {
+			return nil, err
+		}
+		list = append(list, &c)
+	}
+	return list, rows.Err()
+}
+
+// UpdateStatus moves a complaint from one status to another and logs it in a transaction.
+// Closing or rejecting stores the note as the resolution; reopening clears it.
+func (r *Repository) UpdateStatus(ctx context.Context, complaintID, userID uuid.UUID, from, to, action, note string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Update status, unless someone else changed it since it was read
+	tag, err := tx.Exec(ctx, `
+		UPDATE complaints
+		SET status = $1,
+		    resolution_notes = CASE
+		        WHEN $1 IN ('closed', 'rejected') THEN $2
+		        WHEN $4 IN ('closed', 'rejected') THEN NULL
+		        ELSE resolution_notes
+		    END,
+		    closed_at = CASE WHEN $1 = 'closed' THEN NOW() END,
+		    status_changed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $3 AND status = $4 AND deleted_at IS NULL
+	`, to, note, complaintID, from)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrStatusChanged
+	}
+
+	// 2. Log the change
+	logQuery := `
+		INSERT INTO complaint_logs (complaint_id, user_id, action, old_status, new_status, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = tx.Exec(ctx, logQuery, complaintID, userID, action, from, to, note)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateEvidence links a file to a complaint
+func (r *Repository) CreateEvidence(ctx context.Context, e *models.ComplaintEvidence) error {
+	query := `
+		INSERT INTO complaint_evidence (complaint_id, file_path, file_type, file_size, content_hash, original_name, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, processing_status
+	`
+	return r.db.QueryRow(ctx, query,
+		e.ComplaintID, e.FilePath, e.FileType, e.FileSize, e.ContentHash, e.OriginalName, e.UploadedBy,
+	).Scan(&e.ID, &e.CreatedAt, &e.ProcessingStatus)
+}
+
+// ListEvidence returns the files attached to a complaint
+func (r *Repository) ListEvidence(ctx context.Context, complaintID uuid.UUID) ([]*models.ComplaintEvidence, error) {
+	query := `
+		SELECT id, complaint_id, file_path, COALESCE(file_type, ''), COALESCE(file_size, 0),
+		       COALESCE(content_hash, ''), COALESCE(original_name, ''), uploaded_by, created_at,
+		       processing_status, processing_error, processed_at, original_path, thumbnail_path
+		FROM complaint_evidence
+		WHERE complaint_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, complaintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ComplaintEvidence
+	for rows.Next() {
+		var e models.ComplaintEvidence
 		err := rows.Scan(
-			&c.ID, &c.TrackingID, &c.UserID, &c.JurisdictionID, &c.IsAnonymous,
-			&c.ComplainantName, &c.ComplainantContact, &c.Subject, &c.Description,
-			&c.Status, &c.AssignedToID, &c.ResolutionNotes, &c.CreatedAt, &c.UpdatedAt,
-			&c.JurisdictionName, &c.AssignedToName,
+			&e.ID, &e.ComplaintID, &e.FilePath, &e.FileType, &e.FileSize,
+			&e.ContentHash, &e.OriginalName, &e.UploadedBy, &e.CreatedAt,
+			&e.ProcessingStatus, &e.ProcessingError, &e.ProcessedAt, &e.OriginalPath, &e.ThumbnailPath,
 		)
 		if err != nil {
 			return nil, err
 		}
-		list = append(list, &c)
+		list = append(list, &e)
 	}
 	return list, nil
 }
 
-// UpdateStatus changes the status and logs the action in a transaction
-func (r *Repository) UpdateStatus(ctx context.Context, complaintID uuid.UUID, userID uuid.UUID, newStatus, note string) error {
+// CountEvidence returns how many files are attached to a complaint
+func (r *Repository) CountEvidence(ctx context.Context, complaintID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM complaint_evidence WHERE complaint_id = $1", complaintID).Scan(&n)
+	return n, err
+}
+
+// ASSIGNMENT
+
+// Assign hands a complaint to an official and logs it. actorID is nil when the complaint was
+// routed automatically. Returns the previous assignee, if any.
+func (r *Repository) Assign(ctx context.Context, complaintID, assigneeID uuid.UUID, actorID *uuid.UUID, note string) (*uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the complaint
+	var previous *uuid.UUID
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT assigned_to_id, status FROM complaints WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, complaintID).Scan(&previous, &status)
+	if err == pgx.ErrNoRows {
+		return nil, ErrComplaintNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && *previous == assigneeID {
+		return nil, ErrAlreadyAssigned
+	}
+
+	// 2. Assign
+	_, err = tx.Exec(ctx, `
+		UPDATE complaints SET assigned_to_id = $1, assigned_at = NOW(), updated_at = NOW() WHERE id = $2
+	`, assigneeID, complaintID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Log the change
+	_, err = tx.Exec(ctx, `
+		INSERT INTO complaint_logs (complaint_id, user_id, action, old_status, new_status, note)
+		VALUES ($1, $2, $3, $4, $4, $5)
+	`, complaintID, actorID, models.ComplaintActionAssigned, status, note)
+	if err != nil {
+		return nil, err
+	}
+
+	return previous, tx.Commit(ctx)
+}
+
+// GetRouting returns a jurisdiction's routing rule, or nil when it has none
+func (r *Repository) GetRouting(ctx context.Context, jurisdictionID uuid.UUID) (*models.ComplaintRouting, error) {
+	var rt models.ComplaintRouting
+	err := r.db.QueryRow(ctx, `
+		SELECT rr.jurisdiction_id, rr.mode, rr.officer_id, rr.position_ids, rr.updated_by, rr.updated_at,
+		       COALESCE(u.full_name, '')
+		FROM complaint_routing_rules rr
+		LEFT JOIN users u ON rr.officer_id = u.id
+		WHERE rr.jurisdiction_id = $1
+	`, jurisdictionID).Scan(
+		&rt.JurisdictionID, &rt.Mode, &rt.OfficerID, &rt.PositionIDs, &rt.UpdatedBy, &rt.UpdatedAt,
+		&rt.OfficerName,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rt, nil
+}
+
+// UpsertRouting creates or replaces a jurisdiction's routing rule. The round-robin cursor is
+// kept so changing the pool does not restart the rotation.
+func (r *Repository) UpsertRouting(ctx context.Context, rt *models.ComplaintRouting) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO complaint_routing_rules (jurisdiction_id, mode, officer_id, position_ids, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (jurisdiction_id) DO UPDATE
+		SET mode = EXCLUDED.mode, officer_id = EXCLUDED.officer_id, position_ids = EXCLUDED.position_ids,
+		    updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at
+	`, rt.JurisdictionID, rt.Mode, rt.OfficerID, rt.PositionIDs, rt.UpdatedBy).Scan(&rt.UpdatedAt)
+}
+
+// NextRoundRobin picks the officer after the rule's cursor among active members of the
+// jurisdiction's committee holding one of positionIDs, and moves the cursor to them.
+// Returns nil when nobody holds those positions.
+func (r *Repository) NextRoundRobin(ctx context.Context, jurisdictionID uuid.UUID, positionIDs []int) (*uuid.UUID, error) {
+	// 1. Candidates in a stable order
+	candidates, err := r.queryUserIDs(ctx, `
+		SELECT DISTINCT cm.user_id
+		FROM committees c
+		JOIN committee_members cm ON cm.committee_id = c.id AND cm.ended_at IS NULL AND cm.is_active = TRUE
+		JOIN users u ON cm.user_id = u.id AND u.is_active = TRUE AND u.deleted_at IS NULL
+		WHERE c.jurisdiction_id = $1 AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+		  AND cm.position_id = ANY($2)
+		ORDER BY cm.user_id
+	`, jurisdictionID, positionIDs)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 2. Lock the rule so concurrent submissions take turns
+	var last *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT last_assigned_id FROM complaint_routing_rules WHERE jurisdiction_id = $1 FOR UPDATE
+	`, jurisdictionID).Scan(&last)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Take the one after the cursor
+	next := candidates[0]
+	if last != nil {
+		for i, id := range candidates {
+			if id == *last {
+				next = candidates[(i+1)%len(candidates)]
+				break
+			}
+			if id.String() > last.String() {
+				next = id // The previous officer left the pool; continue from where they were
+				break
+			}
+		}
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE complaint_routing_rules SET last_assigned_id = $1 WHERE jurisdiction_id = $2", next, jurisdictionID)
+	if err != nil {
+		return nil, err
+	}
+	return &next, tx.Commit(ctx)
+}
+
+// IsActiveOfficer reports whether a user is an active member of an active committee
+func (r *Repository) IsActiveOfficer(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM committee_members cm
+			JOIN committees c ON cm.committee_id = c.id
+			JOIN users u ON cm.user_id = u.id
+			WHERE cm.user_id = $1 AND cm.ended_at IS NULL AND cm.is_active = TRUE
+			  AND c.status = 'active' AND c.deleted_at IS NULL
+			  AND u.is_active = TRUE AND u.deleted_at IS NULL
+		)
+	`, userID).Scan(&ok)
+	return ok, err
+}
+
+// ListLeaderIDs returns the committee leaders (positions up to auth.LeaderRank) of the nearest
+// jurisdiction, starting at jurisdictionID and walking up, that has any
+func (r *Repository) ListLeaderIDs(ctx context.Context, jurisdictionID uuid.UUID) ([]uuid.UUID, error) {
+	return r.queryUserIDs(ctx, `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_id, 0 AS depth FROM jurisdictions WHERE id = $1
+			UNION ALL
+			SELECT j.id, j.parent_id, ch.depth + 1 FROM jurisdictions j
+			INNER JOIN chain ch ON j.id = ch.parent_id
+		),
+		leaders AS (
+			SELECT ch.depth, cm.user_id
+			FROM chain ch
+			JOIN committees c ON c.jurisdiction_id = ch.id AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+			JOIN committee_members cm ON cm.committee_id = c.id AND cm.ended_at IS NULL AND cm.is_active = TRUE
+			JOIN positions p ON cm.position_id = p.id
+			WHERE p.rank <= $2
+		)
+		SELECT DISTINCT user_id FROM leaders WHERE depth = (SELECT MIN(depth) FROM leaders)
+	`, jurisdictionID, auth.LeaderRank)
+}
+
+func (r *Repository) queryUserIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CONVERSATION
+
+// ListPublicMessages returns a complaint's public comment thread oldest first, without deleted
+// comments or who wrote them
+func (r *Repository) ListPublicMessages(ctx context.Context, complaintID uuid.UUID) ([]*models.ComplaintMessage, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, body, author_id IS NULL, created_at
+		FROM comments
+		WHERE entity_type = 'complaint' AND entity_id = $1 AND visibility = 'public' AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`, complaintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ComplaintMessage
+	for rows.Next() {
+		var m models.ComplaintMessage
+		if err := rows.Scan(&m.ID, &m.Body, &m.FromComplainant, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, &m)
+	}
+	return list, rows.Err()
+}
+
+// CreateComplainantMessage adds a message from the complainant to the complaint's public
+// comment thread and logs it, in a transaction
+func (r *Repository) CreateComplainantMessage(ctx context.Context, complaintID uuid.UUID, m *models.ComplaintMessage) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	// 1. Get current status
-	var oldStatus string
-	err = tx.QueryRow(ctx, "SELECT status FROM complaints WHERE id = $1 FOR UPDATE", complaintID).Scan(&oldStatus)
+	err = tx.QueryRow(ctx, `
+		INSERT INTO comments (entity_type, entity_id, author_id, body, visibility)
+		VALUES ('complaint', $1, NULL, $2, 'public')
+		RETURNING id, created_at
+	`, complaintID, m.Body).Scan(&m.ID, &m.CreatedAt)
 	if err != nil {
 		return err
 	}
+	m.FromComplainant = true
 
-	// 2. Update status
-	_, err = tx.Exec(ctx, "UPDATE complaints SET status = $1, updated_at = NOW() WHERE id = $2", newStatus, complaintID)
+	_, err = tx.Exec(ctx, `
+		INSERT INTO complaint_logs (complaint_id, user_id, action, note)
+		VALUES ($1, NULL, 'comment', $2)
+	`, complaintID, m.Body)
 	if err != nil {
 		return err
 	}
 
-	// 3. Log the change
-	logQuery := `
+	return tx.Commit(ctx)
+}
+
+// MODERATION
+
+// ListFlagged returns the complaints in a jurisdiction's queue held for moderation, oldest first,
+// without those naming viewerID
+func (r *Repository) ListFlagged(ctx context.Context, jurisdictionID, viewerID uuid.UUID, limit, offset int) ([]*models.Complaint, error) {
+	query := complaintSelect + ` WHERE ` + inQueue + ` AND c.deleted_at IS NULL
+		AND c.moderation_status = 'flagged'
+		AND NOT EXISTS (SELECT 1 FROM complaint_accused a WHERE a.complaint_id = c.id AND a.user_id = $2)
+		ORDER BY c.created_at ASC LIMIT $3 OFFSET $4`
+	return r.listComplaints(ctx, query, jurisdictionID, viewerID, limit, offset)
+}
+
+// Moderate records a moderator's decision on a flagged complaint and logs it in a transaction.
+// Cleared complaints start their first status SLA now.
+func (r *Repository) Moderate(ctx context.Context, complaintID, userID uuid.UUID, moderation, note string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Decide, unless another moderator already has
+	var status string
+	err = tx.QueryRow(ctx, `
+		UPDATE complaints
+		SET moderation_status = $1, moderated_by_id = $2, moderated_at = NOW(), updated_at = NOW(),
+		    status_changed_at = CASE WHEN $1 = 'clear' THEN NOW() ELSE status_changed_at END
+		WHERE id = $3 AND moderation_status = 'flagged' AND deleted_at IS NULL
+		RETURNING status
+	`, moderation, userID, complaintID).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return ErrNotAwaitingModeration
+	}
+	if err != nil {
+		return err
+	}
+
+	// 2. Log the decision
+	_, err = tx.Exec(ctx, `
 		INSERT INTO complaint_logs (complaint_id, user_id, action, old_status, new_status, note)
-		VALUES ($1, $2, 'status_change', $3, $4, $5)
-	`
-	_, err = tx.Exec(ctx, logQuery, complaintID, userID, oldStatus, newStatus, note)
+		VALUES ($1, $2, $3, $4, $4, $5)
+	`, complaintID, userID, models.ComplaintActionModerated, status, note)
 	if err != nil {
 		return err
 	}
@@ -140,8 +575,266 @@ func (r *Repository) UpdateStatus(ctx context.Context, complaintID uuid.UUID, us
 	return tx.Commit(ctx)
 }
 
-// CreateEvidence links a file to a complaint
-func (r *Repository) CreateEvidence(ctx context.Context, e *models.ComplaintEvidence) error {
-	query := `INSERT INTO complaint_evidence (complaint_id, file_path, file_type) VALUES ($1, $2, $3) RETURNING id, created_at`
-	return r.db.QueryRow(ctx, query, e.ComplaintID, e.FilePath, e.FileType).Scan(&e.ID, &e.CreatedAt)
+// CONFLICT OF INTEREST
+
+// FindCommitteeMembers returns the active members of a jurisdiction's committee who hold
+// positionID or whose name is name (case-insensitive). Either may be left empty.
+func (r *Repository) FindCommitteeMembers(ctx context.Context, jurisdictionID uuid.UUID, positionID *int, name string) ([]*models.ComplaintAccused, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT ON (cm.user_id) cm.user_id, cm.position_id, u.full_name, COALESCE(p.name, '')
+		FROM committees c
+		JOIN committee_members cm ON cm.committee_id = c.id AND cm.ended_at IS NULL AND cm.is_active = TRUE
+		JOIN users u ON cm.user_id = u.id AND u.deleted_at IS NULL
+		LEFT JOIN positions p ON cm.position_id = p.id
+		WHERE c.jurisdiction_id = $1 AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+		  AND (cm.position_id = $2 OR ($3 <> '' AND LOWER(u.full_name) = LOWER($3)))
+		ORDER BY cm.user_id, p.rank
+	`, jurisdictionID, positionID, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ComplaintAccused
+	for rows.Next() {
+		a := &models.ComplaintAccused{InCommittee: true}
+		if err := rows.Scan(&a.UserID, &a.PositionID, &a.UserName, &a.PositionName); err != nil {
+			return nil, err
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// ListCommitteePositions returns the positions currently held in a jurisdiction's committee,
+// highest first
+func (r *Repository) ListCommitteePositions(ctx context.Context, jurisdictionID uuid.UUID) ([]*models.Position, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT p.id, p.name, COALESCE(p.name_bn, ''), p.rank
+		FROM committees c
+		JOIN committee_members cm ON cm.committee_id = c.id AND cm.ended_at IS NULL AND cm.is_active = TRUE
+		JOIN positions p ON cm.position_id = p.id
+		WHERE c.jurisdiction_id = $1 AND c.status = 'active' AND c.deleted_at IS NULL AND c.parent_committee_id IS NULL
+		ORDER BY p.rank, p.id
+	`, jurisdictionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Position
+	for rows.Next() {
+		var p models.Position
+		if err := rows.Scan(&p.ID, &p.Name, &p.NameBn, &p.Rank); err != nil {
+			return nil, err
+		}
+		list = append(list, &p)
+	}
+	return list, rows.Err()
+}
+
+// GetPositionName returns a position's name, or "" when it does not exist
+func (r *Repository) GetPositionName(ctx context.Context, id int) (string, error) {
+	var name string
+	err := r.db.QueryRow(ctx, "SELECT name FROM positions WHERE id = $1", id).Scan(&name)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	return name, err
+}
+
+// ListAccused returns the members and positions a complaint names
+func (r *Repository) ListAccused(ctx context.Context, complaintID uuid.UUID) ([]*models.ComplaintAccused, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.complaint_id, a.user_id, a.position_id, a.name, a.in_committee, a.created_at,
+		       COALESCE(u.full_name, ''), COALESCE(p.name, '')
+		FROM complaint_accused a
+		LEFT JOIN users u ON a.user_id = u.id
+		LEFT JOIN positions p ON a.position_id = p.id
+		WHERE a.complaint_id = $1
+		ORDER BY a.created_at
+	`, complaintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ComplaintAccused
+	for rows.Next() {
+		var a models.ComplaintAccused
+		if err := rows.Scan(
+			&a.ID, &a.ComplaintID, &a.UserID, &a.PositionID, &a.Name, &a.InCommittee, &a.CreatedAt,
+			&a.UserName, &a.PositionName,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, &a)
+	}
+	return list, rows.Err()
+}
+
+// ListAccusedUserIDs returns the members a complaint names
+func (r *Repository) ListAccusedUserIDs(ctx context.Context, complaintID uuid.UUID) ([]uuid.UUID, error) {
+	return r.queryUserIDs(ctx, "SELECT user_id FROM complaint_accused WHERE complaint_id = $1 AND user_id IS NOT NULL", complaintID)
+}
+
+// SLA
+
+// ListOpenComplaints returns complaints still being handled that were filed before cutoff
+func (r *Repository) ListOpenComplaints(ctx context.Context, cutoff time.Time) ([]*models.Complaint, error) {
+	query := complaintSelect + `
+		WHERE c.status IN ('received', 'under_review', 'action_taken') AND c.deleted_at IS NULL AND c.created_at < $1
+		  AND c.moderation_status = 'clear'
+		ORDER BY c.created_at ASC
+	`
+	return r.listComplaints(ctx, query, cutoff)
+}
+
+// GetParentJurisdiction returns a jurisdiction's parent, or nil at the top of the hierarchy
+func (r *Repository) GetParentJurisdiction(ctx context.Context, id uuid.UUID) (*uuid.UUID, string, error) {
+	var parentID uuid.UUID
+	var name string
+	err := r.db.QueryRow(ctx, `
+		SELECT p.id, p.name
+		FROM jurisdictions j
+		JOIN jurisdictions p ON j.parent_id = p.id
+		WHERE j.id = $1 AND p.deleted_at IS NULL
+	`, id).Scan(&parentID, &name)
+	if err == pgx.ErrNoRows {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return &parentID, name, nil
+}
+
+// slaBreach is one missed deadline, as recorded by RecordBreach
+type slaBreach struct {
+	ComplaintID    uuid.UUID
+	JurisdictionID uuid.UUID // Queue that missed it
+	SLA            string
+	Status         string
+	StartedAt      time.Time
+	DueAt          time.Time
+	EscalatedToID  *uuid.UUID
+	Note           string
+}
+
+// RecordBreach stores a missed deadline, moves the complaint to the escalation queue if there
+// is one, and logs it, all in one transaction. It returns false if the breach was already
+// recorded, e.g. by another API instance.
+func (r *Repository) RecordBreach(ctx context.Context, b *slaBreach) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Claim the breach
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO complaint_sla_breaches (complaint_id, jurisdiction_id, sla, started_at, due_at, escalated_to_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (complaint_id, sla, started_at) DO NOTHING
+	`, b.ComplaintID, b.JurisdictionID, b.SLA, b.StartedAt, b.DueAt, b.EscalatedToID)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	// 2. Move it up
+	action := models.ComplaintActionSLABreached
+	if b.EscalatedToID != nil {
+		action = models.ComplaintActionEscalated
+		_, err = tx.Exec(ctx, `
+			UPDATE complaints
+			SET escalation_level = escalation_level + 1, escalated_to_id = $1, escalated_at = NOW()
+			WHERE id = $2
+		`, b.EscalatedToID, b.ComplaintID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// 3. Log it as a system entry
+	_, err = tx.Exec(ctx, `
+		INSERT INTO complaint_logs (complaint_id, action, old_status, new_status, note)
+		VALUES ($1, $2, $3, $3, $4)
+	`, b.ComplaintID, action, b.Status, b.Note)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// GetSLAStats summarises SLA compliance for complaints filed in [from, to), per jurisdiction in
+// the subtree of rootID that had any. ackSLA and resolveSLA are in seconds; nil leaves the
+// in-time counts at zero.
+func (r *Repository) GetSLAStats(ctx context.Context, rootID uuid.UUID, from, to time.Time, ackSLA, resolveSLA *float64) ([]*models.ComplaintSLAStats, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM jurisdictions WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT j.id FROM jurisdictions j
+			INNER JOIN subtree s ON j.parent_id = s.id
+			WHERE j.deleted_at IS NULL
+		),
+		filed AS (
+			SELECT c.id, c.jurisdiction_id, c.created_at, c.status, c.escalated_to_id,
+			       (SELECT MIN(l.created_at) FROM complaint_logs l
+			        WHERE l.complaint_id = c.id AND l.old_status = 'received' AND l.new_status <> 'received') AS ack_at,
+			       (SELECT MIN(l.created_at) FROM complaint_logs l
+			        WHERE l.complaint_id = c.id AND l.new_status IN ('closed', 'rejected') AND l.old_status <> l.new_status) AS resolved_at
+			FROM complaints c
+			JOIN subtree s ON c.jurisdiction_id = s.id
+			WHERE c.deleted_at IS NULL AND c.moderation_status = 'clear' AND c.created_at >= $2 AND c.created_at < $3
+		),
+		breaches AS (
+			SELECT b.jurisdiction_id, COUNT(*) AS n
+			FROM complaint_sla_breaches b
+			JOIN subtree s ON b.jurisdiction_id = s.id
+			WHERE b.created_at >= $2 AND b.created_at < $3
+			GROUP BY b.jurisdiction_id
+		)
+		SELECT j.id, j.name,
+		       COUNT(f.id),
+		       COUNT(f.ack_at),
+		       COUNT(*) FILTER (WHERE f.ack_at <= f.created_at + make_interval(secs => $4)),
+		       COUNT(f.resolved_at),
+		       COUNT(*) FILTER (WHERE f.resolved_at <= f.created_at + make_interval(secs => $5)),
+		       COALESCE(MAX(b.n), 0),
+		       COUNT(*) FILTER (WHERE f.escalated_to_id IS NOT NULL AND f.status IN ('received', 'under_review', 'action_taken')),
+		       AVG(EXTRACT(EPOCH FROM f.ack_at - f.created_at) / 3600),
+		       AVG(EXTRACT(EPOCH FROM f.resolved_at - f.created_at) / 3600)
+		FROM subtree s
+		JOIN jurisdictions j ON j.id = s.id
+		LEFT JOIN filed f ON f.jurisdiction_id = j.id
+		LEFT JOIN breaches b ON b.jurisdiction_id = j.id
+		GROUP BY j.id, j.name
+		HAVING COUNT(f.id) > 0 OR MAX(b.n) > 0
+		ORDER BY j.name ASC
+	`
+	rows, err := r.db.Query(ctx, query, rootID, from, to, ackSLA, resolveSLA)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ComplaintSLAStats
+	for rows.Next() {
+		var st models.ComplaintSLAStats
+		err := rows.Scan(
+			&st.JurisdictionID, &st.JurisdictionName,
+			&st.Received, &st.Acknowledged, &st.AcknowledgedInSLA, &st.Resolved, &st.ResolvedInSLA,
+			&st.Breaches, &st.OpenEscalated, &st.AvgAckHours, &st.AvgResolveHours,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &st)
+	}
+	return list, rows.Err()
 }
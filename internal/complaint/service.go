@@ -2,37 +2,111 @@ package complaint
 
 import (
 	"context"
-	"crypto/sha256"
+	"errors"
 	"fmt"
 	"math/rand"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/bjdms/api/internal/abuse"
+	"github.com/bjdms/api/internal/auth"
 	"github.com/bjdms/api/internal/models"
 	"github.com/bjdms/api/internal/notification"
+	"github.com/bjdms/api/pkg/storage"
 	"github.com/google/uuid"
 )
 
+// Complaint limits
+const (
+	MaxEvidencePerComplaint = 10
+	MaxSubjectLength        = 200
+	MaxDescriptionLength    = 10000
+	MaxComplainantLength    = 200 // Names and contact details
+)
+
+// Lookup and access errors
+var (
+	ErrComplaintNotFound    = errors.New("complaint not found")
+	ErrJurisdictionNotFound = errors.New("jurisdiction not found")
+	ErrNotOfficial          = errors.New("only officials at or above the complaint's jurisdiction can handle it")
+)
+
+// Submission errors
+var (
+	ErrSubjectRequired      = errors.New("subject and description are required")
+	ErrSubjectTooLong       = fmt.Errorf("subject must be at most %d characters", MaxSubjectLength)
+	ErrDescriptionTooLong   = fmt.Errorf("description must be at most %d characters", MaxDescriptionLength)
+	ErrComplainantTooLong   = fmt.Errorf("name and contact must be at most %d characters", MaxComplainantLength)
+	ErrJurisdictionRequired = errors.New("jurisdiction_id is required")
+	ErrEvidenceClosed       = errors.New("evidence cannot be added to a closed or rejected complaint")
+	ErrTooMuchEvidence      = fmt.Errorf("a complaint can have at most %d evidence files", MaxEvidencePerComplaint)
+)
+
+// AnonymousEvidenceTypes are the only files accepted from anonymous uploaders: the media pipeline
+// can strip their metadata. PDFs and videos would be served with author and location data intact.
+var AnonymousEvidenceTypes = []string{"image/jpeg", "image/png", "image/webp", "image/gif"}
+
+// submissionQuota limits public complaints per sender fingerprint and rotation period
+var submissionQuota = abuse.Quota{Flag: 5, Limit: 10}
+
+// trackingIDPattern matches IDs issued by generateTrackingID
+var trackingIDPattern = regexp.MustCompile(`^C-\d{4}-[A-HJ-NP-Z2-9]{6}$`)
+
+// JurisdictionChecker resolves jurisdiction hierarchy (implemented by committee.Service)
+type JurisdictionChecker interface {
+	IsChildJurisdiction(ctx context.Context, parentID, targetID uuid.UUID) (bool, error)
+}
+
+// AuthorityStore loads what a user's account and position entitle them to and records audit
+// entries (implemented by auth.Repository)
+type AuthorityStore interface {
+	GetUserAuthority(ctx context.Context, userID uuid.UUID) (*auth.Authority, error)
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+}
+
 // Service handles business logic for complaints
 type Service struct {
 	repo         *Repository
 	notification *notification.Service
+	files        *storage.Uploader
+	authRepo     AuthorityStore
+	org          JurisdictionChecker
+	sla          SLAPolicy
+	guard        *abuse.Guard
 }
 
 // NewService creates a new complaint service
-func NewService(repo *Repository, ns *notification.Service) *Service {
-	return &Service{repo: repo, notification: ns}
+func NewService(repo *Repository, ns *notification.Service, files *storage.Uploader, authRepo AuthorityStore, org JurisdictionChecker, sla SLAPolicy, guard *abuse.Guard) *Service {
+	return &Service{repo: repo, notification: ns, files: files, authRepo: authRepo, org: org, sla: sla, guard: guard}
 }
 
-// SubmitComplaint handles raw submission including tracking ID and IP hashing
-func (s *Service) SubmitComplaint(ctx context.Context, c *models.Complaint, ipAddress string) error {
-	// 1. Generate human-readable Tracking ID (C-YYYY-[RANDOM])
+// SubmitComplaint handles raw submission including tracking ID and spam screening. Suspected
+// spam is accepted but held for moderation instead of being routed.
+func (s *Service) SubmitComplaint(ctx context.Context, c *models.Complaint, ipAddress string, form abuse.Form) error {
+	if err := s.validateSubmission(ctx, c); err != nil {
+		return err
+	}
+	if err := s.resolveAccused(ctx, c); err != nil {
+		return err
+	}
+	verdict, err := s.guard.Screen(ctx, abuse.KindComplaint, submissionQuota, ipAddress, form)
+	if err != nil {
+		return err
+	}
+
+	// 1. Generate human-readable Tracking ID (C-YYYY-[RANDOM]), and the secret that together
+	// with it opens the conversation with the officials
 	c.TrackingID = generateTrackingID()
+	secret, secretHash, err := newAccessSecret()
+	if err != nil {
+		return err
+	}
+	c.AccessSecret, c.AccessSecretHash = secret, &secretHash
 
-	// 2. Hash IP for anonymous tracking/spam control
+	// 2. Keep only the sender's rotating fingerprint for anonymous complaints
 	if c.IsAnonymous {
-		hash := sha256.Sum256([]byte(ipAddress))
-		hashStr := fmt.Sprintf("%x", hash)
-		c.AnonymousIPHash = &hashStr
+		c.AnonymousIPHash = &verdict.Fingerprint
 		c.ComplainantName = nil
 		c.ComplainantContact = nil
 	}
@@ -41,49 +115,109 @@ func (s *Service) SubmitComplaint(ctx context.Context, c *models.Complaint, ipAd
 	if c.Status == "" {
 		c.Status = models.ComplaintStatusReceived
 	}
+	c.ModerationStatus = models.ModerationClear
+	if verdict.Flagged {
+		c.ModerationStatus, c.ModerationReason = models.ModerationFlagged, &verdict.Reason
+	}
 
-	err := s.repo.CreateComplaint(ctx, c)
-	if err != nil {
+	if err := s.repo.CreateComplaint(ctx, c); err != nil {
 		return err
 	}
 
-	// 4. Notify Jurisdiction Leaders
-	// Realistically, we'd fetch leader IDs, but for this demo, we'll notify the 'Jurisdiction Feed'
-	// System assumes an automated listener for these alerts.
-	s.notification.Create(ctx, &notification.Notification{
-		Type:           notification.TypeComplaintAlert,
-		Title:          "New Complaint Filed",
-		Message:        fmt.Sprintf("Tracking ID: %s. A new complaint has been submitted to your jurisdiction.", c.TrackingID),
-		JurisdictionID: c.JurisdictionID,
-	})
+	// 4. Assign it, or alert the leaders who will; flagged complaints wait for a moderator
+	if !verdict.Flagged {
+		s.routeComplaint(ctx, c)
+	}
 
 	return nil
 }
 
-// GetComplaintStatus allows public/anonymous lookup by tracking ID
+// validateSubmission checks the fields a complainant controls
+func (s *Service) validateSubmission(ctx context.Context, c *models.Complaint) error {
+	c.Subject = strings.TrimSpace(c.Subject)
+	c.Description = strings.TrimSpace(c.Description)
+	if c.Subject == "" || c.Description == "" {
+		return ErrSubjectRequired
+	}
+	if len(c.Subject) > MaxSubjectLength {
+		return ErrSubjectTooLong
+	}
+	if len(c.Description) > MaxDescriptionLength {
+		return ErrDescriptionTooLong
+	}
+	if (c.ComplainantName != nil && len(*c.ComplainantName) > MaxComplainantLength) ||
+		(c.ComplainantContact != nil && len(*c.ComplainantContact) > MaxComplainantLength) {
+		return ErrComplainantTooLong
+	}
+	if c.JurisdictionID == uuid.Nil {
+		return ErrJurisdictionRequired
+	}
+	ok, err := s.repo.JurisdictionExists(ctx, c.JurisdictionID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrJurisdictionNotFound
+	}
+	return nil
+}
+
+// GetComplaintStatus allows public/anonymous lookup by tracking ID. Callers must only pass on
+// the fields meant for the complainant.
 func (s *Service) GetComplaintStatus(ctx context.Context, trackingID string) (*models.Complaint, error) {
-	// We mask sensitive data for public lookup
+	// Malformed IDs never reach the database
+	if !ValidTrackingID(trackingID) {
+		return nil, ErrComplaintNotFound
+	}
+	return s.repo.GetByTrackingID(ctx, trackingID)
+}
+
+// GetComplaint returns the full record, including the complainant's details and whom it
+// accuses, to officials responsible for the complaint's jurisdiction
+func (s *Service) GetComplaint(ctx context.Context, trackingID string, userID uuid.UUID) (*models.Complaint, error) {
 	c, err := s.repo.GetByTrackingID(ctx, trackingID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkViewer(ctx, c, userID); err != nil {
+		return nil, err
+	}
+	if c.Accused, err = s.repo.ListAccused(ctx, c.ID); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
 
-	if c.IsAnonymous {
-		c.ComplainantName = nil
-		c.ComplainantContact = nil
+// GetComplaintByID returns a complaint by ID to officials responsible for it
+func (s *Service) GetComplaintByID(ctx context.Context, id, userID uuid.UUID) (*models.Complaint, error) {
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkViewer(ctx, c, userID); err != nil {
+		return nil, err
 	}
-	
 	return c, nil
 }
 
-// UpdateComplaintStatus handles status transitions with audit trail
-func (s *Service) UpdateComplaintStatus(ctx context.Context, id uuid.UUID, userID uuid.UUID, status, note string) error {
-	// Logic to validate status transition could be added here
-	return s.repo.UpdateStatus(ctx, id, userID, status, note)
+// checkViewer allows officials responsible for a complaint, other than those it accuses
+func (s *Service) checkViewer(ctx context.Context, c *models.Complaint, userID uuid.UUID) error {
+	if err := s.checkConflict(ctx, c, userID); err != nil {
+		return err
+	}
+	if err := s.checkAccess(ctx, userID, c.JurisdictionID); err != nil {
+		return err
+	}
+	s.auditOpened(c, userID)
+	return nil
 }
 
-// ListJurisdictionComplaints returns complaints for authorized leaders
-func (s *Service) ListJurisdictionComplaints(ctx context.Context, jurisdictionID uuid.UUID, status string, page, pageSize int) ([]*models.Complaint, error) {
+// ListJurisdictionComplaints returns a jurisdiction's complaints to officials at or above it,
+// without those naming the user
+func (s *Service) ListJurisdictionComplaints(ctx context.Context, jurisdictionID, userID uuid.UUID, status string, page, pageSize int) ([]*models.Complaint, error) {
+	if err := s.checkAccess(ctx, userID, jurisdictionID); err != nil {
+		return nil, err
+	}
 	if page < 1 {
 		page = 1
 	}
@@ -92,7 +226,113 @@ func (s *Service) ListJurisdictionComplaints(ctx context.Context, jurisdictionID
 	}
 	offset := (page - 1) * pageSize
 
-	return s.repo.ListComplaints(ctx, jurisdictionID, status, pageSize, offset)
+	return s.repo.ListComplaints(ctx, jurisdictionID, userID, status, pageSize, offset)
+}
+
+// EVIDENCE
+
+// CanAttachEvidence checks that a complaint accepts more evidence. Files can only be added
+// while the complaint is still open.
+func (s *Service) CanAttachEvidence(ctx context.Context, c *models.Complaint, adding int) error {
+	if c.Status == models.ComplaintStatusClosed || c.Status == models.ComplaintStatusRejected {
+		return ErrEvidenceClosed
+	}
+	count, err := s.repo.CountEvidence(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if count+adding > MaxEvidencePerComplaint {
+		return ErrTooMuchEvidence
+	}
+	return nil
+}
+
+// AttachEvidence records stored files against a complaint. uploadedBy is nil for anonymous uploads.
+func (s *Service) AttachEvidence(ctx context.Context, c *models.Complaint, uploadedBy *uuid.UUID, files []*storage.File) ([]*models.ComplaintEvidence, error) {
+	if err := s.CanAttachEvidence(ctx, c, len(files)); err != nil {
+		return nil, err
+	}
+
+	var list []*models.ComplaintEvidence
+	for _, f := range files {
+		e := &models.ComplaintEvidence{
+			ComplaintID:  c.ID,
+			FilePath:     f.Key,
+			FileType:     f.ContentType,
+			FileSize:     f.Size,
+			ContentHash:  f.SHA256,
+			OriginalName: f.OriginalName,
+			UploadedBy:   uploadedBy,
+		}
+		if err := s.repo.CreateEvidence(ctx, e); err != nil {
+			return list, err
+		}
+		list = append(list, e)
+	}
+	return list, nil
+}
+
+// ListEvidence returns a complaint's evidence with signed download links
+func (s *Service) ListEvidence(ctx context.Context, complaintID, userID uuid.UUID) ([]*models.ComplaintEvidence, error) {
+	if _, err := s.GetComplaintByID(ctx, complaintID, userID); err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListEvidence(ctx, complaintID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range list {
+		s.signEvidence(ctx, e)
+	}
+	return list, nil
+}
+
+// signEvidence attaches download links. Files are only linked once the media pipeline is done
+// with them, so metadata in an anonymous upload is never served.
+func (s *Service) signEvidence(ctx context.Context, e *models.ComplaintEvidence) {
+	if !models.MediaViewable(e.ProcessingStatus) {
+		return
+	}
+	e.URL, _ = s.files.SignedURL(ctx, e.FilePath)
+	if e.ThumbnailPath != nil {
+		e.ThumbnailURL, _ = s.files.SignedURL(ctx, *e.ThumbnailPath)
+	}
+	if e.OriginalPath != nil {
+		e.OriginalURL, _ = s.files.SignedURL(ctx, *e.OriginalPath)
+	}
+}
+
+// checkAccess allows the Super Admin and officials at or above the complaint's jurisdiction
+func (s *Service) checkAccess(ctx context.Context, userID, jurisdictionID uuid.UUID) error {
+	official, err := s.IsOfficial(ctx, userID, jurisdictionID)
+	if err != nil {
+		return err
+	}
+	if !official {
+		return ErrNotOfficial
+	}
+	return nil
+}
+
+// IsOfficial reports whether the user is a Super Admin or holds a committee position in the
+// jurisdiction or one above it. Members without a position are not officials.
+func (s *Service) IsOfficial(ctx context.Context, userID, jurisdictionID uuid.UUID) (bool, error) {
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if authority.SuperAdmin {
+		return true, nil
+	}
+	if authority.JurisdictionID == nil || authority.Rank == auth.NoPositionRank {
+		return false, nil
+	}
+	return s.org.IsChildJurisdiction(ctx, *authority.JurisdictionID, jurisdictionID)
+}
+
+// ValidTrackingID reports whether id has the shape of an issued tracking ID
+func ValidTrackingID(id string) bool {
+	return trackingIDPattern.MatchString(id)
 }
 
 // Helper: Generate a unique tracking ID
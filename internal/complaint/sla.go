@@ -0,0 +1,272 @@
+package complaint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/internal/notification"
+	"github.com/google/uuid"
+)
+
+// Maximum period covered by one SLA report
+const maxSLAReportSpan = 366 * 24 * time.Hour
+
+// SLA report errors
+var (
+	ErrInvalidRange = errors.New("to must be after from")
+	ErrRangeTooLong = fmt.Errorf("an SLA report can cover at most %d days", int(maxSLAReportSpan.Hours()/24))
+)
+
+// SLAPolicy maps an open status, or SLAResolution, to the time allowed. Status SLAs run from
+// when the complaint entered the status; the resolution SLA from when it was filed.
+type SLAPolicy map[string]time.Duration
+
+// ParseSLAPolicy reads a policy of the form "received=72h;resolution=720h"
+func ParseSLAPolicy(s string) (SLAPolicy, error) {
+	policy := make(SLAPolicy)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid SLA rule %q", entry)
+		}
+		key = strings.TrimSpace(key)
+		switch key {
+		case models.ComplaintStatusReceived, models.ComplaintStatusUnderReview, models.ComplaintStatusActionTaken, models.SLAResolution:
+		default:
+			return nil, fmt.Errorf("SLA rule %q must name an open status or %q", entry, models.SLAResolution)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid duration in SLA rule %q", entry)
+		}
+		policy[key] = d
+	}
+	return policy, nil
+}
+
+// shortest is how old a complaint must be before any SLA can have run out
+func (p SLAPolicy) shortest() time.Duration {
+	var min time.Duration
+	for _, d := range p {
+		if min == 0 || d < min {
+			min = d
+		}
+	}
+	return min
+}
+
+// seconds returns an SLA in seconds, or nil when it is not configured
+func (p SLAPolicy) seconds(key string) *float64 {
+	d, ok := p[key]
+	if !ok {
+		return nil
+	}
+	secs := d.Seconds()
+	return &secs
+}
+
+// GetSLAStats reports SLA compliance per jurisdiction in a subtree, for complaints filed in [from, to)
+func (s *Service) GetSLAStats(ctx context.Context, rootID, userID uuid.UUID, from, to time.Time) ([]*models.ComplaintSLAStats, error) {
+	if err := s.checkAccess(ctx, userID, rootID); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, ErrInvalidRange
+	}
+	if to.Sub(from) > maxSLAReportSpan {
+		return nil, ErrRangeTooLong
+	}
+
+	ackSLA, resolveSLA := s.sla.seconds(models.ComplaintStatusReceived), s.sla.seconds(models.SLAResolution)
+	list, err := s.repo.GetSLAStats(ctx, rootID, from, to, ackSLA, resolveSLA)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range list {
+		if ackSLA != nil && st.Acknowledged > 0 {
+			v := float64(st.AcknowledgedInSLA) / float64(st.Acknowledged)
+			st.AckCompliance = &v
+		}
+		if resolveSLA != nil && st.Resolved > 0 {
+			v := float64(st.ResolvedInSLA) / float64(st.Resolved)
+			st.ResolveCompliance = &v
+		}
+	}
+	return list, nil
+}
+
+// SLAMonitor is the background job that escalates complaints whose SLAs have run out
+type SLAMonitor struct {
+	service  *Service
+	interval time.Duration
+}
+
+// NewSLAMonitor creates the complaint SLA job
+func NewSLAMonitor(service *Service, interval time.Duration) *SLAMonitor {
+	return &SLAMonitor{service: service, interval: interval}
+}
+
+// Run checks open complaints every interval until ctx is cancelled. Breaches are claimed in the
+// database first, so several API instances can run the job without escalating twice.
+func (m *SLAMonitor) Run(ctx context.Context) {
+	if len(m.service.sla) == 0 {
+		log.Printf("complaints: no SLA policy configured, escalation disabled")
+		return
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.scan(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("complaints: SLA run failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *SLAMonitor) scan(ctx context.Context, now time.Time) error {
+	list, err := m.service.repo.ListOpenComplaints(ctx, now.Add(-m.service.sla.shortest()))
+	if err != nil {
+		return err
+	}
+
+	for _, c := range list {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := m.check(ctx, c, now); err != nil {
+			log.Printf("complaints: SLA check of %s failed: %v", c.TrackingID, err)
+		}
+	}
+	return nil
+}
+
+// check escalates a complaint for its status SLA, then for the resolution SLA. A complaint that
+// misses both in one run moves up two levels.
+func (m *SLAMonitor) check(ctx context.Context, c *models.Complaint, now time.Time) error {
+	policy := m.service.sla
+
+	if limit, ok := policy[c.Status]; ok && now.Sub(c.StatusChangedAt) > limit {
+		reason := fmt.Sprintf("Still %s after %s", strings.ReplaceAll(c.Status, "_", " "), formatSpan(limit))
+		if c.Status == models.ComplaintStatusReceived {
+			reason = fmt.Sprintf("Not acknowledged within %s", formatSpan(limit))
+		}
+		if err := m.escalate(ctx, c, c.Status, c.StatusChangedAt, limit, reason); err != nil {
+			return err
+		}
+	}
+
+	if limit, ok := policy[models.SLAResolution]; ok && now.Sub(c.CreatedAt) > limit {
+		reason := fmt.Sprintf("Not resolved within %s", formatSpan(limit))
+		return m.escalate(ctx, c, models.SLAResolution, c.CreatedAt, limit, reason)
+	}
+	return nil
+}
+
+// escalate records a breach and moves the complaint to the parent of the queue holding it.
+// The original jurisdiction keeps seeing the complaint.
+func (m *SLAMonitor) escalate(ctx context.Context, c *models.Complaint, sla string, started time.Time, limit time.Duration, reason string) error {
+	queue := c.JurisdictionID
+	if c.EscalatedToID != nil {
+		queue = *c.EscalatedToID
+	}
+	parentID, parentName, err := m.service.repo.GetParentJurisdiction(ctx, queue)
+	if err != nil {
+		return err
+	}
+
+	b := &slaBreach{
+		ComplaintID:    c.ID,
+		JurisdictionID: queue,
+		SLA:            sla,
+		Status:         c.Status,
+		StartedAt:      started,
+		DueAt:          started.Add(limit),
+		EscalatedToID:  parentID,
+		Note:           reason + "; no parent jurisdiction to escalate to",
+	}
+	if parentID != nil {
+		b.Note = fmt.Sprintf("%s; escalated to %s", reason, parentName)
+	}
+	ok, err := m.service.repo.RecordBreach(ctx, b)
+	if err != nil || !ok {
+		return err
+	}
+
+	// Later checks in this run start from the new queue
+	if parentID != nil {
+		c.EscalatedToID = parentID
+		c.EscalationLevel++
+	}
+	m.notify(ctx, c, parentID, reason)
+	return nil
+}
+
+// notify alerts the leaders of the queue the complaint is now in, and its assignee
+func (m *SLAMonitor) notify(ctx context.Context, c *models.Complaint, parentID *uuid.UUID, reason string) {
+	queue := c.JurisdictionID
+	if parentID != nil {
+		queue = *parentID
+	} else if c.EscalatedToID != nil {
+		queue = *c.EscalatedToID
+	}
+	recipients, err := m.service.repo.ListLeaderIDs(ctx, queue)
+	if err != nil {
+		log.Printf("complaints: no leaders alerted for %s: %v", c.TrackingID, err)
+	}
+	if c.AssignedToID != nil {
+		recipients = append(recipients, *c.AssignedToID)
+	}
+	recipients = m.service.excludeAccused(ctx, c, recipients)
+
+	title, msg := "Complaint SLA Missed", fmt.Sprintf("Complaint %s: %s.", c.TrackingID, reason)
+	if parentID != nil {
+		title = "Complaint Escalated"
+		msg = fmt.Sprintf("Complaint %s from %s has been escalated to the parent jurisdiction: %s.", c.TrackingID, c.JurisdictionName, reason)
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range recipients {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		m.service.notification.Create(ctx, &notification.Notification{
+			UserID:         id,
+			Type:           notification.TypeComplaintSLA,
+			Title:          title,
+			Message:        msg,
+			JurisdictionID: queue,
+		})
+	}
+}
+
+// formatSpan renders a duration in whole days, or hours below a day
+func formatSpan(d time.Duration) string {
+	if d >= 24*time.Hour {
+		days := int(d / (24 * time.Hour))
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	hours := int(d / time.Hour)
+	if hours == 1 {
+		return "1 hour"
+	}
+	return fmt.Sprintf("%d hours", hours)
+}
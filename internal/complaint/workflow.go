@@ -0,0 +1,104 @@
+package complaint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Resolution settings
+const (
+	ReopenWindow  = 30 * 24 * time.Hour // How long after closing a complaint can be reopened
+	MaxNoteLength = 5000
+)
+
+// Status change errors
+var (
+	ErrUnknownStatus     = errors.New("unknown complaint status")
+	ErrInvalidTransition = errors.New("status change not allowed")
+	ErrNoteRequired      = errors.New("a resolution note is required to close or reject a complaint")
+	ErrNoteTooLong       = fmt.Errorf("note must be at most %d characters", MaxNoteLength)
+	ErrReopenExpired     = fmt.Errorf("complaint was closed more than %d days ago and can no longer be reopened", int(ReopenWindow.Hours()/24))
+	ErrStatusChanged     = errors.New("complaint status changed concurrently, reload and try again")
+)
+
+// transitions lists the statuses each status can move to. Rejected is final; closed can only
+// go back to review, within ReopenWindow.
+var transitions = map[string][]string{
+	models.ComplaintStatusReceived:    {models.ComplaintStatusUnderReview},
+	models.ComplaintStatusUnderReview: {models.ComplaintStatusActionTaken, models.ComplaintStatusRejected},
+	models.ComplaintStatusActionTaken: {models.ComplaintStatusClosed},
+	models.ComplaintStatusClosed:      {models.ComplaintStatusUnderReview},
+	models.ComplaintStatusRejected:    nil,
+}
+
+// UpdateComplaintStatus moves a complaint along its workflow. Closing and rejecting need a note,
+// which becomes the resolution shown to the complainant.
+func (s *Service) UpdateComplaintStatus(ctx context.Context, id uuid.UUID, userID uuid.UUID, status, note string) (*models.Complaint, error) {
+	// 1. Validate
+	note = strings.TrimSpace(note)
+	if _, ok := transitions[status]; !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownStatus, status)
+	}
+	if len(note) > MaxNoteLength {
+		return nil, ErrNoteTooLong
+	}
+
+	c, err := s.GetComplaintByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTransition(c, status, note, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// 2. Apply
+	action := models.ComplaintActionStatusChange
+	if c.Status == models.ComplaintStatusClosed {
+		action = models.ComplaintActionReopened
+	}
+	if err := s.repo.UpdateStatus(ctx, id, userID, c.Status, status, action, note); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// checkTransition enforces the transition graph, resolution notes and the reopen window
+func checkTransition(c *models.Complaint, to, note string, now time.Time) error {
+	allowed := false
+	for _, next := range transitions[c.Status] {
+		if next == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, c.Status, to)
+	}
+
+	if (to == models.ComplaintStatusClosed || to == models.ComplaintStatusRejected) && note == "" {
+		return ErrNoteRequired
+	}
+	if c.Status == models.ComplaintStatusClosed && c.ClosedAt != nil && now.Sub(*c.ClosedAt) > ReopenWindow {
+		return ErrReopenExpired
+	}
+	return nil
+}
+
+// StatusError maps status change errors to an HTTP status code, or 0 for other errors
+func StatusError(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrReopenExpired), errors.Is(err, ErrStatusChanged):
+		return http.StatusConflict
+	case errors.Is(err, ErrUnknownStatus), errors.Is(err, ErrNoteRequired), errors.Is(err, ErrNoteTooLong):
+		return http.StatusUnprocessableEntity
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,345 @@
+package election
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bjdms/api/internal/committee"
+	"github.com/bjdms/api/internal/middleware"
+	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Handler handles election HTTP endpoints
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new election handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Routes returns routes for elections
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/", h.Create)
+	r.Get("/", h.List)
+	r.Get("/{id}", h.Get)
+	r.Patch("/{id}/status", h.ChangeStatus)
+
+	r.Get("/{id}/candidates", h.ListCandidates)
+	r.Post("/{id}/candidates", h.RegisterCandidate)
+	r.Delete("/{id}/candidates/{candidateId}", h.WithdrawCandidate)
+
+	r.Post("/{id}/ballots", h.CastBallot)
+	r.Post("/{id}/ballots/in-person", h.RecordInPersonBallot)
+
+	r.Get("/{id}/results", h.Results)
+	r.Post("/{id}/certify", h.Certify)
+
+	return r
+}
+
+// Create handles POST /api/v1/elections
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var e models.Election
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	actorID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "Invalid user")
+		return
+	}
+	e.CreatedBy = actorID
+
+	if err := h.service.CreateElection(r.Context(), &e); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, e, "Election created successfully")
+}
+
+// List handles GET /api/v1/elections?committee_id=..
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	committeeID, err := uuid.Parse(r.URL.Query().Get("committee_id"))
+	if err != nil {
+		response.BadRequest(w, "committee_id is required")
+		return
+	}
+
+	list, err := h.service.ListElections(r.Context(), committeeID)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch elections", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// Get handles GET /api/v1/elections/{id}
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+
+	e, err := h.service.GetElection(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, e, "")
+}
+
+// ChangeStatus handles PATCH /api/v1/elections/{id}/status
+func (h *Handler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	actorID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	e, err := h.service.ChangeStatus(r.Context(), id, body.Status, actorID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, e, "Election status updated")
+}
+
+// ListCandidates handles GET /api/v1/elections/{id}/candidates
+func (h *Handler) ListCandidates(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+
+	list, err := h.service.ListCandidates(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "Failed to fetch candidates", "")
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
+// RegisterCandidate handles POST /api/v1/elections/{id}/candidates
+func (h *Handler) RegisterCandidate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+
+	var c models.ElectionCandidate
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	c.ElectionID = id
+
+	// Members nominate themselves unless a user_id is given
+	actorID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if c.UserID == uuid.Nil {
+		c.UserID = actorID
+	}
+	c.RegisteredBy = &actorID
+
+	if err := h.service.RegisterCandidate(r.Context(), &c); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, c, "Candidate registered successfully")
+}
+
+// WithdrawCandidate handles DELETE /api/v1/elections/{id}/candidates/{candidateId}
+func (h *Handler) WithdrawCandidate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+	candidateID, err := uuid.Parse(chi.URLParam(r, "candidateId"))
+	if err != nil {
+		response.BadRequest(w, "Invalid candidate ID")
+		return
+	}
+
+	actorID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.WithdrawCandidate(r.Context(), id, candidateID, actorID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Candidacy withdrawn")
+}
+
+// CastBallot handles POST /api/v1/elections/{id}/ballots (secret online ballot)
+func (h *Handler) CastBallot(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+
+	var b models.Ballot
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	voterID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.CastOnlineBallot(r.Context(), id, voterID, &b); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, nil, "Your vote has been recorded")
+}
+
+// RecordInPersonBallot handles POST /api/v1/elections/{id}/ballots/in-person
+func (h *Handler) RecordInPersonBallot(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+
+	var b models.Ballot
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if b.VoterID == uuid.Nil {
+		response.BadRequest(w, "voter_id is required")
+		return
+	}
+
+	officerID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err := h.service.RecordInPersonBallot(r.Context(), id, &b, officerID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, nil, "Ballot recorded")
+}
+
+// Results handles GET /api/v1/elections/{id}/results
+func (h *Handler) Results(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+
+	result, err := h.service.GetResults(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, result, "")
+}
+
+// Certify handles POST /api/v1/elections/{id}/certify
+func (h *Handler) Certify(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid election ID")
+		return
+	}
+
+	// Optional body: {"tie_resolutions": {"<position_id>": ["<candidate_id>", ...]}}
+	var body struct {
+		TieResolutions map[string][]uuid.UUID `json:"tie_resolutions"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.BadRequest(w, "Invalid request body")
+			return
+		}
+	}
+	resolutions := make(map[int][]uuid.UUID, len(body.TieResolutions))
+	for key, ids := range body.TieResolutions {
+		positionID, err := strconv.Atoi(key)
+		if err != nil {
+			response.BadRequest(w, "tie_resolutions keys must be position IDs")
+			return
+		}
+		resolutions[positionID] = ids
+	}
+
+	actorID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	c, err := h.service.Certify(r.Context(), id, resolutions, actorID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Created(w, c, "Results certified; proposed committee created for approval")
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case matchesAny(err, notFoundErrors):
+		response.NotFound(w, err.Error())
+	case matchesAny(err, forbiddenErrors):
+		response.Forbidden(w, err.Error())
+	case matchesAny(err, conflictErrors):
+		response.Conflict(w, err.Error())
+	case matchesAny(err, invalidErrors):
+		response.BadRequest(w, err.Error())
+	default:
+		// Database and other internal failures are not shown to the client
+		response.InternalError(w, "Failed to process election request", "")
+	}
+}
+
+// Service errors by response status; anything else is an internal error
+var (
+	notFoundErrors  = []error{ErrElectionNotFound, ErrCommitteeNotFound, ErrCandidateNotFound}
+	forbiddenErrors = []error{ErrElectionAccess, ErrNotReturningOfficer, ErrNotElectorate, ErrWithdrawAccess}
+	conflictErrors  = []error{ErrAlreadyVoted, ErrAlreadyCandidate, ErrNotClosed}
+	invalidErrors   = []error{
+		ErrCommitteeInactive, ErrSubCommitteeElection, ErrTitleRequired, ErrInvalidCommitteeType,
+		ErrInvalidVotingMode, ErrVotingWindow, ErrNoPosts, ErrDuplicatePost, ErrInvalidSeats, ErrPostNotAllowed,
+		ErrTooManySeats, ErrInvalidTransition, ErrNominationsClosed, ErrPostNotContested, ErrWithdrawalClosed,
+		ErrVotingNotOpen, ErrVotingNotStarted, ErrVotingEnded, ErrChannelNotAccepted, ErrPostOnBallotTwice,
+		ErrTooManyChoices, ErrCandidateNotStanding, ErrCandidateChosenTwice, ErrResultsNotReady,
+		ErrTieUnresolved, ErrTieResolution, committee.ErrTermLimit, committee.ErrStructureMismatch,
+	}
+)
+
+func matchesAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package election
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bjdms/api/internal/committee"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"election not found", ErrElectionNotFound, http.StatusNotFound, ErrElectionNotFound.Error()},
+		{"no access", ErrElectionAccess, http.StatusForbidden, ErrElectionAccess.Error()},
+		{"returning officer", fmt.Errorf("cannot certify the results: %w", ErrNotReturningOfficer), http.StatusForbidden, "cannot certify the results: only the returning officer can do this"},
+		{"already voted", ErrAlreadyVoted, http.StatusConflict, ErrAlreadyVoted.Error()},
+		{"transition", fmt.Errorf("election cannot move from %s to %s: %w", "draft", "closed", ErrInvalidTransition), http.StatusBadRequest, "from draft to closed"},
+		{"term limit", fmt.Errorf("%w: user has already served 2 term(s)", committee.ErrTermLimit), http.StatusBadRequest, "served 2 term(s)"},
+		{"cannot seat", fmt.Errorf("cannot seat the elected members: %w", committee.ErrStructureMismatch), http.StatusBadRequest, "cannot seat"},
+		{"database error", errors.New(`ERROR: duplicate key value violates unique constraint "election_voters_pkey" (SQLSTATE 23505)`), http.StatusInternalServerError, ""},
+		{"wrapped database error", fmt.Errorf("failed to seat elected member: %w", errors.New("conn closed")), http.StatusInternalServerError, ""},
+		{"message ending in not found", errors.New("row not found"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := rec.Body.String()
+			if tt.wantBody != "" && !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body %s does not contain %q", body, tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(body, tt.err.Error()) {
+				t.Errorf("internal error leaked to the client: %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package election
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Lookup and ballot errors
+var (
+	ErrElectionNotFound  = errors.New("election not found")
+	ErrCommitteeNotFound = errors.New("committee not found")
+	ErrCandidateNotFound = errors.New("candidate not found")
+	ErrAlreadyVoted      = errors.New("member has already voted in this election")
+	ErrNotClosed         = errors.New("results can only be certified once voting has closed")
+)
+
+// Repository handles database operations for elections
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new election repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// ELECTIONS
+
+// Create inserts an election and its contested posts
+func (r *Repository) Create(ctx context.Context, e *models.Election) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO elections (
+			committee_id, jurisdiction_id, committee_type, title, voting_mode,
+			status, voting_starts_at, voting_ends_at, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query,
+		e.CommitteeID, e.JurisdictionID, e.CommitteeType, e.Title, e.VotingMode,
+		e.Status, e.VotingStartsAt, e.VotingEndsAt, e.CreatedBy,
+	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range e.Posts {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO election_posts (election_id, position_id, seats) VALUES ($1, $2, $3)`,
+			e.ID, p.PositionID, p.Seats,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByID retrieves an election with its posts
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*models.Election, error) {
+	query := `
+		SELECT id, committee_id, jurisdiction_id, committee_type, title, voting_mode, status,
+		       voting_starts_at, voting_ends_at, result_committee_id, created_by, created_at, updated_at
+		FROM elections
+		WHERE id = $1
+	`
+	var e models.Election
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&e.ID, &e.CommitteeID, &e.JurisdictionID, &e.CommitteeType, &e.Title, &e.VotingMode, &e.Status,
+		&e.VotingStartsAt, &e.VotingEndsAt, &e.ResultCommitteeID, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrElectionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.Posts, err = r.ListPosts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ListByCommittee returns elections held by a committee, newest first
+func (r *Repository) ListByCommittee(ctx context.Context, committeeID uuid.UUID) ([]*models.Election, error) {
+	query := `
+		SELECT id, committee_id, jurisdiction_id, committee_type, title, voting_mode, status,
+		       voting_starts_at, voting_ends_at, result_committee_id, created_by, created_at, updated_at
+		FROM elections
+		WHERE committee_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, committeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.Election
+	for rows.Next() {
+		var e models.Election
+		err := rows.Scan(
+			&e.ID, &e.CommitteeID, &e.JurisdictionID, &e.CommitteeType, &e.Title, &e.VotingMode, &e.Status,
+			&e.VotingStartsAt, &e.VotingEndsAt, &e.ResultCommitteeID, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &e)
+	}
+	return list, nil
+}
+
+// ListPosts returns the posts contested in an election, by rank
+func (r *Repository) ListPosts(ctx context.Context, electionID uuid.UUID) ([]*models.ElectionPost, error) {
+	query := `
+		SELECT ep.position_id, ep.seats, p.name, p.rank
+		FROM election_posts ep
+		JOIN positions p ON ep.position_id = p.id
+		WHERE ep.election_id = $1
+		ORDER BY p.rank ASC
+	`
+	rows, err := r.db.Query(ctx, query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ElectionPost
+	for rows.Next() {
+		var p models.ElectionPost
+		if err := rows.Scan(&p.PositionID, &p.Seats, &p.PositionName, &p.PositionRank); err != nil {
+			return nil, err
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+// UpdateStatus moves an election to a new status
+func (r *Repository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	query := `UPDATE elections SET status = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Exec(ctx, query, status, id)
+	return err
+}
+
+// Certify stores the proposed committee and its elected members and marks the election
+// certified, all in one transaction. It fails if the election is no longer closed.
+func (r *Repository) Certify(ctx context.Context, electionID uuid.UUID, c *models.Committee, members []*models.CommitteeMember) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Proposed committee
+	err = tx.QueryRow(ctx, `
+		INSERT INTO committees (jurisdiction_id, type, status, formed_at, expires_at, parent_committee_id, name, mandate, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`, c.JurisdictionID, c.Type, c.Status, c.FormedAt, c.ExpiresAt, c.ParentCommitteeID, c.Name, c.Mandate, c.CreatedBy,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// 2. Elected members
+	for _, m := range members {
+		m.CommitteeID = c.ID
+		m.JoinedAt = time.Now()
+		err := tx.QueryRow(ctx, `
+			INSERT INTO committee_members (committee_id, user_id, position_id, joined_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`, m.CommitteeID, m.UserID, m.PositionID, m.JoinedAt).Scan(&m.ID, &m.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to seat elected member: %w", err)
+		}
+	}
+
+	// 3. Certify, unless someone else already has
+	res, err := tx.Exec(ctx, `
+		UPDATE elections
+		SET status = 'certified', result_committee_id = $1, updated_at = NOW()
+		WHERE id = $2 AND status = 'closed'
+	`, c.ID, electionID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotClosed
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ELECTORATE
+
+// GetCommittee retrieves the status and jurisdiction of the electorate committee
+func (r *Repository) GetCommittee(ctx context.Context, id uuid.UUID) (*models.Committee, error) {
+	query := `SELECT id, jurisdiction_id, type, status FROM committees WHERE id = $1 AND deleted_at IS NULL`
+	var c models.Committee
+	err := r.db.QueryRow(ctx, query, id).Scan(&c.ID, &c.JurisdictionID, &c.Type, &c.Status)
+	if err == pgx.ErrNoRows {
+		return nil, ErrCommitteeNotFound
+	}
+	return &c, err
+}
+
+// IsActiveMember reports whether a user currently sits on the committee
+func (r *Repository) IsActiveMember(ctx context.Context, committeeID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM committee_members
+			WHERE committee_id = $1 AND user_id = $2 AND ended_at IS NULL AND is_active = TRUE
+		)
+	`
+	var ok bool
+	err := r.db.QueryRow(ctx, query, committeeID, userID).Scan(&ok)
+	return ok, err
+}
+
+// CountElectorate returns the number of members eligible to vote
+func (r *Repository) CountElectorate(ctx context.Context, committeeID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT user_id) FROM committee_members
+		WHERE committee_id = $1 AND ended_at IS NULL AND is_active = TRUE
+	`
+	var count int
+	err := r.db.QueryRow(ctx, query, committeeID).Scan(&count)
+	return count, err
+}
+
+// CANDIDATES
+
+// AddCandidate registers a candidate for a post
+func (r *Repository) AddCandidate(ctx context.Context, c *models.ElectionCandidate) error {
+	query := `
+		INSERT INTO election_candidates (election_id, position_id, user_id, registered_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (election_id, position_id, user_id) DO UPDATE SET withdrawn_at = NULL
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, query, c.ElectionID, c.PositionID, c.UserID, c.RegisteredBy).Scan(&c.ID, &c.CreatedAt)
+}
+
+// GetCandidate retrieves a candidate by ID
+func (r *Repository) GetCandidate(ctx context.Context, id uuid.UUID) (*models.ElectionCandidate, error) {
+	query := `
+		SELECT ec.id, ec.election_id, ec.position_id, ec.user_id, ec.withdrawn_at, ec.registered_by, ec.created_at,
+		       u.full_name, p.name
+		FROM election_candidates ec
+		JOIN users u ON ec.user_id = u.id
+		JOIN positions p ON ec.position_id = p.id
+		WHERE ec.id = $1
+	`
+	var c models.ElectionCandidate
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&c.ID, &c.ElectionID, &c.PositionID, &c.UserID, &c.WithdrawnAt, &c.RegisteredBy, &c.CreatedAt,
+		&c.UserName, &c.PositionName,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrCandidateNotFound
+	}
+	return &c, err
+}
+
+// ListCandidates returns an election's candidates that have not withdrawn
+func (r *Repository) ListCandidates(ctx context.Context, electionID uuid.UUID) ([]*models.ElectionCandidate, error) {
+	query := `
+		SELECT ec.id, ec.election_id, ec.position_id, ec.user_id, ec.withdrawn_at, ec.registered_by, ec.created_at,
+		       u.full_name, p.name
+		FROM election_candidates ec
+		JOIN users u ON ec.user_id = u.id
+		JOIN positions p ON ec.position_id = p.id
+		WHERE ec.election_id = $1 AND ec.withdrawn_at IS NULL
+		ORDER BY p.rank ASC, u.full_name ASC
+	`
+	rows, err := r.db.Query(ctx, query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.ElectionCandidate
+	for rows.Next() {
+		var c models.ElectionCandidate
+		err := rows.Scan(
+			&c.ID, &c.ElectionID, &c.PositionID, &c.UserID, &c.WithdrawnAt, &c.RegisteredBy, &c.CreatedAt,
+			&c.UserName, &c.PositionName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &c)
+	}
+	return list, nil
+}
+
+// WithdrawCandidate marks a candidacy as withdrawn
+func (r *Repository) WithdrawCandidate(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE election_candidates SET withdrawn_at = NOW() WHERE id = $1 AND withdrawn_at IS NULL`
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+// BALLOTS
+
+// CastBallot records turnout and the anonymous choices of a ballot in one transaction.
+// The turnout row's primary key guarantees one ballot per member across channels.
+func (r *Repository) CastBallot(ctx context.Context, electionID uuid.UUID, b *models.Ballot) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx, `
+		INSERT INTO election_voters (election_id, user_id, channel, recorded_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (election_id, user_id) DO NOTHING
+	`, electionID, b.VoterID, b.Channel, b.RecordedBy)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrAlreadyVoted
+	}
+
+	// Ballots recorded on a member's behalf are audited with the officer and the voter
+	if b.RecordedBy != nil {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO audit_logs (user_id, action, entity, entity_id, metadata, created_at)
+			VALUES ($1, $2, 'election', $3, jsonb_build_object('voter_id', $4::uuid, 'channel', $5::text), NOW())
+		`, *b.RecordedBy, AuditBallotRecorded, electionID, b.VoterID, b.Channel)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, choice := range b.Choices {
+		for _, candidateID := range choice.CandidateIDs {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO election_ballot_choices (election_id, position_id, candidate_id)
+				VALUES ($1, $2, $3)
+			`, electionID, choice.PositionID, candidateID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// HasVoted reports whether a member has cast a ballot
+func (r *Repository) HasVoted(ctx context.Context, electionID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM election_voters WHERE election_id = $1 AND user_id = $2)`
+	var ok bool
+	err := r.db.QueryRow(ctx, query, electionID, userID).Scan(&ok)
+	return ok, err
+}
+
+// CountBallots returns the number of ballots cast
+func (r *Repository) CountBallots(ctx context.Context, electionID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM election_voters WHERE election_id = $1`, electionID).Scan(&count)
+	return count, err
+}
+
+// TallyVotes returns vote counts for every standing candidate, highest first within each post
+func (r *Repository) TallyVotes(ctx context.Context, electionID uuid.UUID) (map[int][]*models.CandidateResult, error) {
+	query := `
+		SELECT ec.position_id, ec.id, ec.user_id, u.full_name, COUNT(bc.id) AS votes
+		FROM election_candidates ec
+		JOIN users u ON ec.user_id = u.id
+		LEFT JOIN election_ballot_choices bc ON bc.candidate_id = ec.id
+		WHERE ec.election_id = $1 AND ec.withdrawn_at IS NULL
+		GROUP BY ec.position_id, ec.id, ec.user_id, u.full_name
+		ORDER BY ec.position_id ASC, votes DESC, u.full_name ASC
+	`
+	rows, err := r.db.Query(ctx, query, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tally := make(map[int][]*models.CandidateResult)
+	for rows.Next() {
+		var positionID int
+		var c models.CandidateResult
+		if err := rows.Scan(&positionID, &c.CandidateID, &c.UserID, &c.UserName, &c.Votes); err != nil {
+			return nil, err
+		}
+		tally[positionID] = append(tally[positionID], &c)
+	}
+	return tally, nil
+}
+
+// GetPostQuota returns whether a position is allowed in committees of the given type at the
+// jurisdiction's level, and its seat limit (nil = unlimited)
+func (r *Repository) GetPostQuota(ctx context.Context, jurisdictionID uuid.UUID, committeeType string, positionID int) (bool, *int, error) {
+	query := `
+		SELECT q.max_seats
+		FROM committee_position_quotas q
+		JOIN jurisdictions j ON q.level_id = j.level_id
+		WHERE j.id = $1 AND q.committee_type = $2 AND q.position_id = $3
+	`
+	var maxSeats *int
+	err := r.db.QueryRow(ctx, query, jurisdictionID, committeeType, positionID).Scan(&maxSeats)
+	if err == pgx.ErrNoRows {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return true, maxSeats, nil
+}
@@ -0,0 +1,552 @@
+package election
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bjdms/api/internal/auth"
+	"github.com/bjdms/api/internal/committee"
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// AuditBallotRecorded is the audit action for a ballot recorded on a member's behalf
+const AuditBallotRecorded = "election_ballot_recorded"
+
+// ErrElectionAccess is returned when the caller may not hold a committee's election
+var ErrElectionAccess = errors.New("only leaders of the committee or of a jurisdiction above it can hold its election")
+
+// Election setup errors
+var (
+	ErrCommitteeInactive    = errors.New("elections can only be held by an active committee")
+	ErrSubCommitteeElection = errors.New("sub-committees do not hold elections")
+	ErrTitleRequired        = errors.New("title is required")
+	ErrInvalidCommitteeType = errors.New("committee_type must be full or convener")
+	ErrInvalidVotingMode    = errors.New("voting_mode must be in_person, online or hybrid")
+	ErrVotingWindow         = errors.New("voting_ends_at must be after voting_starts_at")
+	ErrNoPosts              = errors.New("at least one post must be contested")
+	ErrDuplicatePost        = errors.New("post is listed more than once")
+	ErrInvalidSeats         = errors.New("seats must be a positive number")
+	ErrPostNotAllowed       = errors.New("post is not allowed")
+	ErrTooManySeats         = errors.New("post has too many seats")
+	ErrNotReturningOfficer  = errors.New("only the returning officer can do this")
+	ErrInvalidTransition    = errors.New("election status change is not allowed")
+)
+
+// Candidacy and ballot errors
+var (
+	ErrNominationsClosed    = errors.New("nominations are not open for this election")
+	ErrPostNotContested     = errors.New("post is not contested in this election")
+	ErrNotElectorate        = errors.New("only active members of the committee can take part in its election")
+	ErrAlreadyCandidate     = errors.New("member is already a candidate")
+	ErrWithdrawalClosed     = errors.New("candidates can only withdraw during nominations")
+	ErrWithdrawAccess       = errors.New("only the candidate or the returning officer can withdraw a candidacy")
+	ErrVotingNotOpen        = errors.New("voting is not open for this election")
+	ErrVotingNotStarted     = errors.New("voting has not started yet")
+	ErrVotingEnded          = errors.New("voting has ended")
+	ErrChannelNotAccepted   = errors.New("this election does not accept ballots from this channel")
+	ErrPostOnBallotTwice    = errors.New("post appears more than once on the ballot")
+	ErrTooManyChoices       = errors.New("too many candidates chosen")
+	ErrCandidateNotStanding = errors.New("candidate is not standing")
+	ErrCandidateChosenTwice = errors.New("a candidate may only be chosen once per post")
+)
+
+// Result errors
+var (
+	ErrResultsNotReady = errors.New("results are available after voting closes")
+	ErrTieUnresolved   = errors.New("tied result must be resolved")
+	ErrTieResolution   = errors.New("invalid tie resolution")
+)
+
+// Service handles business logic for committee elections
+type Service struct {
+	repo      *Repository
+	committee *committee.Service
+	authRepo  *auth.Repository
+}
+
+// NewService creates a new election service
+func NewService(repo *Repository, cs *committee.Service, authRepo *auth.Repository) *Service {
+	return &Service{repo: repo, committee: cs, authRepo: authRepo}
+}
+
+// allowedTransitions lists the statuses an election may move to from each status
+var allowedTransitions = map[string][]string{
+	models.ElectionStatusDraft:       {models.ElectionStatusNominations, models.ElectionStatusCancelled},
+	models.ElectionStatusNominations: {models.ElectionStatusVoting, models.ElectionStatusCancelled},
+	models.ElectionStatusVoting:      {models.ElectionStatusClosed, models.ElectionStatusCancelled},
+	models.ElectionStatusClosed:      {models.ElectionStatusCancelled},
+}
+
+// ELECTIONS
+
+// CreateElection defines an election for a committee's posts. The committee's active members form the electorate.
+// The creator becomes the returning officer and must lead the committee or a jurisdiction above it.
+func (s *Service) CreateElection(ctx context.Context, e *models.Election) error {
+	// 1. Electorate committee must exist and be active
+	c, err := s.repo.GetCommittee(ctx, e.CommitteeID)
+	if err != nil {
+		return err
+	}
+	if c.Status != models.StatusActive {
+		return ErrCommitteeInactive
+	}
+	if c.Type == models.TypeSubCommittee {
+		return ErrSubCommitteeElection
+	}
+	e.JurisdictionID = c.JurisdictionID
+
+	if err := s.checkOfficerAccess(ctx, e.CreatedBy, c); err != nil {
+		return err
+	}
+
+	// 2. Defaults and basic validation
+	if strings.TrimSpace(e.Title) == "" {
+		return ErrTitleRequired
+	}
+	if e.CommitteeType == "" {
+		e.CommitteeType = models.TypeFull
+	}
+	if e.CommitteeType != models.TypeFull && e.CommitteeType != models.TypeConvener {
+		return ErrInvalidCommitteeType
+	}
+	switch e.VotingMode {
+	case "":
+		e.VotingMode = models.VotingModeHybrid
+	case models.VotingModeInPerson, models.VotingModeOnline, models.VotingModeHybrid:
+	default:
+		return ErrInvalidVotingMode
+	}
+	if e.VotingStartsAt != nil && e.VotingEndsAt != nil && !e.VotingEndsAt.After(*e.VotingStartsAt) {
+		return ErrVotingWindow
+	}
+
+	// 3. Posts must be allowed in the committee being elected
+	if len(e.Posts) == 0 {
+		return ErrNoPosts
+	}
+	seen := make(map[int]bool)
+	for _, p := range e.Posts {
+		if seen[p.PositionID] {
+			return fmt.Errorf("%w: position %d", ErrDuplicatePost, p.PositionID)
+		}
+		seen[p.PositionID] = true
+
+		if p.Seats == 0 {
+			p.Seats = 1
+		}
+		if p.Seats < 0 {
+			return ErrInvalidSeats
+		}
+		allowed, maxSeats, err := s.repo.GetPostQuota(ctx, e.JurisdictionID, e.CommitteeType, p.PositionID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return fmt.Errorf("%w: position %d in %s committees at this level", ErrPostNotAllowed, p.PositionID, e.CommitteeType)
+		}
+		if maxSeats != nil && p.Seats > *maxSeats {
+			return fmt.Errorf("%w: position %d has at most %d seat(s)", ErrTooManySeats, p.PositionID, *maxSeats)
+		}
+	}
+
+	e.Status = models.ElectionStatusDraft
+	return s.repo.Create(ctx, e)
+}
+
+// GetElection retrieves an election with its posts
+func (s *Service) GetElection(ctx context.Context, id uuid.UUID) (*models.Election, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListElections returns the elections held by a committee
+func (s *Service) ListElections(ctx context.Context, committeeID uuid.UUID) ([]*models.Election, error) {
+	return s.repo.ListByCommittee(ctx, committeeID)
+}
+
+// ChangeStatus advances an election through its lifecycle. Only the returning officer (creator) may do so.
+func (s *Service) ChangeStatus(ctx context.Context, id uuid.UUID, status string, actorID uuid.UUID) (*models.Election, error) {
+	e, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.CreatedBy != actorID {
+		return nil, fmt.Errorf("cannot change the election status: %w", ErrNotReturningOfficer)
+	}
+
+	allowed := false
+	for _, next := range allowedTransitions[e.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("election cannot move from %s to %s: %w", e.Status, status, ErrInvalidTransition)
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+	e.Status = status
+	return e, nil
+}
+
+// CANDIDATES
+
+// RegisterCandidate registers an eligible member for a post. Members may stand for one post per election.
+func (s *Service) RegisterCandidate(ctx context.Context, c *models.ElectionCandidate) error {
+	e, err := s.repo.GetByID(ctx, c.ElectionID)
+	if err != nil {
+		return err
+	}
+	if e.Status != models.ElectionStatusNominations {
+		return ErrNominationsClosed
+	}
+
+	// 1. Post must be contested
+	if findPost(e, c.PositionID) == nil {
+		return fmt.Errorf("%w: position %d", ErrPostNotContested, c.PositionID)
+	}
+
+	// 2. Candidate must belong to the electorate
+	member, err := s.repo.IsActiveMember(ctx, e.CommitteeID, c.UserID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return fmt.Errorf("cannot stand as a candidate: %w", ErrNotElectorate)
+	}
+
+	// 3. One post per candidate
+	candidates, err := s.repo.ListCandidates(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range candidates {
+		if existing.UserID == c.UserID {
+			return fmt.Errorf("standing for %s: %w", existing.PositionName, ErrAlreadyCandidate)
+		}
+	}
+
+	// 4. Term limits from tenure history
+	if err := s.committee.CheckTermLimit(ctx, c.UserID, c.PositionID, e.JurisdictionID, nil); err != nil {
+		return err
+	}
+
+	return s.repo.AddCandidate(ctx, c)
+}
+
+// ListCandidates returns the standing candidates of an election
+func (s *Service) ListCandidates(ctx context.Context, electionID uuid.UUID) ([]*models.ElectionCandidate, error) {
+	return s.repo.ListCandidates(ctx, electionID)
+}
+
+// WithdrawCandidate withdraws a candidacy before voting opens. Candidates may withdraw themselves;
+// the returning officer may withdraw anyone.
+func (s *Service) WithdrawCandidate(ctx context.Context, electionID, candidateID, actorID uuid.UUID) error {
+	e, err := s.repo.GetByID(ctx, electionID)
+	if err != nil {
+		return err
+	}
+	if e.Status != models.ElectionStatusNominations {
+		return ErrWithdrawalClosed
+	}
+
+	c, err := s.repo.GetCandidate(ctx, candidateID)
+	if err != nil {
+		return err
+	}
+	if c.ElectionID != electionID {
+		return ErrCandidateNotFound
+	}
+	if c.UserID != actorID && e.CreatedBy != actorID {
+		return ErrWithdrawAccess
+	}
+
+	return s.repo.WithdrawCandidate(ctx, candidateID)
+}
+
+// BALLOTS
+
+// CastOnlineBallot records a member's secret online ballot
+func (s *Service) CastOnlineBallot(ctx context.Context, electionID, voterID uuid.UUID, b *models.Ballot) error {
+	b.VoterID = voterID
+	b.Channel = models.BallotChannelOnline
+	b.RecordedBy = nil
+	return s.castBallot(ctx, electionID, b)
+}
+
+// RecordInPersonBallot records a paper ballot on behalf of a member. Only the returning officer may record ballots.
+func (s *Service) RecordInPersonBallot(ctx context.Context, electionID uuid.UUID, b *models.Ballot, officerID uuid.UUID) error {
+	e, err := s.repo.GetByID(ctx, electionID)
+	if err != nil {
+		return err
+	}
+	if e.CreatedBy != officerID {
+		return fmt.Errorf("cannot record in-person ballots: %w", ErrNotReturningOfficer)
+	}
+	b.Channel = models.BallotChannelInPerson
+	b.RecordedBy = &officerID
+	return s.castBallot(ctx, electionID, b)
+}
+
+func (s *Service) castBallot(ctx context.Context, electionID uuid.UUID, b *models.Ballot) error {
+	e, err := s.repo.GetByID(ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	// 1. Voting must be open, within the window and allowed on this channel
+	if e.Status != models.ElectionStatusVoting {
+		return ErrVotingNotOpen
+	}
+	now := time.Now()
+	if e.VotingStartsAt != nil && now.Before(*e.VotingStartsAt) {
+		return ErrVotingNotStarted
+	}
+	if e.VotingEndsAt != nil && now.After(*e.VotingEndsAt) {
+		return ErrVotingEnded
+	}
+	if e.VotingMode != models.VotingModeHybrid && e.VotingMode != b.Channel {
+		return fmt.Errorf("cannot cast %s ballots: %w", strings.ReplaceAll(b.Channel, "_", "-"), ErrChannelNotAccepted)
+	}
+
+	// 2. Voter must belong to the electorate
+	member, err := s.repo.IsActiveMember(ctx, e.CommitteeID, b.VoterID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return fmt.Errorf("cannot vote: %w", ErrNotElectorate)
+	}
+
+	// 3. Choices must name standing candidates for contested posts, within the seat count
+	candidates, err := s.repo.ListCandidates(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+	standing := make(map[uuid.UUID]int, len(candidates))
+	for _, c := range candidates {
+		standing[c.ID] = c.PositionID
+	}
+
+	seenPosts := make(map[int]bool)
+	for _, choice := range b.Choices {
+		post := findPost(e, choice.PositionID)
+		if post == nil {
+			return fmt.Errorf("%w: position %d", ErrPostNotContested, choice.PositionID)
+		}
+		if seenPosts[choice.PositionID] {
+			return fmt.Errorf("%w: position %d", ErrPostOnBallotTwice, choice.PositionID)
+		}
+		seenPosts[choice.PositionID] = true
+
+		if len(choice.CandidateIDs) > post.Seats {
+			return fmt.Errorf("%w: at most %d may be chosen for %s", ErrTooManyChoices, post.Seats, post.PositionName)
+		}
+		seenCandidates := make(map[uuid.UUID]bool)
+		for _, id := range choice.CandidateIDs {
+			if positionID, ok := standing[id]; !ok || positionID != choice.PositionID {
+				return fmt.Errorf("candidate %s for %s: %w", id, post.PositionName, ErrCandidateNotStanding)
+			}
+			if seenCandidates[id] {
+				return ErrCandidateChosenTwice
+			}
+			seenCandidates[id] = true
+		}
+	}
+
+	return s.repo.CastBallot(ctx, e.ID, b)
+}
+
+// RESULTS
+
+// GetResults tallies an election. Results are available once voting has closed.
+func (s *Service) GetResults(ctx context.Context, electionID uuid.UUID) (*models.ElectionResult, error) {
+	e, err := s.repo.GetByID(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != models.ElectionStatusClosed && e.Status != models.ElectionStatusCertified {
+		return nil, ErrResultsNotReady
+	}
+	return s.tally(ctx, e)
+}
+
+func (s *Service) tally(ctx context.Context, e *models.Election) (*models.ElectionResult, error) {
+	electorate, err := s.repo.CountElectorate(ctx, e.CommitteeID)
+	if err != nil {
+		return nil, err
+	}
+	ballots, err := s.repo.CountBallots(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := s.repo.TallyVotes(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ElectionResult{
+		ElectionID:  e.ID,
+		Status:      e.Status,
+		Electorate:  electorate,
+		BallotsCast: ballots,
+	}
+	for _, post := range e.Posts {
+		pr := &models.PostResult{
+			PositionID:   post.PositionID,
+			PositionName: post.PositionName,
+			Seats:        post.Seats,
+			Candidates:   votes[post.PositionID],
+		}
+		if pr.Candidates == nil {
+			pr.Candidates = []*models.CandidateResult{}
+		}
+
+		// Candidates arrive sorted by votes; the top N win unless the last seat is tied.
+		// Uncontested candidates are elected without needing a vote.
+		for i, c := range pr.Candidates {
+			if i >= post.Seats {
+				break
+			}
+			if c.Votes == 0 && len(pr.Candidates) > post.Seats {
+				continue
+			}
+			c.Elected = true
+		}
+		if len(pr.Candidates) > post.Seats {
+			last, next := pr.Candidates[post.Seats-1], pr.Candidates[post.Seats]
+			if last.Votes > 0 && last.Votes == next.Votes {
+				pr.Tie = true
+				for _, c := range pr.Candidates {
+					if c.Votes == last.Votes {
+						c.Elected = false
+					}
+				}
+			}
+		}
+		result.Posts = append(result.Posts, pr)
+	}
+	return result, nil
+}
+
+// Certify turns the results into a proposed committee, checked against the regular committee
+// structure, quotas and term limits and stored in one transaction. Tied seats must be resolved by
+// listing the winning candidate IDs per position.
+func (s *Service) Certify(ctx context.Context, electionID uuid.UUID, resolutions map[int][]uuid.UUID, actorID uuid.UUID) (*models.Committee, error) {
+	e, err := s.repo.GetByID(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	if e.CreatedBy != actorID {
+		return nil, fmt.Errorf("cannot certify the results: %w", ErrNotReturningOfficer)
+	}
+	if e.Status != models.ElectionStatusClosed {
+		return nil, ErrNotClosed
+	}
+
+	result, err := s.tally(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Determine winners, applying tie resolutions
+	var winners []*models.CommitteeMember
+	for _, pr := range result.Posts {
+		if pr.Tie {
+			chosen, ok := resolutions[pr.PositionID]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrTieUnresolved, pr.PositionName)
+			}
+
+			// Resolutions fill only the tied seats; clear winners keep theirs
+			tied := make(map[uuid.UUID]*models.CandidateResult)
+			open := pr.Seats
+			for _, c := range pr.Candidates {
+				if c.Elected {
+					open--
+				} else if c.Votes == pr.Candidates[pr.Seats-1].Votes {
+					tied[c.CandidateID] = c
+				}
+			}
+			if len(chosen) != open {
+				return nil, fmt.Errorf("%w: exactly %d tied candidate(s) must be declared for %s", ErrTieResolution, open, pr.PositionName)
+			}
+			for _, id := range chosen {
+				c, ok := tied[id]
+				if !ok {
+					return nil, fmt.Errorf("%w: candidate %s is not tied for %s", ErrTieResolution, id, pr.PositionName)
+				}
+				if c.Elected {
+					return nil, fmt.Errorf("%w: candidate %s is declared more than once for %s", ErrTieResolution, id, pr.PositionName)
+				}
+				c.Elected = true
+			}
+		}
+		for _, c := range pr.Candidates {
+			if c.Elected {
+				winners = append(winners, &models.CommitteeMember{UserID: c.UserID, PositionID: pr.PositionID})
+			}
+		}
+	}
+
+	// 2. Check every winner can be seated before storing anything
+	c := &models.Committee{
+		JurisdictionID: e.JurisdictionID,
+		Type:           e.CommitteeType,
+	}
+	if err := s.committee.ValidateSuccessorCommittee(ctx, c, winners); err != nil {
+		return nil, fmt.Errorf("cannot seat the elected members: %w", err)
+	}
+
+	// 3. Propose the successor committee with its members and certify, all or nothing
+	if err := s.repo.Certify(ctx, e.ID, c, winners); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// checkOfficerAccess allows the Super Admin, leaders (by position rank) of the electing
+// committee, and leaders of a jurisdiction above it
+func (s *Service) checkOfficerAccess(ctx context.Context, userID uuid.UUID, c *models.Committee) error {
+	authority, err := s.authRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if authority.SuperAdmin {
+		return nil
+	}
+
+	leader, err := s.committee.IsCommitteeLeader(ctx, c.ID, userID)
+	if err != nil {
+		return err
+	}
+	if leader {
+		return nil
+	}
+
+	if authority.IsLeader() && authority.JurisdictionID != nil && *authority.JurisdictionID != c.JurisdictionID {
+		above, err := s.committee.IsChildJurisdiction(ctx, *authority.JurisdictionID, c.JurisdictionID)
+		if err != nil {
+			return err
+		}
+		if above {
+			return nil
+		}
+	}
+	return ErrElectionAccess
+}
+
+func findPost(e *models.Election, positionID int) *models.ElectionPost {
+	for _, p := range e.Posts {
+		if p.PositionID == positionID {
+			return p
+		}
+	}
+	return nil
+}
@@ -8,18 +8,20 @@ import (
 	"github.com/bjdms/api/internal/middleware"
 	"github.com/bjdms/api/internal/models"
 	"github.com/bjdms/api/pkg/response"
+	"github.com/bjdms/api/pkg/storage"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 // Handler handles financial HTTP requests
 type Handler struct {
-	service *Service
+	service  *Service
+	receipts *storage.Uploader
 }
 
 // NewHandler creates a new finance handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, uploader *storage.Uploader) *Handler {
+	return &Handler{service: service, receipts: uploader.WithTypes(ReceiptTypes...)}
 }
 
 // Routes defines routes for financial management
@@ -27,6 +29,7 @@ func (h *Handler) Routes() chi.Router {
 	r := chi.NewRouter()
 
 	r.Get("/categories", h.ListCategories)
+	r.Post("/receipts", h.UploadReceipt)
 	r.Post("/transactions", h.RecordTransaction)
 	r.Get("/statement", h.GetStatement)
 
@@ -64,6 +67,26 @@ func (h *Handler) RecordTransaction(w http.ResponseWriter, r *http.Request) {
 	response.Created(w, t, "Transaction recorded successfully")
 }
 
+// UploadReceipt handles POST /api/v1/finance/receipts (multipart, field "file").
+// The returned key is then passed as evidence_path when recording the transaction.
+func (h *Handler) UploadReceipt(w http.ResponseWriter, r *http.Request) {
+	files, err := h.receipts.FromRequest(w, r, "file")
+	if err != nil {
+		if status := storage.UploadError(err); status != http.StatusInternalServerError {
+			response.Error(w, status, "upload_rejected", err.Error(), "")
+			return
+		}
+		response.InternalError(w, "Failed to store upload", "")
+		return
+	}
+	if len(files) != 1 {
+		response.BadRequest(w, "Upload exactly one receipt per request")
+		return
+	}
+
+	response.Created(w, files[0], "Receipt uploaded successfully")
+}
+
 // GetStatement handles GET /api/v1/finance/statement?jurisdiction_id=...
 func (h *Handler) GetStatement(w http.ResponseWriter, r *http.Request) {
 	jurisIDStr := r.URL.Query().Get("jurisdiction_id")
@@ -5,17 +5,22 @@ import (
 	"fmt"
 
 	"github.com/bjdms/api/internal/models"
+	"github.com/bjdms/api/pkg/storage"
 	"github.com/google/uuid"
 )
 
+// ReceiptTypes are the file types accepted as receipts
+var ReceiptTypes = []string{"image/jpeg", "image/png", "image/webp", "application/pdf"}
+
 // Service handles business logic for BJDMS finance
 type Service struct {
-	repo *Repository
+	repo  *Repository
+	files *storage.Uploader
 }
 
 // NewService creates a new finance service
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, files *storage.Uploader) *Service {
+	return &Service{repo: repo, files: files}
 }
 
 // RecordTransaction handles the creation of income/expense entries
@@ -36,7 +41,18 @@ func (s *Service) RecordTransaction(ctx context.Context, t *models.FinanceTransa
 		}
 	}
 
-	// 3. Persist (Immutability enforced by DB triggers)
+	// 3. Receipt must already be uploaded; the entry cannot be corrected afterwards
+	if t.EvidencePath != "" {
+		exists, err := s.files.Store().Exists(ctx, t.EvidencePath)
+		if err != nil {
+			return fmt.Errorf("invalid evidence_path: %v", err)
+		}
+		if !exists {
+			return fmt.Errorf("receipt %s has not been uploaded", t.EvidencePath)
+		}
+	}
+
+	// 4. Persist (Immutability enforced by DB triggers)
 	return s.repo.CreateTransaction(ctx, t)
 }
 
@@ -59,6 +75,11 @@ func (s *Service) GetJurisdictionStatement(ctx context.Context, jurisdictionID u
 	if err != nil {
 		return nil, nil, err
 	}
+	for _, t := range transactions {
+		if t.EvidencePath != "" {
+			t.EvidenceURL, _ = s.files.SignedURL(ctx, t.EvidencePath)
+		}
+	}
 
 	return balance, transactions, nil
 }
@@ -2,9 +2,11 @@ package join
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/bjdms/api/internal/abuse"
 	"github.com/bjdms/api/internal/middleware"
 	"github.com/bjdms/api/internal/models"
 	"github.com/bjdms/api/pkg/response"
@@ -27,9 +29,11 @@ func (h *Handler) Routes() chi.Router {
 	r := chi.NewRouter()
 
 	r.Get("/", h.List)
+	r.Get("/moderation", h.ListFlagged)
 	r.Get("/{id}", h.Get)
 	r.Patch("/{id}/approve", h.Approve)
 	r.Patch("/{id}/reject", h.Reject)
+	r.Patch("/{id}/moderation", h.Moderate)
 
 	return r
 }
@@ -43,14 +47,22 @@ func (h *Handler) PublicRoutes() chi.Router {
 
 // Submit handles POST /api/v1/public/join/apply
 func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
-	var jr models.JoinRequest
-	if err := json.NewDecoder(r.Body).Decode(&jr); err != nil {
+	var req struct {
+		models.JoinRequest
+		abuse.Form
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.BadRequest(w, "Invalid request body")
 		return
 	}
 
-	if err := h.service.SubmitApplication(r.Context(), &jr); err != nil {
-		response.BadRequest(w, err.Error())
+	jr := req.JoinRequest
+	if err := h.service.SubmitApplication(r.Context(), &jr, middleware.ClientIP(r), req.Form); err != nil {
+		if status := abuse.StatusError(err); status != 0 {
+			response.Error(w, status, "submission_refused", err.Error(), "")
+			return
+		}
+		writeError(w, err)
 		return
 	}
 
@@ -81,6 +93,30 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, list, "")
 }
 
+// ListFlagged handles GET /api/v1/join-requests/moderation
+func (h *Handler) ListFlagged(w http.ResponseWriter, r *http.Request) {
+	jurisID, err := uuid.Parse(r.URL.Query().Get("jurisdiction_id"))
+	if err != nil {
+		response.BadRequest(w, "jurisdiction_id is required")
+		return
+	}
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+
+	list, err := h.service.ListFlagged(r.Context(), jurisID, userID, page, pageSize)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, list, "")
+}
+
 // Get handles GET /api/v1/join-requests/{id}
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	id, _ := uuid.Parse(chi.URLParam(r, "id"))
@@ -124,3 +160,49 @@ func (h *Handler) Reject(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, nil, "Request rejected")
 }
+
+// Moderate handles PATCH /api/v1/join-requests/{id}/moderation
+func (h *Handler) Moderate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid join request ID")
+		return
+	}
+	actorID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	var body struct {
+		Decision string `json:"decision"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.service.ModerateRequest(r.Context(), id, body.Decision, actorID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response.Success(w, nil, "Moderation decision recorded")
+}
+
+// writeError maps service errors to responses; database and other internal failures are not
+// shown to the client
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrRequestNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrNotLeader):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrNotAwaitingModeration):
+		response.Conflict(w, err.Error())
+	case errors.Is(err, ErrUnknownDecision), errors.Is(err, ErrUnderage):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalError(w, "Failed to process join request", "")
+	}
+}
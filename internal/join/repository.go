@@ -2,13 +2,18 @@ package join
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/bjdms/api/internal/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrRequestNotFound is returned when no join request has the given ID
+var ErrRequestNotFound = errors.New("join request not found")
+
 // Repository handles database operations for join requests
 type Repository struct {
 	db *pgxpool.Pool
@@ -24,13 +29,15 @@ func (r *Repository) Create(ctx context.Context, jr *models.JoinRequest) error {
 	query := `
 		INSERT INTO join_requests (
 			full_name, full_name_bn, phone, nid, date_of_birth, gender, blood_group, 
-			occupation, address, jurisdiction_id, referred_by_id
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			occupation, address, jurisdiction_id, referred_by_id,
+			ip_fingerprint, moderation_status, moderation_reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, applied_at, status, created_at, updated_at
 	`
 	return r.db.QueryRow(ctx, query,
 		jr.FullName, jr.FullNameBn, jr.Phone, jr.NID, jr.DateOfBirth, jr.Gender, jr.BloodGroup,
 		jr.Occupation, jr.Address, jr.JurisdictionID, jr.ReferredByID,
+		jr.IPFingerprint, jr.ModerationStatus, jr.ModerationReason,
 	).Scan(&jr.ID, &jr.AppliedAt, &jr.Status, &jr.CreatedAt, &jr.UpdatedAt)
 }
 
@@ -40,7 +47,8 @@ func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*models.JoinReq
 		SELECT jr.id, jr.full_name, jr.full_name_bn, jr.phone, jr.nid, jr.date_of_birth, 
 		       jr.gender, jr.blood_group, jr.occupation, jr.address, jr.jurisdiction_id, 
 		       jr.applied_at, jr.status, jr.referred_by_id, jr.rejection_reason, 
-		       jr.processed_by_id, jr.created_at, jr.updated_at,
+		       jr.processed_by_id, jr.moderation_status, jr.moderation_reason,
+		       jr.created_at, jr.updated_at,
 		       j.name as jurisdiction_name, u.full_name as referrer_name
 		FROM join_requests jr
 		JOIN jurisdictions j ON jr.jurisdiction_id = j.id
@@ -52,9 +60,13 @@ func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*models.JoinReq
 		&jr.ID, &jr.FullName, &jr.FullNameBn, &jr.Phone, &jr.NID, &jr.DateOfBirth,
 		&jr.Gender, &jr.BloodGroup, &jr.Occupation, &jr.Address, &jr.JurisdictionID,
 		&jr.AppliedAt, &jr.Status, &jr.ReferredByID, &jr.RejectionReason,
-		&jr.ProcessedByID, &jr.CreatedAt, &jr.UpdatedAt,
+		&jr.ProcessedByID, &jr.ModerationStatus, &jr.ModerationReason,
+		&jr.CreatedAt, &jr.UpdatedAt,
 		&jr.JurisdictionName, &jr.ReferrerName,
 	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrRequestNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +81,7 @@ func (r *Repository) List(ctx context.Context, jurisdictionID uuid.UUID, status
 		FROM join_requests jr
 		JOIN jurisdictions j ON jr.jurisdiction_id = j.id
 		WHERE (jr.jurisdiction_id = $1 OR j.path <@ (SELECT path FROM jurisdictions WHERE id = $1))
+		  AND jr.moderation_status = 'clear'
 	`
 	args := []interface{}{jurisdictionID}
 	nextArg := 2
@@ -137,3 +150,69 @@ func (r *Repository) UpdateStatus(ctx context.Context, id uuid.UUID, newStatus,
 
 	return tx.Commit(ctx)
 }
+
+// ListFlagged returns the join requests in or under a jurisdiction held for moderation, oldest first
+func (r *Repository) ListFlagged(ctx context.Context, jurisdictionID uuid.UUID, limit, offset int) ([]*models.JoinRequest, error) {
+	query := `
+		SELECT jr.id, jr.full_name, jr.full_name_bn, jr.phone, jr.status, jr.applied_at,
+		       jr.moderation_status, jr.moderation_reason, j.name as jurisdiction_name
+		FROM join_requests jr
+		JOIN jurisdictions j ON jr.jurisdiction_id = j.id
+		WHERE (jr.jurisdiction_id = $1 OR j.path <@ (SELECT path FROM jurisdictions WHERE id = $1))
+		  AND jr.moderation_status = 'flagged'
+		ORDER BY jr.applied_at ASC LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(ctx, query, jurisdictionID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.JoinRequest
+	for rows.Next() {
+		var jr models.JoinRequest
+		if err := rows.Scan(
+			&jr.ID, &jr.FullName, &jr.FullNameBn, &jr.Phone, &jr.Status, &jr.AppliedAt,
+			&jr.ModerationStatus, &jr.ModerationReason, &jr.JurisdictionName,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, &jr)
+	}
+	return list, rows.Err()
+}
+
+// Moderate records a moderator's decision on a flagged request and logs it in a transaction
+func (r *Repository) Moderate(ctx context.Context, id uuid.UUID, moderation, note string, actorID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Decide, unless another moderator already has
+	var status string
+	err = tx.QueryRow(ctx, `
+		UPDATE join_requests
+		SET moderation_status = $1, moderated_by_id = $2, moderated_at = NOW(), updated_at = NOW()
+		WHERE id = $3 AND moderation_status = 'flagged'
+		RETURNING status
+	`, moderation, actorID, id).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return ErrNotAwaitingModeration
+	}
+	if err != nil {
+		return err
+	}
+
+	// 2. Log the decision
+	_, err = tx.Exec(ctx, `
+		INSERT INTO join_request_logs (request_id, actor_id, action, old_status, new_status, note)
+		VALUES ($1, $2, 'moderated', $3, $3, $4)
+	`, id, actorID, status, note)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -2,9 +2,11 @@ package join
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/bjdms/api/internal/abuse"
 	"github.com/bjdms/api/internal/auth"
 	"github.com/bjdms/api/internal/models"
 	"github.com/bjdms/api/internal/notification"
@@ -12,35 +14,66 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// submissionQuota limits applications from one network per fingerprint rotation period
+var submissionQuota = abuse.Quota{Flag: 2, Limit: 3}
+
+// ErrUnderage is returned for applicants younger than the minimum age
+var ErrUnderage = errors.New("applicant must be at least 18 years old")
+
+// Moderation errors
+var (
+	ErrNotLeader             = errors.New("only committee leaders at or above the request's jurisdiction can moderate join requests")
+	ErrUnknownDecision       = fmt.Errorf("decision must be %q or %q", models.ModerationApprove, models.ModerationReject)
+	ErrNotAwaitingModeration = errors.New("request is not awaiting moderation")
+)
+
+// JurisdictionChecker resolves jurisdiction hierarchy (implemented by committee.Service)
+type JurisdictionChecker interface {
+	IsChildJurisdiction(ctx context.Context, parentID, targetID uuid.UUID) (bool, error)
+}
+
 // Service handles business logic for joining Jubodal
 type Service struct {
 	repo         *Repository
 	userRepo     *auth.Repository
 	notification *notification.Service
+	guard        *abuse.Guard
+	org          JurisdictionChecker
 }
 
 // NewService creates a new join service
-func NewService(repo *Repository, userRepo *auth.Repository, ns *notification.Service) *Service {
-	return &Service{repo: repo, userRepo: userRepo, notification: ns}
+func NewService(repo *Repository, userRepo *auth.Repository, ns *notification.Service, guard *abuse.Guard, org JurisdictionChecker) *Service {
+	return &Service{repo: repo, userRepo: userRepo, notification: ns, guard: guard, org: org}
 }
 
-// SubmitApplication handles public join request submission
-func (s *Service) SubmitApplication(ctx context.Context, jr *models.JoinRequest) error {
+// SubmitApplication handles public join request submission. Applications that screening flags
+// are held for moderation and stay out of the committee's list until cleared.
+func (s *Service) SubmitApplication(ctx context.Context, jr *models.JoinRequest, ipAddress string, form abuse.Form) error {
 	// 1. Age Validation (18-40 is typically Jubo Dal bracket, allowing some flexibility)
 	age := time.Since(jr.DateOfBirth).Hours() / 24 / 365
 	if age < 18 {
-		return fmt.Errorf("applicant must be at least 18 years old")
+		return ErrUnderage
 	}
 
 	// 2. Duplicate check (Phone/NID)
 	// This would typically involve checking existing users and pending join requests
 	// Simplified for this implementation
 
-	jr.Status = models.JoinRequestStatusPending
-	err := s.repo.Create(ctx, jr)
+	// 3. Screening (proof of work, quota, honeypot)
+	verdict, err := s.guard.Screen(ctx, abuse.KindJoinRequest, submissionQuota, ipAddress, form)
 	if err != nil {
 		return err
 	}
+	jr.IPFingerprint = &verdict.Fingerprint
+	jr.ModerationStatus, jr.ModerationReason = models.ModerationClear, nil
+	if verdict.Flagged {
+		jr.ModerationStatus, jr.ModerationReason = models.ModerationFlagged, &verdict.Reason
+	}
+
+	jr.Status = models.JoinRequestStatusPending
+	if err := s.repo.Create(ctx, jr); err != nil {
+		return err
+	}
 
 	// Notify Jurisdiction Leaders of new application
 	// s.notification.Create(ctx, &notification.Notification{...})
@@ -55,6 +88,9 @@ func (s *Service) ApproveRequest(ctx context.Context, id uuid.UUID, actorID uuid
 		return err
 	}
 
+	if jr.ModerationStatus != models.ModerationClear {
+		return fmt.Errorf("request is held for moderation and cannot be approved")
+	}
 	if jr.Status != models.JoinRequestStatusPending && jr.Status != models.JoinRequestStatusUnderReview {
 		return fmt.Errorf("request is in %s status and cannot be approved", jr.Status)
 	}
@@ -111,3 +147,64 @@ func (s *Service) ListRequests(ctx context.Context, jurisdictionID uuid.UUID, st
 
 	return s.repo.List(ctx, jurisdictionID, status, pageSize, offset)
 }
+
+// ListFlagged returns the applications in a jurisdiction that screening held for moderation.
+// They show applicants' contact details and screening reasons, so only leaders at or above the
+// jurisdiction may list them.
+func (s *Service) ListFlagged(ctx context.Context, jurisdictionID, userID uuid.UUID, page, pageSize int) ([]*models.JoinRequest, error) {
+	if err := s.checkLeaderAccess(ctx, userID, jurisdictionID); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+
+	return s.repo.ListFlagged(ctx, jurisdictionID, pageSize, offset)
+}
+
+// ModerateRequest clears a flagged application, which then joins the committee's list, or
+// confirms it as spam, which hides it for good. Only leaders at or above the application's
+// jurisdiction can decide.
+func (s *Service) ModerateRequest(ctx context.Context, id uuid.UUID, decision string, actorID uuid.UUID) error {
+	jr, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkLeaderAccess(ctx, actorID, jr.JurisdictionID); err != nil {
+		return err
+	}
+
+	switch decision {
+	case models.ModerationApprove:
+		return s.repo.Moderate(ctx, id, models.ModerationClear, "Cleared by a moderator", actorID)
+	case models.ModerationReject:
+		return s.repo.Moderate(ctx, id, models.ModerationSpam, "Confirmed as spam", actorID)
+	default:
+		return ErrUnknownDecision
+	}
+}
+
+// checkLeaderAccess allows the Super Admin and committee leaders at or above the jurisdiction
+func (s *Service) checkLeaderAccess(ctx context.Context, userID, jurisdictionID uuid.UUID) error {
+	authority, err := s.userRepo.GetUserAuthority(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if authority.SuperAdmin {
+		return nil
+	}
+	if authority.JurisdictionID != nil && authority.IsLeader() {
+		ok, err := s.org.IsChildJurisdiction(ctx, *authority.JurisdictionID, jurisdictionID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return ErrNotLeader
+}
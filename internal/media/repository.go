@@ -0,0 +1,149 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Tables holding uploaded files
+const (
+	TableComplaintEvidence = "complaint_evidence"
+	TableActivityProofs    = "activity_proofs"
+)
+
+// anonymousExpr tells, per table, whether a row's uploader must stay anonymous
+var anonymousExpr = map[string]string{
+	TableComplaintEvidence: `(uploaded_by IS NULL OR EXISTS (
+		SELECT 1 FROM complaints c WHERE c.id = complaint_evidence.complaint_id AND c.is_anonymous))`,
+	TableActivityProofs: `FALSE`,
+}
+
+// Job is an uploaded file waiting to be processed
+type Job struct {
+	Table     string
+	ID        uuid.UUID
+	FilePath  string
+	FileType  string
+	Attempts  int
+	Anonymous bool
+}
+
+// Outcome is the result of processing a job
+type Outcome struct {
+	FilePath      string
+	FileType      string
+	FileSize      int64
+	ContentHash   string
+	OriginalPath  *string
+	ThumbnailPath *string
+}
+
+// Repository handles database operations for the media pipeline
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new media repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// ClaimJobs marks up to limit pending rows as processing and returns them. Rows left in
+// processing longer than staleAfter (a crashed worker) are claimed again.
+func (r *Repository) ClaimJobs(ctx context.Context, table string, limit int, staleAfter time.Duration) ([]*Job, error) {
+	anon, ok := anonymousExpr[table]
+	if !ok {
+		return nil, fmt.Errorf("unknown media table %q", table)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %[1]s
+		SET processing_status = 'processing',
+		    processing_attempts = processing_attempts + 1,
+		    claimed_at = NOW()
+		WHERE id IN (
+			SELECT id FROM %[1]s
+			WHERE processing_status = 'pending'
+			   OR (processing_status = 'processing' AND claimed_at < NOW() - $2::interval)
+			ORDER BY created_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, file_path, COALESCE(file_type, ''), processing_attempts, %[2]s
+	`, table, anon)
+
+	rows, err := r.db.Query(ctx, query, limit, fmt.Sprintf("%d seconds", int(staleAfter.Seconds())))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		j := &Job{Table: table}
+		if err := rows.Scan(&j.ID, &j.FilePath, &j.FileType, &j.Attempts, &j.Anonymous); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// Complete stores the processed file on the row
+func (r *Repository) Complete(ctx context.Context, j *Job, o *Outcome) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET processing_status = 'processed', processing_error = NULL, processed_at = NOW(),
+		    file_path = $2, file_type = $3, file_size = $4, content_hash = $5,
+		    original_path = $6, thumbnail_path = $7
+		WHERE id = $1
+	`, j.Table)
+	_, err := r.db.Exec(ctx, query, j.ID, o.FilePath, o.FileType, o.FileSize, o.ContentHash, o.OriginalPath, o.ThumbnailPath)
+	return err
+}
+
+// SetStatus records a terminal or retry status with an optional error message
+func (r *Repository) SetStatus(ctx context.Context, j *Job, status, message string) error {
+	var msg *string
+	if message != "" {
+		msg = &message
+	}
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET processing_status = $2, processing_error = $3,
+		    processed_at = CASE WHEN $2 = 'pending' THEN NULL ELSE NOW() END
+		WHERE id = $1
+	`, j.Table)
+	_, err := r.db.Exec(ctx, query, j.ID, status, msg)
+	return err
+}
+
+// Reject marks a file as rejected. For anonymous uploaders the link to the stored file is
+// dropped too, so it can be deleted.
+func (r *Repository) Reject(ctx context.Context, j *Job, message string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET processing_status = $2, processing_error = $3, processed_at = NOW(),
+		    file_path = CASE WHEN $4 THEN '' ELSE file_path END
+		WHERE id = $1
+	`, j.Table)
+	_, err := r.db.Exec(ctx, query, j.ID, models.MediaStatusRejected, message, j.Anonymous)
+	return err
+}
+
+// IsReferenced reports whether any record still points at a stored object
+func (r *Repository) IsReferenced(ctx context.Context, key string) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM complaint_evidence WHERE file_path = $1 OR original_path = $1 OR thumbnail_path = $1)
+		    OR EXISTS (SELECT 1 FROM activity_proofs WHERE file_path = $1 OR original_path = $1 OR thumbnail_path = $1)
+		    OR EXISTS (SELECT 1 FROM finance_transactions WHERE evidence_path = $1)
+	`
+	var found bool
+	err := r.db.QueryRow(ctx, query, key).Scan(&found)
+	return found, err
+}
@@ -0,0 +1,213 @@
+// Package media runs the background pipeline that sanitizes uploaded proofs and evidence
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/bjdms/api/internal/models"
+	pkgmedia "github.com/bjdms/api/pkg/media"
+	"github.com/bjdms/api/pkg/storage"
+)
+
+// Worker settings
+const (
+	pollInterval = 5 * time.Second
+	batchSize    = 10
+	staleAfter   = 10 * time.Minute
+	maxAttempts  = 3
+	maxInputSize = 64 << 20
+)
+
+// Worker processes uploaded files in the background
+type Worker struct {
+	repo  *Repository
+	store storage.Store
+}
+
+// NewWorker creates a media worker
+func NewWorker(repo *Repository, store storage.Store) *Worker {
+	return &Worker{repo: repo, store: store}
+}
+
+// Run polls for pending files until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, table := range []string{TableComplaintEvidence, TableActivityProofs} {
+			w.drain(ctx, table)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain processes batches from one table until it is empty
+func (w *Worker) drain(ctx context.Context, table string) {
+	for ctx.Err() == nil {
+		jobs, err := w.repo.ClaimJobs(ctx, table, batchSize, staleAfter)
+		if err != nil {
+			log.Printf("media: failed to claim %s jobs: %v", table, err)
+			return
+		}
+		if len(jobs) == 0 {
+			return
+		}
+		for _, j := range jobs {
+			w.process(ctx, j)
+		}
+	}
+}
+
+func (w *Worker) process(ctx context.Context, j *Job) {
+	if j.Attempts > maxAttempts {
+		w.setStatus(ctx, j, models.MediaStatusFailed, "gave up after repeated errors")
+		return
+	}
+
+	err := w.sanitize(ctx, j)
+	switch {
+	case err == nil:
+	case errors.Is(err, pkgmedia.ErrUnsupported) && j.Anonymous:
+		// Uploads are limited to images, but a complaint may become anonymous after its evidence
+		// was attached. A file that cannot be sanitized must never be served for an anonymous sender.
+		if rerr := w.repo.Reject(ctx, j, "file type cannot be sanitized for an anonymous sender"); rerr != nil {
+			log.Printf("media: failed to reject %s %s: %v", j.Table, j.ID, rerr)
+			return
+		}
+		w.deleteIfUnreferenced(ctx, j.FilePath)
+	case errors.Is(err, pkgmedia.ErrUnsupported):
+		w.setStatus(ctx, j, models.MediaStatusSkipped, "")
+	case errors.Is(err, pkgmedia.ErrInvalid):
+		if rerr := w.repo.Reject(ctx, j, err.Error()); rerr != nil {
+			log.Printf("media: failed to reject %s %s: %v", j.Table, j.ID, rerr)
+			return
+		}
+		if j.Anonymous {
+			w.deleteIfUnreferenced(ctx, j.FilePath)
+		}
+	default:
+		log.Printf("media: processing %s %s failed (attempt %d): %v", j.Table, j.ID, j.Attempts, err)
+		status := models.MediaStatusPending
+		if j.Attempts >= maxAttempts {
+			status = models.MediaStatusFailed
+		}
+		w.setStatus(ctx, j, status, err.Error())
+	}
+}
+
+// sanitize strips metadata, stores the clean file and thumbnail, and updates the row
+func (w *Worker) sanitize(ctx context.Context, j *Job) error {
+	// 1. Load the upload
+	rc, _, err := w.store.Get(ctx, j.FilePath)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, maxInputSize+1))
+	rc.Close()
+	if err != nil {
+		return err
+	}
+	if len(data) > maxInputSize {
+		return fmt.Errorf("%w: file is too large to process", pkgmedia.ErrInvalid)
+	}
+
+	// 2. Re-encode
+	res, err := pkgmedia.Process(data, j.FileType)
+	if err != nil {
+		return err
+	}
+
+	// 3. Store the results
+	cleanKey, cleanHash, err := w.put(ctx, res.Data, res.ContentType)
+	if err != nil {
+		return err
+	}
+	var thumbKey *string
+	if res.Thumbnail != nil {
+		key, _, err := w.put(ctx, res.Thumbnail, "image/jpeg")
+		if err != nil {
+			return err
+		}
+		thumbKey = &key
+	}
+
+	// 4. Point the row at the clean file; the original is kept only for known uploaders.
+	// The hash is replaced too, so an anonymous upload cannot be matched to the file on the sender's device.
+	outcome := &Outcome{
+		FilePath:      cleanKey,
+		FileType:      res.ContentType,
+		FileSize:      int64(len(res.Data)),
+		ContentHash:   cleanHash,
+		ThumbnailPath: thumbKey,
+	}
+	if !j.Anonymous {
+		original := j.FilePath
+		outcome.OriginalPath = &original
+	}
+	if err := w.repo.Complete(ctx, j, outcome); err != nil {
+		return err
+	}
+
+	if j.Anonymous && cleanKey != j.FilePath {
+		w.deleteIfUnreferenced(ctx, j.FilePath)
+	}
+	return nil
+}
+
+// put stores content under its content-addressed key unless it is already present
+func (w *Worker) put(ctx context.Context, data []byte, contentType string) (string, string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	key := storage.ContentKey(hash)
+
+	exists, err := w.store.Exists(ctx, key)
+	if err != nil {
+		return "", "", err
+	}
+	if !exists {
+		info := storage.ObjectInfo{Key: key, Size: int64(len(data)), ContentType: contentType, SHA256: hash}
+		if err := w.store.Put(ctx, key, bytes.NewReader(data), info); err != nil {
+			return "", "", err
+		}
+	}
+	return key, hash, nil
+}
+
+// deleteIfUnreferenced removes an anonymous original once no record needs it. Identical
+// uploads share one object, so another row may still be waiting to process it.
+func (w *Worker) deleteIfUnreferenced(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	referenced, err := w.repo.IsReferenced(ctx, key)
+	if err != nil {
+		log.Printf("media: failed to check references of %s: %v", key, err)
+		return
+	}
+	if referenced {
+		return
+	}
+	if err := w.store.Delete(ctx, key); err != nil {
+		log.Printf("media: failed to delete anonymous original %s: %v", key, err)
+	}
+}
+
+func (w *Worker) setStatus(ctx context.Context, j *Job, status, message string) {
+	if err := w.repo.SetStatus(ctx, j, status, message); err != nil {
+		log.Printf("media: failed to set %s %s to %s: %v", j.Table, j.ID, status, err)
+	}
+}
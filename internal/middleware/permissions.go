@@ -1,62 +1,84 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/bjdms/api/internal/auth"
-	"github.com/bjdms/api/internal/committee"
 	"github.com/bjdms/api/pkg/response"
 	"github.com/google/uuid"
 )
 
-// RBACMiddleware checks if the user has a specific role (position rank)
-func RBACMiddleware(minRank int) func(http.Handler) http.Handler {
+// JurisdictionChecker resolves jurisdiction hierarchy (implemented by committee.Service)
+type JurisdictionChecker interface {
+	IsChildJurisdiction(ctx context.Context, parentID, targetID uuid.UUID) (bool, error)
+}
+
+// RBACMiddleware checks if the user is a Super Admin or holds a position of at least the given
+// rank (positions.rank). Lower rank number = higher authority, at any jurisdiction level.
+func RBACMiddleware(authRepo *auth.Repository, minRank int) func(http.Handler) http.Handler {
+	return authorityMiddleware(authRepo, func(a *auth.Authority) bool {
+		return a.SuperAdmin || a.Rank <= minRank
+	})
+}
+
+// SuperAdminMiddleware admits only Super Admin accounts
+func SuperAdminMiddleware(authRepo *auth.Repository) func(http.Handler) http.Handler {
+	return authorityMiddleware(authRepo, func(a *auth.Authority) bool {
+		return a.SuperAdmin
+	})
+}
+
+func authorityMiddleware(authRepo *auth.Repository, allowed func(*auth.Authority) bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			claims := GetClaims(r.Context())
-			if claims == nil {
+			userID, err := uuid.Parse(GetUserID(r.Context()))
+			if err != nil {
 				response.Unauthorized(w, "Not authenticated")
 				return
 			}
 
-			// For now, we assume claims contain the user's rank/role
-			// In a real system, we'd fetch this from the current session/cache
-			// Logic: Lower rank number = higher authority
-			// Super Admin = Rank 0 or 1
-			// If minRank is 3, ranks 1, 2, 3 can pass.
-			
-			// Mock: If user has a rank in claims (need to add it)
-			// For now, let's just make it a placeholder that we'll extend
+			authority, err := authRepo.GetUserAuthority(r.Context(), userID)
+			if err != nil {
+				response.Unauthorized(w, "User authorization details not found")
+				return
+			}
+
+			if !allowed(authority) {
+				response.Forbidden(w, "You do not have sufficient rank for this action")
+				return
+			}
+
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
 // ABACJurisdictionMiddleware ensures the user can only manage their own or child jurisdictions
-func ABACJurisdictionMiddleware(committeeService *committee.Service, authRepo *auth.Repository) func(http.Handler) http.Handler {
+func ABACJurisdictionMiddleware(committeeService JurisdictionChecker, authRepo *auth.Repository) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// 1. Get UserID from context
-			userIDStr := GetUserID(r.Context())
-			if userIDStr == "" {
+			userID, err := uuid.Parse(GetUserID(r.Context()))
+			if err != nil {
 				response.Unauthorized(w, "Not authenticated")
 				return
 			}
-			userID, _ := uuid.Parse(userIDStr)
 
 			// 2. Fetch User's jurisdiction from DB
-			userJurisID, rank, err := authRepo.GetUserAuthDetails(r.Context(), userID)
+			authority, err := authRepo.GetUserAuthority(r.Context(), userID)
 			if err != nil {
 				response.Unauthorized(w, "User authorization details not found")
 				return
 			}
 
-			// Super Admin (Rank 1) can manage everything
-			if rank == 1 {
+			// Super Admin can manage everything
+			if authority.SuperAdmin {
 				next.ServeHTTP(w, r)
 				return
 			}
 
+			userJurisID := authority.JurisdictionID
 			if userJurisID == nil {
 				response.Forbidden(w, "You must be part of a committee to manage organizational units")
 				return
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -11,10 +12,11 @@ import (
 
 // RateLimiter implements a simple in-memory leaky bucket rate limiter
 type RateLimiter struct {
-	requests map[string][]time.Time
-	mu       sync.Mutex
-	limit    int
-	window   time.Duration
+	requests  map[string][]time.Time
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	lastSweep time.Time
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -29,29 +31,64 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 // Limit middleware restricts requests by IP
 func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := r.RemoteAddr // Simplistic, in production use X-Forwarded-For
+		// Every connection from one client shares a bucket
+		ip := ClientIP(r)
 
-		rl.mu.Lock()
-		defer rl.mu.Unlock()
-
-		now := time.Now()
-		cutoff := now.Add(-rl.window)
-
-		// Clean up old requests
-		validRequests := []time.Time{}
-		for _, reqTime := range rl.requests[ip] {
-			if reqTime.After(cutoff) {
-				validRequests = append(validRequests, reqTime)
-			}
-		}
-
-		if len(validRequests) >= rl.limit {
+		// The lock only covers the count; the request itself runs unlocked
+		if !rl.allow(ip) {
 			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(rl.window.Seconds())))
 			response.Error(w, http.StatusTooManyRequests, "too_many_requests", "Rate limit exceeded. Please try again later.", "")
 			return
 		}
-
-		rl.requests[ip] = append(validRequests, now)
 		next.ServeHTTP(w, r)
 	})
 }
+
+// allow records a request from the key, unless it has reached the limit for the window
+func (rl *RateLimiter) allow(key string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-rl.window)
+
+	// Forget clients with no requests left in the window, at most once per window
+	if now.Sub(rl.lastSweep) >= rl.window {
+		rl.evict(cutoff)
+		rl.lastSweep = now
+	}
+
+	// Clean up old requests
+	validRequests := []time.Time{}
+	for _, reqTime := range rl.requests[key] {
+		if reqTime.After(cutoff) {
+			validRequests = append(validRequests, reqTime)
+		}
+	}
+
+	if len(validRequests) >= rl.limit {
+		rl.requests[key] = validRequests
+		return false
+	}
+
+	rl.requests[key] = append(validRequests, now)
+	return true
+}
+
+// evict removes the keys whose latest request is older than the cutoff
+func (rl *RateLimiter) evict(cutoff time.Time) {
+	for key, times := range rl.requests {
+		if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+			delete(rl.requests, key)
+		}
+	}
+}
+
+// ClientIP returns the caller's address without the port. TrustedProxies.RealIP has already
+// applied the headers of trusted proxies.
+func ClientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterLimit(t *testing.T) {
+	rl := NewRateLimiter(2, time.Minute)
+	handler := rl.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "198.51.100.7:1234"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("request %d: status = %d, want %d", i+1, rec.Code, want)
+		}
+	}
+
+	// Other clients have their own bucket
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "198.51.100.8:1234"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("other client: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRateLimiterDoesNotHoldLockDuringRequest(t *testing.T) {
+	rl := NewRateLimiter(10, time.Minute)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slow := rl.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	fast := rl.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	go slow.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	<-started
+	defer close(release)
+
+	done := make(chan struct{})
+	go func() {
+		fast.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("request waited behind a slow request on the same limiter")
+	}
+}
+
+func TestRateLimiterEvictsExpiredKeys(t *testing.T) {
+	rl := NewRateLimiter(5, 20*time.Millisecond)
+	for _, key := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+		rl.allow(key)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	rl.allow("198.51.100.4")
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if len(rl.requests) != 1 {
+		t.Errorf("tracking %d clients, want 1 after the others expired", len(rl.requests))
+	}
+}
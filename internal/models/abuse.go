@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Moderation status of a public submission
+const (
+	ModerationClear   = "clear"
+	ModerationFlagged = "flagged" // Held for a moderator
+	ModerationSpam    = "spam"    // Confirmed by a moderator; hidden
+)
+
+// Moderation decisions
+const (
+	ModerationApprove = "approve"
+	ModerationReject  = "spam"
+)
+
+// PowChallenge is a proof-of-work puzzle for a public form. The client finds a nonce such that
+// SHA-256 of "<challenge>:<nonce>" starts with Difficulty zero bits, and sends both back.
+type PowChallenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
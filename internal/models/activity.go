@@ -29,19 +29,110 @@ type Activity struct {
 	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt      *time.Time `json:"-" db:"deleted_at"`
 
+	// Review by a superior committee
+	ReviewStatus string     `json:"review_status" db:"review_status"`
+	ReviewedBy   *uuid.UUID `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewNote   *string    `json:"review_note,omitempty" db:"review_note"`
+
 	// Joined fields
 	UserName         string `json:"user_name,omitempty" db:"user_name"`
 	JurisdictionName string `json:"jurisdiction_name,omitempty" db:"jurisdiction_name"`
 }
 
-// ActivityProof represents an uploaded file as evidence for an activity
+// ActivityPatch lists the activity fields to change; nil fields are left as they are
+type ActivityPatch struct {
+	Title        *string    `json:"title"`
+	Description  *string    `json:"description"`
+	Category     *string    `json:"category"`
+	ActivityDate *time.Time `json:"activity_date"`
+}
+
+// Activity review states
+const (
+	ReviewSubmitted = "submitted"
+	ReviewVerified  = "verified"
+	ReviewDisputed  = "disputed"
+)
+
+// ActivityReview is an entry in an activity's review history
+type ActivityReview struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	ActivityID   uuid.UUID `json:"activity_id" db:"activity_id"`
+	ReviewerID   uuid.UUID `json:"reviewer_id" db:"reviewer_id"`
+	ReviewerName string    `json:"reviewer_name" db:"reviewer_name"`
+	FromStatus   string    `json:"from_status" db:"from_status"`
+	ToStatus     string    `json:"to_status" db:"to_status"`
+	Note         *string   `json:"note,omitempty" db:"note"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ScoreWeight is the points a verified activity of a category earns
+type ScoreWeight struct {
+	Category      string     `json:"category" db:"category"`
+	Points        float64    `json:"points" db:"points"`
+	ProofBonus    float64    `json:"proof_bonus" db:"proof_bonus"` // Per proof file
+	MaxProofBonus float64    `json:"max_proof_bonus" db:"max_proof_bonus"`
+	UpdatedBy     *uuid.UUID `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// MemberScore is a member's score for a month
+type MemberScore struct {
+	Rank             int        `json:"rank"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id"`
+	UserName         string     `json:"user_name" db:"user_name"`
+	JurisdictionID   *uuid.UUID `json:"jurisdiction_id,omitempty" db:"jurisdiction_id"`
+	JurisdictionName string     `json:"jurisdiction_name,omitempty" db:"jurisdiction_name"`
+	ActivityCount    int        `json:"activity_count" db:"activity_count"`
+	ProofCount       int        `json:"proof_count" db:"proof_count"`
+	Score            float64    `json:"score" db:"score"`
+}
+
+// JurisdictionScore is a jurisdiction's score for a month
+type JurisdictionScore struct {
+	Rank             int       `json:"rank"`
+	JurisdictionID   uuid.UUID `json:"jurisdiction_id" db:"jurisdiction_id"`
+	JurisdictionName string    `json:"jurisdiction_name" db:"jurisdiction_name"`
+	Level            string    `json:"level" db:"level"`
+	OwnScore         float64   `json:"own_score" db:"own_score"`
+	TotalScore       float64   `json:"total_score" db:"total_score"` // Including jurisdictions below
+	ActivityCount    int       `json:"activity_count" db:"activity_count"`
+	MemberCount      int       `json:"member_count" db:"member_count"`
+}
+
+// Leaderboard is a ranked monthly score table
+type Leaderboard struct {
+	Month         string               `json:"month"` // YYYY-MM
+	ComputedAt    *time.Time           `json:"computed_at,omitempty"`
+	Members       []*MemberScore       `json:"members,omitempty"`
+	Jurisdictions []*JurisdictionScore `json:"jurisdictions,omitempty"`
+}
+
+// ActivityProof represents an uploaded file as evidence for an activity or a task
 type ActivityProof struct {
-	ID         uuid.UUID `json:"id" db:"id"`
-	ActivityID uuid.UUID `json:"activity_id" db:"activity_id"`
-	FilePath   string    `json:"file_path" db:"file_path"`
-	FileType   string    `json:"file_type" db:"file_type"`
-	FileSize   int       `json:"file_size" db:"file_size"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ActivityID   *uuid.UUID `json:"activity_id,omitempty" db:"activity_id"`
+	TaskID       *uuid.UUID `json:"task_id,omitempty" db:"task_id"`
+	FilePath     string     `json:"file_path" db:"file_path"` // Object store key
+	FileType     string     `json:"file_type" db:"file_type"`
+	FileSize     int64      `json:"file_size" db:"file_size"`
+	ContentHash  string     `json:"content_hash" db:"content_hash"`
+	OriginalName string     `json:"original_name,omitempty" db:"original_name"`
+	UploadedBy   *uuid.UUID `json:"uploaded_by,omitempty" db:"uploaded_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+
+	// Media pipeline
+	ProcessingStatus string     `json:"processing_status" db:"processing_status"`
+	ProcessingError  *string    `json:"processing_error,omitempty" db:"processing_error"`
+	ProcessedAt      *time.Time `json:"processed_at,omitempty" db:"processed_at"`
+	OriginalPath     *string    `json:"-" db:"original_path"` // Untouched upload; never kept for anonymous uploaders
+	ThumbnailPath    *string    `json:"-" db:"thumbnail_path"`
+
+	// Signed download links, valid for a short time. Withheld until processing has finished.
+	URL          string `json:"url,omitempty" db:"-"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty" db:"-"`
+	OriginalURL  string `json:"original_url,omitempty" db:"-"`
 }
 
 // Task statuses
@@ -70,6 +161,178 @@ type Task struct {
 	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt      *time.Time `json:"-" db:"deleted_at"`
+
+	// Completion requirements
+	RequiresCompletionNote bool    `json:"requires_completion_note" db:"requires_completion_note"`
+	RequiresProof          bool    `json:"requires_proof" db:"requires_proof"`
+	CompletionNote         *string `json:"completion_note,omitempty" db:"completion_note"`
+
+	// Deadline tracking, maintained by the escalation job
+	RemindedAt      *time.Time `json:"-" db:"reminded_at"`
+	OverdueAt       *time.Time `json:"overdue_at,omitempty" db:"overdue_at"`
+	EscalationLevel int        `json:"escalation_level" db:"escalation_level"`
+	EscalatedAt     *time.Time `json:"escalated_at,omitempty" db:"escalated_at"`
+
+	// Set on tasks generated from a recurring template
+	TemplateID *uuid.UUID `json:"template_id,omitempty" db:"template_id"`
+
+	// Per-member progress, set for tasks fanned out to a committee
+	Progress *TaskProgress `json:"progress,omitempty" db:"-"`
+}
+
+// TaskPatch lists the task fields to change; nil fields are left as they are
+type TaskPatch struct {
+	Title                  *string    `json:"title"`
+	Description            *string    `json:"description"`
+	Priority               *int       `json:"priority"`
+	DueDate                *time.Time `json:"due_date"`
+	RequiresCompletionNote *bool      `json:"requires_completion_note"`
+	RequiresProof          *bool      `json:"requires_proof"`
+}
+
+// Task template assignment modes
+const (
+	TemplateAssignLeader    = "leader"    // The committee head (or secretary) of each jurisdiction
+	TemplateAssignCommittee = "committee" // Every member of each jurisdiction's committee
+)
+
+// TaskTemplate is a recurring duty that generates tasks for every jurisdiction at a level
+type TaskTemplate struct {
+	ID                     uuid.UUID  `json:"id" db:"id"`
+	Title                  string     `json:"title" db:"title"`
+	Description            string     `json:"description" db:"description"`
+	Priority               int        `json:"priority" db:"priority"`
+	RequiresCompletionNote bool       `json:"requires_completion_note" db:"requires_completion_note"`
+	RequiresProof          bool       `json:"requires_proof" db:"requires_proof"`
+	JurisdictionID         uuid.UUID  `json:"jurisdiction_id" db:"jurisdiction_id"`
+	LevelID                int        `json:"level_id" db:"level_id"`
+	AssignTo               string     `json:"assign_to" db:"assign_to"`
+	RRule                  string     `json:"rrule" db:"rrule"`
+	StartsAt               time.Time  `json:"starts_at" db:"starts_at"`
+	LeadDays               int        `json:"lead_days" db:"lead_days"`
+	IsPaused               bool       `json:"is_paused" db:"is_paused"`
+	NextDueAt              *time.Time `json:"next_due_at,omitempty" db:"next_due_at"`
+	LastGeneratedAt        *time.Time `json:"last_generated_at,omitempty" db:"last_generated_at"`
+	CreatedBy              uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt              time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt              *time.Time `json:"-" db:"deleted_at"`
+
+	// Joined fields
+	JurisdictionName string `json:"jurisdiction_name,omitempty" db:"jurisdiction_name"`
+	LevelName        string `json:"level_name,omitempty" db:"level_name"`
+}
+
+// TemplateTarget is a jurisdiction that receives a template's tasks
+type TemplateTarget struct {
+	JurisdictionID uuid.UUID  `json:"jurisdiction_id"`
+	Name           string     `json:"name"`
+	CommitteeID    *uuid.UUID `json:"committee_id,omitempty"` // Nil when there is no active committee; no task is created
+	LeaderID       *uuid.UUID `json:"leader_id,omitempty"`
+	LeaderName     string     `json:"leader_name,omitempty"`
+}
+
+// TemplateOccurrence is one upcoming round of a template
+type TemplateOccurrence struct {
+	DueAt    time.Time `json:"due_at"`
+	CreateAt time.Time `json:"create_at"` // When the tasks will be generated
+}
+
+// TemplatePreview shows what a template will generate
+type TemplatePreview struct {
+	RRule       string                `json:"rrule"`
+	Occurrences []*TemplateOccurrence `json:"occurrences"`
+	Targets     []*TemplateTarget     `json:"targets"`
+	Skipped     int                   `json:"skipped"` // Targets without an active committee
+}
+
+// Task escalation levels
+const (
+	TaskEscalationNone      = 0
+	TaskEscalationCommittee = 1 // The assignee's committee leadership has been alerted
+	TaskEscalationParent    = 2 // The parent jurisdiction's leadership has been alerted
+)
+
+// OverdueCount reports open overdue tasks in one jurisdiction
+type OverdueCount struct {
+	JurisdictionID uuid.UUID  `json:"jurisdiction_id"`
+	ParentID       *uuid.UUID `json:"parent_id,omitempty"`
+	Name           string     `json:"name"`
+	NameBn         *string    `json:"name_bn,omitempty"`
+	Level          string     `json:"level"`
+	Depth          int        `json:"depth"`
+	OpenTasks      int        `json:"open_tasks"`
+	Overdue        int        `json:"overdue"`
+	Escalated      int        `json:"escalated"`        // Reached committee leadership or beyond
+	EscalatedUp    int        `json:"escalated_parent"` // Reached the parent jurisdiction
+}
+
+// OverdueReport is the overdue task breakdown of a jurisdiction subtree
+type OverdueReport struct {
+	RootID        uuid.UUID       `json:"root_id"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+	OpenTasks     int             `json:"open_tasks"`
+	Overdue       int             `json:"overdue"`
+	Escalated     int             `json:"escalated"`
+	EscalatedUp   int             `json:"escalated_parent"`
+	Jurisdictions []*OverdueCount `json:"jurisdictions"`
+}
+
+// TaskProgress aggregates the member assignments of a committee task
+type TaskProgress struct {
+	Total      int    `json:"total"`
+	Pending    int    `json:"pending"`
+	InProgress int    `json:"in_progress"`
+	Completed  int    `json:"completed"`
+	Summary    string `json:"summary"` // e.g. "17/31 completed"
+}
+
+// TaskAssignment is one committee member's share of a committee task
+type TaskAssignment struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TaskID      uuid.UUID  `json:"task_id" db:"task_id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	Status      string     `json:"status" db:"status"`
+	Note        *string    `json:"note,omitempty" db:"note"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	UserName string `json:"user_name,omitempty" db:"user_name"`
+}
+
+// Task log actions
+const (
+	TaskActionCreated      = "created"
+	TaskActionStatusChange = "status_change"
+	TaskActionProofAdded   = "proof_added"
+	TaskActionAssignment   = "assignment_update"
+	TaskActionReminder     = "reminder_sent"
+	TaskActionOverdue      = "overdue"
+	TaskActionEscalated    = "escalated"
+	TaskActionUpdated      = "updated"
+	TaskActionDeleted      = "deleted"
+	TaskActionRestored     = "restored"
+)
+
+// TaskLog represents an entry in a task's history
+type TaskLog struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TaskID    uuid.UUID  `json:"task_id" db:"task_id"`
+	UserID    *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	Action    string     `json:"action" db:"action"`
+	OldStatus *string    `json:"old_status,omitempty" db:"old_status"`
+	NewStatus *string    `json:"new_status,omitempty" db:"new_status"`
+	Note      *string    `json:"note,omitempty" db:"note"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	// Set for assignment_update entries: whose assignment changed
+	AssignmentUserID *uuid.UUID `json:"assignment_user_id,omitempty" db:"assignment_user_id"`
+
+	// Joined fields
+	UserName           string `json:"user_name,omitempty" db:"user_name"`
+	AssignmentUserName string `json:"assignment_user_name,omitempty" db:"assignment_user_name"`
 }
 
 // Event represents an organized gathering or program
@@ -83,15 +346,139 @@ type Event struct {
 	StartTime      time.Time  `json:"start_time" db:"start_time"`
 	EndTime        *time.Time `json:"end_time" db:"end_time"`
 	IsPublic       bool       `json:"is_public" db:"is_public"`
-	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt      *time.Time `json:"-" db:"deleted_at"`
+	Capacity       *int       `json:"capacity" db:"capacity"`           // Nil = unlimited
+	RSVPDeadline   *time.Time `json:"rsvp_deadline" db:"rsvp_deadline"` // Nil = until the event starts
+
+	// Self check-in rules
+	Latitude          *float64 `json:"latitude" db:"latitude"`
+	Longitude         *float64 `json:"longitude" db:"longitude"`
+	GeofenceRadius    *int     `json:"geofence_radius_m" db:"geofence_radius_m"` // Nil = check-in from anywhere
+	EnforceTimeWindow bool     `json:"enforce_time_window" db:"enforce_time_window"`
+
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
+
+	// Computed fields
+	Going      int `json:"going"`
+	Waitlisted int `json:"waitlisted"`
+}
+
+// EventPatch lists the event fields to change; nil fields are left as they are. Capacity and
+// check-in rules have their own endpoints.
+type EventPatch struct {
+	Title        *string    `json:"title"`
+	Description  *string    `json:"description"`
+	Location     *string    `json:"location"`
+	StartTime    *time.Time `json:"start_time"`
+	EndTime      *time.Time `json:"end_time"`
+	IsPublic     *bool      `json:"is_public"`
+	RSVPDeadline *time.Time `json:"rsvp_deadline"`
 }
 
+// Check-in methods
+const (
+	CheckInManual  = "manual"  // Recorded by an organizer
+	CheckInQR      = "qr"      // Member scanned the event's QR code
+	CheckInOffline = "offline" // Collected offline by an organizer and uploaded later
+)
+
 // EventAttendance tracks participation in an event
 type EventAttendance struct {
-	ID         uuid.UUID `json:"id" db:"id"`
-	EventID    uuid.UUID `json:"event_id" db:"event_id"`
-	UserID     uuid.UUID `json:"user_id" db:"user_id"`
-	AttendedAt time.Time `json:"attended_at" db:"attended_at"`
+	ID         uuid.UUID  `json:"id" db:"id"`
+	EventID    uuid.UUID  `json:"event_id" db:"event_id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	AttendedAt time.Time  `json:"attended_at" db:"attended_at"`
+	Method     string     `json:"method" db:"method"`
+	RecordedBy *uuid.UUID `json:"recorded_by,omitempty" db:"recorded_by"`
+	Latitude   *float64   `json:"latitude,omitempty" db:"latitude"`
+	Longitude  *float64   `json:"longitude,omitempty" db:"longitude"`
+	DistanceM  *int       `json:"distance_m,omitempty" db:"distance_m"`
+	SyncedAt   *time.Time `json:"synced_at,omitempty" db:"synced_at"`
+}
+
+// CheckInToken is the current value of an event's rotating QR code
+type CheckInToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Period    int       `json:"period_seconds"`
+}
+
+// OfflineCheckIn is one entry of an offline check-in upload
+type OfflineCheckIn struct {
+	UserID      uuid.UUID `json:"user_id"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+	Latitude    *float64  `json:"latitude"`
+	Longitude   *float64  `json:"longitude"`
+}
+
+// Offline check-in outcomes
+const (
+	CheckInRecorded  = "recorded"
+	CheckInDuplicate = "duplicate" // Already checked in
+	CheckInRejected  = "rejected"
+)
+
+// CheckInResult reports what happened to one uploaded check-in
+type CheckInResult struct {
+	UserID uuid.UUID `json:"user_id"`
+	Status string    `json:"status"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// RSVP answers
+const (
+	RSVPPending    = "pending"
+	RSVPYes        = "yes"
+	RSVPNo         = "no"
+	RSVPMaybe      = "maybe"
+	RSVPWaitlisted = "waitlisted" // Answered yes while the event was full
+)
+
+// EventInvitation is a member's invitation to an event and their answer
+type EventInvitation struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	EventID      uuid.UUID  `json:"event_id" db:"event_id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	RSVP         string     `json:"rsvp" db:"rsvp"`
+	InvitedBy    *uuid.UUID `json:"invited_by,omitempty" db:"invited_by"`
+	InvitedAt    *time.Time `json:"invited_at,omitempty" db:"invited_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty" db:"responded_at"`
+	WaitlistedAt *time.Time `json:"waitlisted_at,omitempty" db:"waitlisted_at"`
+	RemindedAt   *time.Time `json:"-" db:"reminded_at"`
+
+	// Joined fields
+	UserName         string `json:"user_name,omitempty" db:"user_name"`
+	WaitlistPosition int    `json:"waitlist_position,omitempty"`
+	Attended         bool   `json:"attended"`
+}
+
+// EventAudience selects the members to invite. Filters combine: e.g. a jurisdiction subtree
+// with a position invites every holder of that position in the subtree.
+type EventAudience struct {
+	JurisdictionID *uuid.UUID  `json:"jurisdiction_id"`
+	IncludeSubtree bool        `json:"include_subtree"`
+	CommitteeID    *uuid.UUID  `json:"committee_id"`
+	PositionID     *int        `json:"position_id"`
+	UserIDs        []uuid.UUID `json:"user_ids"`
+}
+
+// EventTurnout compares RSVPs with recorded attendance
+type EventTurnout struct {
+	EventID    uuid.UUID `json:"event_id"`
+	Capacity   *int      `json:"capacity"`
+	Invited    int       `json:"invited"`
+	Pending    int       `json:"pending"`
+	Yes        int       `json:"yes"`
+	Maybe      int       `json:"maybe"`
+	No         int       `json:"no"`
+	Waitlisted int       `json:"waitlisted"`
+
+	Attended       int     `json:"attended"`        // Everyone checked in
+	AttendedYes    int     `json:"attended_yes"`    // Checked in after answering yes
+	AttendedMaybe  int     `json:"attended_maybe"`  // Checked in after answering maybe
+	NoShows        int     `json:"no_shows"`        // Answered yes but not checked in
+	WalkIns        int     `json:"walk_ins"`        // Checked in without answering yes or maybe
+	Expected       int     `json:"expected"`        // Yes answers
+	TurnoutPercent float64 `json:"turnout_percent"` // Attended yes / expected
 }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Calendar feed scopes
+const (
+	FeedScopeUser         = "user"         // Events the member organizes or is invited to, and their tasks
+	FeedScopeJurisdiction = "jurisdiction" // Events in a jurisdiction and below, and its tasks
+)
+
+// CalendarFeed is a member's iCalendar subscription
+type CalendarFeed struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	Name           string     `json:"name" db:"name"`
+	Scope          string     `json:"scope" db:"scope"`
+	JurisdictionID *uuid.UUID `json:"jurisdiction_id,omitempty" db:"jurisdiction_id"`
+	IncludeTasks   bool       `json:"include_tasks" db:"include_tasks"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+
+	// Returned once, when the feed is created
+	Token string `json:"token,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Records that can carry comments
+const (
+	CommentEntityTask        = "task"
+	CommentEntityActivity    = "activity"
+	CommentEntityComplaint   = "complaint"
+	CommentEntityJoinRequest = "join_request"
+)
+
+// Comment visibility
+const (
+	CommentVisibilityPublic   = "public"
+	CommentVisibilityInternal = "internal" // Officials handling the record only
+)
+
+// Comment is a message in the discussion thread of a task, activity, complaint or join request
+type Comment struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	EntityType string     `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID  `json:"entity_id" db:"entity_id"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty" db:"parent_id"`
+	AuthorID   *uuid.UUID `json:"author_id,omitempty" db:"author_id"` // Nil for an anonymous complainant
+	Body       string     `json:"body" db:"body"`
+	Visibility string     `json:"visibility" db:"visibility"`
+	EditedAt   *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt  *time.Time `json:"-" db:"deleted_at"`
+
+	// Joined fields
+	AuthorName      string      `json:"author_name,omitempty" db:"author_name"`
+	FromComplainant bool        `json:"from_complainant,omitempty"` // Posted with the complaint's tracking ID and secret
+	IsDeleted       bool        `json:"is_deleted,omitempty"`       // Body is withheld; the entry keeps its replies in place
+	Mentions        []uuid.UUID `json:"mentions,omitempty"`
+	Replies         []*Comment  `json:"replies,omitempty"`
+}
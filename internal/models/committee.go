@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,9 +24,25 @@ type Jurisdiction struct {
 	NameBn      *string    `json:"name_bn" db:"name_bn"`
 	IsUrban     bool       `json:"is_urban" db:"is_urban"`
 	Population  int        `json:"population" db:"population"`
+	CentroidLat *float64   `json:"centroid_lat,omitempty" db:"centroid_lat"`
+	CentroidLng *float64   `json:"centroid_lng,omitempty" db:"centroid_lng"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt   *time.Time `json:"-" db:"deleted_at"`
+
+	// Geography (only loaded by boundary-aware queries)
+	Boundary  json.RawMessage `json:"boundary,omitempty" db:"boundary"`
+	LevelRank int             `json:"-" db:"level_rank"`
+}
+
+// JurisdictionMetrics summarises organisational health for map overlays
+type JurisdictionMetrics struct {
+	HasActiveCommittee bool   `json:"has_active_committee"`
+	CommitteeType      string `json:"committee_type,omitempty"`
+	MemberCount        int    `json:"member_count"`
+	ActivityCount30d   int    `json:"activity_count_30d"`
+	OpenComplaints     int    `json:"open_complaints"`
+	ChildCount         int    `json:"child_count"`
 }
 
 // Position represents a role in a committee
@@ -36,6 +53,38 @@ type Position struct {
 	Rank          int    `json:"rank" db:"rank"`
 	CommitteeType string `json:"committee_type" db:"committee_type"`
 	Description   string `json:"description" db:"description"`
+	MaxTerms      *int   `json:"max_terms" db:"max_terms"` // Terms a user may hold this post in one jurisdiction, nil = unlimited
+}
+
+// Position committee types (as stored in positions.committee_type)
+const (
+	PositionTypeFull         = "Full"
+	PositionTypeConvener     = "Convener"
+	PositionTypeSubCommittee = "SubCommittee"
+	PositionTypeBoth         = "Both"
+)
+
+// CommitteeStructure configures the total size of a committee per level and type
+type CommitteeStructure struct {
+	LevelID       int              `json:"level_id" db:"level_id"`
+	CommitteeType string           `json:"committee_type" db:"committee_type"`
+	MaxMembers    *int             `json:"max_members" db:"max_members"` // nil = unlimited
+	Positions     []*PositionQuota `json:"positions"`
+	UpdatedBy     *uuid.UUID       `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// PositionQuota configures an allowed position and its number of seats
+type PositionQuota struct {
+	LevelID       int    `json:"level_id" db:"level_id"`
+	CommitteeType string `json:"committee_type" db:"committee_type"`
+	PositionID    int    `json:"position_id" db:"position_id"`
+	MaxSeats      *int   `json:"max_seats" db:"max_seats"` // nil = unlimited
+
+	// Joined data
+	PositionName   string `json:"position_name,omitempty" db:"position_name"`
+	PositionNameBn string `json:"position_name_bn,omitempty" db:"position_name_bn"`
+	PositionRank   int    `json:"position_rank,omitempty" db:"position_rank"`
 }
 
 // Committee status and type constants
@@ -45,8 +94,9 @@ const (
 	StatusDissolved = "dissolved"
 	StatusExpired   = "expired"
 
-	TypeFull     = "full"
-	TypeConvener = "convener"
+	TypeFull         = "full"
+	TypeConvener     = "convener"
+	TypeSubCommittee = "sub_committee"
 )
 
 // Committee represents a Jubodal committee
@@ -61,6 +111,18 @@ type Committee struct {
 	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt      *time.Time `json:"-" db:"deleted_at"`
+
+	// Sub-committees (working groups) belong to a parent committee instead of standing for a jurisdiction
+	ParentCommitteeID *uuid.UUID `json:"parent_committee_id,omitempty" db:"parent_committee_id"`
+	Name              *string    `json:"name,omitempty" db:"name"`
+	Mandate           *string    `json:"mandate,omitempty" db:"mandate"`
+	CreatedBy         *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	MemberCount       int        `json:"member_count,omitempty" db:"-"`
+}
+
+// IsSubCommittee reports whether the committee is a working group under another committee
+func (c *Committee) IsSubCommittee() bool {
+	return c.ParentCommitteeID != nil
 }
 
 // CommitteeMember represents a user assigned to a committee
@@ -75,7 +137,98 @@ type CommitteeMember struct {
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 
 	// Joined data
-	UserName     string `json:"user_name,omitempty" db:"user_name"`
-	PositionName string `json:"position_name,omitempty" db:"position_name"`
-	PositionRank int    `json:"position_rank,omitempty" db:"position_rank"`
+	UserName       string  `json:"user_name,omitempty" db:"user_name"`
+	UserNameBn     *string `json:"user_name_bn,omitempty" db:"user_name_bn"`
+	Phone          string  `json:"phone,omitempty" db:"phone"`
+	PhotoURL       *string `json:"photo_url,omitempty" db:"photo_url"`
+	PositionName   string  `json:"position_name,omitempty" db:"position_name"`
+	PositionNameBn string  `json:"position_name_bn,omitempty" db:"position_name_bn"`
+	PositionRank   int     `json:"position_rank,omitempty" db:"position_rank"`
+}
+
+// RosterCommittee is a committee with its jurisdiction context and members, ready for export
+type RosterCommittee struct {
+	Committee          *Committee         `json:"committee"`
+	JurisdictionName   string             `json:"jurisdiction_name"`
+	JurisdictionNameBn *string            `json:"jurisdiction_name_bn,omitempty"`
+	LevelName          string             `json:"level_name"`
+	Depth              int                `json:"depth"`
+	Members            []*CommitteeMember `json:"members"`
+}
+
+// OrgChartNode is a jurisdiction node with its active committee leadership and children
+type OrgChartNode struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	NameBn    *string            `json:"name_bn,omitempty"`
+	Level     string             `json:"level"`
+	LevelID   int                `json:"level_id"`
+	Committee *Committee         `json:"committee,omitempty"`
+	Members   []*CommitteeMember `json:"members,omitempty"`
+	Children  []*OrgChartNode    `json:"children,omitempty"`
+}
+
+// CoverageRow describes the committee health of a single jurisdiction
+type CoverageRow struct {
+	JurisdictionID     uuid.UUID  `json:"jurisdiction_id"`
+	ParentID           *uuid.UUID `json:"parent_id,omitempty"`
+	Name               string     `json:"name"`
+	NameBn             *string    `json:"name_bn,omitempty"`
+	Level              string     `json:"level"`
+	Depth              int        `json:"depth"`
+	CommitteeID        *uuid.UUID `json:"committee_id,omitempty"`
+	CommitteeType      string     `json:"committee_type,omitempty"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	MemberCount        int        `json:"member_count"`
+	HasActiveCommittee bool       `json:"has_active_committee"`
+	MissingPresident   bool       `json:"missing_president"`
+	MissingSecretary   bool       `json:"missing_general_secretary"`
+	ConvenerOverdue    bool       `json:"convener_overdue"`
+	OverdueDays        int        `json:"overdue_days,omitempty"`
+}
+
+// HasIssue reports whether the row needs leadership attention
+func (r *CoverageRow) HasIssue() bool {
+	return !r.HasActiveCommittee || r.MissingPresident || r.MissingSecretary || r.ConvenerOverdue
+}
+
+// CoverageSummary aggregates coverage counts over a subtree
+type CoverageSummary struct {
+	Jurisdictions    int `json:"jurisdictions"`
+	WithoutCommittee int `json:"without_committee"`
+	MissingPresident int `json:"missing_president"`
+	MissingSecretary int `json:"missing_general_secretary"`
+	OverdueConveners int `json:"overdue_conveners"`
+}
+
+// Add counts a row into the summary
+func (s *CoverageSummary) Add(r *CoverageRow) {
+	s.Jurisdictions++
+	if !r.HasActiveCommittee {
+		s.WithoutCommittee++
+	}
+	if r.MissingPresident {
+		s.MissingPresident++
+	}
+	if r.MissingSecretary {
+		s.MissingSecretary++
+	}
+	if r.ConvenerOverdue {
+		s.OverdueConveners++
+	}
+}
+
+// CoverageChild is a direct child of the report root with its subtree totals, for drill-down
+type CoverageChild struct {
+	Row     *CoverageRow     `json:"jurisdiction"`
+	Subtree *CoverageSummary `json:"subtree"`
+}
+
+// CoverageReport is the vacancy and coverage report for a jurisdiction subtree
+type CoverageReport struct {
+	Root        *CoverageRow     `json:"root"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Summary     *CoverageSummary `json:"summary"`
+	Children    []*CoverageChild `json:"children"`
+	Issues      []*CoverageRow   `json:"issues"`
 }
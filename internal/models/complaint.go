@@ -15,6 +15,27 @@ const (
 	ComplaintStatusRejected    = "rejected"
 )
 
+// Complaint log actions
+const (
+	ComplaintActionStatusChange = "status_change"
+	ComplaintActionReopened     = "reopened"
+	ComplaintActionAssigned     = "assigned"
+	ComplaintActionEscalated    = "escalated"
+	ComplaintActionSLABreached  = "sla_breached" // Missed deadline with no parent to escalate to
+	ComplaintActionModerated    = "moderated"
+)
+
+// SLAResolution names the SLA from submission to closing or rejecting; the others are named
+// after the status they limit
+const SLAResolution = "resolution"
+
+// Complaint routing modes
+const (
+	RoutingManual     = "manual"      // Leaders are alerted and assign by hand
+	RoutingOfficer    = "officer"     // The designated complaint officer
+	RoutingRoundRobin = "round_robin" // Officers holding the pool positions, in turn
+)
+
 // Complaint represents a grievance submitted by a member or the public
 type Complaint struct {
 	ID                 uuid.UUID  `json:"id" db:"id"`
@@ -28,8 +49,18 @@ type Complaint struct {
 	Description        string     `json:"description" db:"description"`
 	Status             string     `json:"status" db:"status"`
 	AnonymousIPHash    *string    `json:"-" db:"anonymous_ip_hash"`
+	AccessSecretHash   *string    `json:"-" db:"access_secret_hash"`
 	AssignedToID       *uuid.UUID `json:"assigned_to_id,omitempty" db:"assigned_to_id"`
+	AssignedAt         *time.Time `json:"assigned_at,omitempty" db:"assigned_at"`
 	ResolutionNotes    *string    `json:"resolution_notes,omitempty" db:"resolution_notes"`
+	ClosedAt           *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+	StatusChangedAt    time.Time  `json:"status_changed_at" db:"status_changed_at"`
+	EscalationLevel    int        `json:"escalation_level" db:"escalation_level"`
+	EscalatedToID      *uuid.UUID `json:"escalated_to_id,omitempty" db:"escalated_to_id"` // Ancestor whose queue holds the complaint
+	EscalatedAt        *time.Time `json:"escalated_at,omitempty" db:"escalated_at"`
+	ConflictOfInterest bool       `json:"conflict_of_interest" db:"conflict_of_interest"` // Accuses the target committee
+	ModerationStatus   string     `json:"moderation_status" db:"moderation_status"`
+	ModerationReason   *string    `json:"moderation_reason,omitempty" db:"moderation_reason"`
 	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt          *time.Time `json:"-" db:"deleted_at"`
@@ -37,15 +68,59 @@ type Complaint struct {
 	// Joined fields
 	JurisdictionName string `json:"jurisdiction_name,omitempty" db:"jurisdiction_name"`
 	AssignedToName   string `json:"assigned_to_name,omitempty" db:"assigned_to_name"`
+	EscalatedToName  string `json:"escalated_to_name,omitempty" db:"escalated_to_name"`
+
+	Accused      []*ComplaintAccused `json:"accused,omitempty" db:"-"`
+	AccessSecret string              `json:"-" db:"-"` // Issued on submission only; handed to the complainant once
+}
+
+// ComplaintMessage is an entry of a complaint's public thread as the complainant sees it: who
+// wrote it is withheld
+type ComplaintMessage struct {
+	ID              uuid.UUID `json:"id"`
+	Body            string    `json:"body"`
+	FromComplainant bool      `json:"from_complainant"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ComplaintAccused is a member or position a complaint is about
+type ComplaintAccused struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	ComplaintID uuid.UUID  `json:"complaint_id" db:"complaint_id"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	PositionID  *int       `json:"position_id,omitempty" db:"position_id"`
+	Name        *string    `json:"name,omitempty" db:"name"`
+	InCommittee bool       `json:"in_committee" db:"in_committee"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	UserName     string `json:"user_name,omitempty" db:"user_name"`
+	PositionName string `json:"position_name,omitempty" db:"position_name"`
 }
 
 // ComplaintEvidence represents a file attached to a complaint
 type ComplaintEvidence struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	ComplaintID uuid.UUID `json:"complaint_id" db:"complaint_id"`
-	FilePath    string    `json:"file_path" db:"file_path"`
-	FileType    string    `json:"file_type" db:"file_type"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ComplaintID  uuid.UUID  `json:"complaint_id" db:"complaint_id"`
+	FilePath     string     `json:"file_path" db:"file_path"` // Object store key
+	FileType     string     `json:"file_type" db:"file_type"`
+	FileSize     int64      `json:"file_size" db:"file_size"`
+	ContentHash  string     `json:"content_hash" db:"content_hash"`
+	OriginalName string     `json:"original_name,omitempty" db:"original_name"`
+	UploadedBy   *uuid.UUID `json:"uploaded_by,omitempty" db:"uploaded_by"` // Nil for anonymous uploads
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+
+	// Media pipeline
+	ProcessingStatus string     `json:"processing_status" db:"processing_status"`
+	ProcessingError  *string    `json:"processing_error,omitempty" db:"processing_error"`
+	ProcessedAt      *time.Time `json:"processed_at,omitempty" db:"processed_at"`
+	OriginalPath     *string    `json:"-" db:"original_path"` // Untouched upload; never kept for anonymous uploaders
+	ThumbnailPath    *string    `json:"-" db:"thumbnail_path"`
+
+	// Signed download links, valid for a short time. Withheld until processing has finished.
+	URL          string `json:"url,omitempty" db:"-"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty" db:"-"`
+	OriginalURL  string `json:"original_url,omitempty" db:"-"`
 }
 
 // ComplaintLog represents an entry in the complaint's audit trail
@@ -62,3 +137,33 @@ type ComplaintLog struct {
 	// Joined fields
 	UserName string `json:"user_name,omitempty" db:"user_name"`
 }
+
+// ComplaintRouting decides who new complaints in a jurisdiction are assigned to
+type ComplaintRouting struct {
+	JurisdictionID uuid.UUID  `json:"jurisdiction_id" db:"jurisdiction_id"`
+	Mode           string     `json:"mode" db:"mode"`
+	OfficerID      *uuid.UUID `json:"officer_id,omitempty" db:"officer_id"`
+	PositionIDs    []int      `json:"position_ids" db:"position_ids"`
+	UpdatedBy      *uuid.UUID `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	OfficerName string `json:"officer_name,omitempty" db:"officer_name"`
+}
+
+// ComplaintSLAStats summarises how one jurisdiction met its complaint SLAs over a period
+type ComplaintSLAStats struct {
+	JurisdictionID    uuid.UUID `json:"jurisdiction_id" db:"jurisdiction_id"`
+	JurisdictionName  string    `json:"jurisdiction_name" db:"jurisdiction_name"`
+	Received          int       `json:"received" db:"received"`
+	Acknowledged      int       `json:"acknowledged" db:"acknowledged"`
+	AcknowledgedInSLA int       `json:"acknowledged_in_sla" db:"acknowledged_in_sla"`
+	Resolved          int       `json:"resolved" db:"resolved"`
+	ResolvedInSLA     int       `json:"resolved_in_sla" db:"resolved_in_sla"`
+	Breaches          int       `json:"breaches" db:"breaches"`
+	OpenEscalated     int       `json:"open_escalated" db:"open_escalated"`
+	AvgAckHours       *float64  `json:"avg_ack_hours,omitempty" db:"avg_ack_hours"`
+	AvgResolveHours   *float64  `json:"avg_resolve_hours,omitempty" db:"avg_resolve_hours"`
+	AckCompliance     *float64  `json:"ack_compliance,omitempty" db:"-"`     // Share acknowledged in time, 0..1
+	ResolveCompliance *float64  `json:"resolve_compliance,omitempty" db:"-"` // Share resolved in time, 0..1
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Election status constants
+const (
+	ElectionStatusDraft       = "draft"
+	ElectionStatusNominations = "nominations"
+	ElectionStatusVoting      = "voting"
+	ElectionStatusClosed      = "closed"
+	ElectionStatusCertified   = "certified"
+	ElectionStatusCancelled   = "cancelled"
+)
+
+// Election voting modes
+const (
+	VotingModeInPerson = "in_person"
+	VotingModeOnline   = "online"
+	VotingModeHybrid   = "hybrid"
+)
+
+// Ballot channels
+const (
+	BallotChannelInPerson = "in_person"
+	BallotChannelOnline   = "online"
+)
+
+// Election fills a committee's posts for its next term
+type Election struct {
+	ID                uuid.UUID       `json:"id" db:"id"`
+	CommitteeID       uuid.UUID       `json:"committee_id" db:"committee_id"`
+	JurisdictionID    uuid.UUID       `json:"jurisdiction_id" db:"jurisdiction_id"`
+	CommitteeType     string          `json:"committee_type" db:"committee_type"`
+	Title             string          `json:"title" db:"title"`
+	VotingMode        string          `json:"voting_mode" db:"voting_mode"`
+	Status            string          `json:"status" db:"status"`
+	VotingStartsAt    *time.Time      `json:"voting_starts_at,omitempty" db:"voting_starts_at"`
+	VotingEndsAt      *time.Time      `json:"voting_ends_at,omitempty" db:"voting_ends_at"`
+	ResultCommitteeID *uuid.UUID      `json:"result_committee_id,omitempty" db:"result_committee_id"`
+	CreatedBy         uuid.UUID       `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at" db:"updated_at"`
+	Posts             []*ElectionPost `json:"posts,omitempty"`
+}
+
+// ElectionPost is a position contested in an election
+type ElectionPost struct {
+	PositionID   int    `json:"position_id" db:"position_id"`
+	Seats        int    `json:"seats" db:"seats"`
+	PositionName string `json:"position_name,omitempty" db:"position_name"`
+	PositionRank int    `json:"position_rank,omitempty" db:"position_rank"`
+}
+
+// ElectionCandidate is a member standing for a post
+type ElectionCandidate struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ElectionID   uuid.UUID  `json:"election_id" db:"election_id"`
+	PositionID   int        `json:"position_id" db:"position_id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	WithdrawnAt  *time.Time `json:"withdrawn_at,omitempty" db:"withdrawn_at"`
+	RegisteredBy *uuid.UUID `json:"registered_by,omitempty" db:"registered_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+
+	// Joined data
+	UserName     string `json:"user_name,omitempty" db:"user_name"`
+	PositionName string `json:"position_name,omitempty" db:"position_name"`
+}
+
+// BallotChoice selects candidates for one post; up to the post's seat count may be chosen
+type BallotChoice struct {
+	PositionID   int         `json:"position_id"`
+	CandidateIDs []uuid.UUID `json:"candidate_ids"`
+}
+
+// Ballot is a member's vote across all posts
+type Ballot struct {
+	VoterID    uuid.UUID       `json:"voter_id"`
+	Channel    string          `json:"-"`
+	RecordedBy *uuid.UUID      `json:"-"`
+	Choices    []*BallotChoice `json:"choices"`
+}
+
+// CandidateResult is a candidate's vote count in a tally
+type CandidateResult struct {
+	CandidateID uuid.UUID `json:"candidate_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	UserName    string    `json:"user_name"`
+	Votes       int       `json:"votes"`
+	Elected     bool      `json:"elected"`
+}
+
+// PostResult is the tally for one post
+type PostResult struct {
+	PositionID   int                `json:"position_id"`
+	PositionName string             `json:"position_name"`
+	Seats        int                `json:"seats"`
+	Candidates   []*CandidateResult `json:"candidates"`
+	Tie          bool               `json:"tie"` // Candidates tied on the last seat; must be resolved before certification
+}
+
+// ElectionResult is the full tally of an election
+type ElectionResult struct {
+	ElectionID  uuid.UUID     `json:"election_id"`
+	Status      string        `json:"status"`
+	Electorate  int           `json:"electorate"`
+	BallotsCast int           `json:"ballots_cast"`
+	Posts       []*PostResult `json:"posts"`
+}
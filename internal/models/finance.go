@@ -34,7 +34,7 @@ type FinanceTransaction struct {
 	Description     string     `json:"description" db:"description"`
 	ReferenceNo     string     `json:"reference_no" db:"reference_no"`
 	TransactionDate time.Time  `json:"transaction_date" db:"transaction_date"`
-	EvidencePath    string     `json:"evidence_path" db:"evidence_path"`
+	EvidencePath    string     `json:"evidence_path" db:"evidence_path"` // Object store key of the receipt
 	Metadata        any        `json:"metadata" db:"metadata"`
 	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
 
@@ -42,6 +42,7 @@ type FinanceTransaction struct {
 	CategoryName   string `json:"category_name,omitempty" db:"category_name"`
 	CategoryNameBn string `json:"category_name_bn,omitempty" db:"category_name_bn"`
 	UserName       string `json:"user_name,omitempty" db:"user_name"`
+	EvidenceURL    string `json:"evidence_url,omitempty" db:"-"` // Signed receipt link
 }
 
 // FinanceBalance represents the cached financial standing of a jurisdiction
@@ -33,6 +33,9 @@ type JoinRequest struct {
 	ReferredByID    *uuid.UUID `json:"referred_by_id,omitempty" db:"referred_by_id"`
 	RejectionReason string    `json:"rejection_reason,omitempty" db:"rejection_reason"`
 	ProcessedByID   *uuid.UUID `json:"processed_by_id,omitempty" db:"processed_by_id"`
+	IPFingerprint   *string   `json:"-" db:"ip_fingerprint"`
+	ModerationStatus string   `json:"moderation_status" db:"moderation_status"`
+	ModerationReason *string  `json:"moderation_reason,omitempty" db:"moderation_reason"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 
@@ -0,0 +1,16 @@
+package models
+
+// Media processing statuses of uploaded files
+const (
+	MediaStatusPending    = "pending"
+	MediaStatusProcessing = "processing"
+	MediaStatusProcessed  = "processed"
+	MediaStatusSkipped    = "skipped"  // Not an image type the pipeline handles; kept as uploaded (known uploaders only)
+	MediaStatusRejected   = "rejected" // Corrupt, not the type it claimed to be, or unsanitizable from an anonymous sender
+	MediaStatusFailed     = "failed"   // Gave up after repeated errors
+)
+
+// MediaViewable reports whether a file in this status may be shown to users
+func MediaViewable(status string) bool {
+	return status == MediaStatusProcessed || status == MediaStatusSkipped
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/bjdms/api/internal/middleware"
+	"github.com/bjdms/api/pkg/response"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -32,6 +33,7 @@ func (h *Handler) Routes() chi.Router {
 	r.Get("/", h.List)
 	r.Post("/{id}/read", h.MarkAsRead)
 	r.Get("/ws", h.WebSocket)
+	r.Post("/committees/{id}", h.NotifyCommittee)
 	
 	return r
 }
@@ -83,3 +85,50 @@ func (h *Handler) WebSocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// NotifyCommittee sends an announcement to all active members of a committee or sub-committee
+func (h *Handler) NotifyCommittee(w http.ResponseWriter, r *http.Request) {
+	committeeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid committee ID")
+		return
+	}
+	senderID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		response.Unauthorized(w, "Invalid user")
+		return
+	}
+
+	var body struct {
+		Title   string          `json:"title"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Title == "" || body.Message == "" {
+		response.BadRequest(w, "title and message are required")
+		return
+	}
+
+	ok, err := h.service.CanAddressCommittee(r.Context(), senderID, committeeID)
+	if err != nil {
+		response.InternalError(w, "Failed to check permissions", "")
+		return
+	}
+	if !ok {
+		response.Forbidden(w, "Only committee leadership can message this committee")
+		return
+	}
+
+	sent, err := h.service.NotifyCommittee(r.Context(), committeeID, Notification{
+		Type:    TypeAnnouncement,
+		Title:   body.Title,
+		Message: body.Message,
+		Data:    body.Data,
+	}, &senderID)
+	if err != nil {
+		response.InternalError(w, "Failed to send notifications", "")
+		return
+	}
+
+	response.Created(w, map[string]int{"recipients": sent}, "Announcement sent")
+}
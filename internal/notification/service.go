@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/bjdms/api/internal/auth"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -17,9 +18,24 @@ type NotificationType string
 
 const (
 	TypeTaskAssigned    NotificationType = "task_assigned"
+	TypeTaskCompleted   NotificationType = "task_completed"
+	TypeTaskUpdated     NotificationType = "task_updated"
+	TypeTaskReminder    NotificationType = "task_reminder"
+	TypeTaskOverdue     NotificationType = "task_overdue"
+	TypeTaskEscalated   NotificationType = "task_escalated"
+	TypeCommentMention  NotificationType = "comment_mention"
+	TypeEventInvitation NotificationType = "event_invitation"
+	TypeEventReminder   NotificationType = "event_rsvp_reminder"
+	TypeEventSeat       NotificationType = "event_seat_confirmed"
+	TypeEventUpdated    NotificationType = "event_updated"
+	TypeActivityReview  NotificationType = "activity_reviewed"
 	TypeJoinRequest     NotificationType = "join_request"
 	TypeComplaintAlert  NotificationType = "complaint_alert"
+	TypeComplaintAssign NotificationType = "complaint_assigned"
+	TypeComplaintSLA    NotificationType = "complaint_escalated"
+	TypeComplaintReply  NotificationType = "complaint_message"
 	TypePerformanceMile NotificationType = "performance_milestone"
+	TypeAnnouncement    NotificationType = "committee_announcement"
 )
 
 type Notification struct {
@@ -75,6 +91,67 @@ func (s *Service) Create(ctx context.Context, n *Notification) error {
 	return nil
 }
 
+// NotifyCommittee delivers a copy of the notification to every active member of a committee or
+// sub-committee, skipping the excluded user (usually the sender). Returns the number of recipients.
+func (s *Service) NotifyCommittee(ctx context.Context, committeeID uuid.UUID, n Notification, exclude *uuid.UUID) (int, error) {
+	query := `
+		SELECT DISTINCT cm.user_id, c.jurisdiction_id
+		FROM committee_members cm
+		JOIN committees c ON cm.committee_id = c.id
+		WHERE cm.committee_id = $1 AND cm.ended_at IS NULL AND cm.is_active = TRUE
+	`
+	rows, err := s.db.Query(ctx, query, committeeID)
+	if err != nil {
+		return 0, err
+	}
+
+	type recipient struct{ userID, jurisdictionID uuid.UUID }
+	var recipients []recipient
+	for rows.Next() {
+		var rc recipient
+		if err := rows.Scan(&rc.userID, &rc.jurisdictionID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		recipients = append(recipients, rc)
+	}
+	rows.Close()
+
+	sent := 0
+	for _, rc := range recipients {
+		if exclude != nil && rc.userID == *exclude {
+			continue
+		}
+		note := n
+		note.UserID = rc.userID
+		if note.JurisdictionID == uuid.Nil {
+			note.JurisdictionID = rc.jurisdictionID
+		}
+		if err := s.Create(ctx, &note); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// CanAddressCommittee reports whether a user leads the committee or its parent committee
+// (a position up to auth.LeaderRank), which allows them to message it as an audience
+func (s *Service) CanAddressCommittee(ctx context.Context, userID, committeeID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1
+			FROM committees c
+			JOIN committee_members cm ON cm.committee_id IN (c.id, c.parent_committee_id)
+			JOIN positions p ON cm.position_id = p.id
+			WHERE c.id = $1 AND cm.user_id = $2 AND cm.ended_at IS NULL AND p.rank <= $3
+		)
+	`
+	var ok bool
+	err := s.db.QueryRow(ctx, query, committeeID, userID, auth.LeaderRank).Scan(&ok)
+	return ok, err
+}
+
 func (s *Service) List(ctx context.Context, userID uuid.UUID, limit int) ([]Notification, error) {
 	query := `
 		SELECT id, user_id, type, title, message, data, is_read, created_at, jurisdiction_id
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_jurisdiction_bbox;
+
+ALTER TABLE jurisdictions DROP COLUMN IF EXISTS bbox_max_lng;
+ALTER TABLE jurisdictions DROP COLUMN IF EXISTS bbox_max_lat;
+ALTER TABLE jurisdictions DROP COLUMN IF EXISTS bbox_min_lng;
+ALTER TABLE jurisdictions DROP COLUMN IF EXISTS bbox_min_lat;
+ALTER TABLE jurisdictions DROP COLUMN IF EXISTS centroid_lng;
+ALTER TABLE jurisdictions DROP COLUMN IF EXISTS centroid_lat;
+ALTER TABLE jurisdictions DROP COLUMN IF EXISTS boundary;
@@ -0,0 +1,17 @@
+-- Jurisdiction Geography
+-- Boundaries are stored as raw GeoJSON geometry (Polygon / MultiPolygon, WGS84).
+-- The bounding box columns give a cheap indexed pre-filter for point lookups;
+-- exact point-in-polygon is evaluated by the API.
+ALTER TABLE jurisdictions ADD COLUMN IF NOT EXISTS boundary JSONB;
+ALTER TABLE jurisdictions ADD COLUMN IF NOT EXISTS centroid_lat DOUBLE PRECISION;
+ALTER TABLE jurisdictions ADD COLUMN IF NOT EXISTS centroid_lng DOUBLE PRECISION;
+ALTER TABLE jurisdictions ADD COLUMN IF NOT EXISTS bbox_min_lat DOUBLE PRECISION;
+ALTER TABLE jurisdictions ADD COLUMN IF NOT EXISTS bbox_min_lng DOUBLE PRECISION;
+ALTER TABLE jurisdictions ADD COLUMN IF NOT EXISTS bbox_max_lat DOUBLE PRECISION;
+ALTER TABLE jurisdictions ADD COLUMN IF NOT EXISTS bbox_max_lng DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS idx_jurisdiction_bbox
+    ON jurisdictions(bbox_min_lat, bbox_max_lat, bbox_min_lng, bbox_max_lng)
+    WHERE boundary IS NOT NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN jurisdictions.boundary IS 'GeoJSON Polygon or MultiPolygon geometry (WGS84)';
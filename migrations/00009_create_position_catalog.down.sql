@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS committee_position_quotas;
+DROP TABLE IF EXISTS committee_structures;
@@ -0,0 +1,62 @@
+-- Position Catalog Configuration
+-- Replaces the hard-coded committee size limits and position uniqueness rules
+-- with admin-managed configuration per jurisdiction level and committee type.
+
+-- 1. Committee structures (total size per level and type, NULL = unlimited)
+CREATE TABLE IF NOT EXISTS committee_structures (
+    level_id INTEGER REFERENCES jurisdiction_levels(id) NOT NULL,
+    committee_type committee_type NOT NULL,
+    max_members INTEGER CHECK (max_members IS NULL OR max_members > 0),
+    updated_by UUID REFERENCES users(id),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (level_id, committee_type)
+);
+
+-- 2. Allowed positions and seat quotas (NULL max_seats = unlimited within committee size)
+CREATE TABLE IF NOT EXISTS committee_position_quotas (
+    level_id INTEGER REFERENCES jurisdiction_levels(id) NOT NULL,
+    committee_type committee_type NOT NULL,
+    position_id INTEGER REFERENCES positions(id) ON DELETE CASCADE NOT NULL,
+    max_seats INTEGER CHECK (max_seats IS NULL OR max_seats > 0),
+    updated_by UUID REFERENCES users(id),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (level_id, committee_type, position_id)
+);
+
+CREATE INDEX idx_position_quota_position ON committee_position_quotas(position_id);
+
+-- Seed size limits (PHASE_D_COMMITTEE.md, Rule 3)
+INSERT INTO committee_structures (level_id, committee_type, max_members)
+SELECT jl.id, v.committee_type::committee_type, v.max_members
+FROM (VALUES
+    ('Central', 'full', NULL), ('Central', 'convener', 21),
+    ('Division', 'full', 201), ('Division', 'convener', 15),
+    ('District', 'full', 151), ('District', 'convener', 11),
+    ('Upazila', 'full', 101), ('Upazila', 'convener', 9),
+    ('Municipality', 'full', 101), ('Municipality', 'convener', 9),
+    ('Union', 'full', 71), ('Union', 'convener', 7),
+    ('Ward', 'full', 31), ('Ward', 'convener', 5)
+) AS v(level_name, committee_type, max_members)
+JOIN jurisdiction_levels jl ON jl.name = v.level_name
+ON CONFLICT DO NOTHING;
+
+-- Seed allowed positions from positions.committee_type with single-seat posts
+-- per PHASE_D_COMMITTEE.md "Position Uniqueness Rules"
+INSERT INTO committee_position_quotas (level_id, committee_type, position_id, max_seats)
+SELECT jl.id, ct.committee_type::committee_type, p.id,
+       CASE p.name
+           WHEN 'President' THEN 1
+           WHEN 'General Secretary' THEN 1
+           WHEN 'Convener' THEN 1
+           WHEN 'Member Secretary' THEN 1
+           WHEN 'Senior Vice President' THEN 3
+           WHEN 'Treasurer' THEN 1
+           WHEN 'Organizational Secretary' THEN 1
+           WHEN 'Office Secretary' THEN 1
+           ELSE NULL
+       END
+FROM jurisdiction_levels jl
+CROSS JOIN (VALUES ('full'), ('convener')) AS ct(committee_type)
+JOIN positions p ON p.committee_type = 'Both'
+    OR LOWER(p.committee_type) = ct.committee_type
+ON CONFLICT DO NOTHING;
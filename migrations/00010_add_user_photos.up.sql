@@ -0,0 +1,4 @@
+-- Profile photos used on printed committee rosters
+ALTER TABLE users ADD COLUMN IF NOT EXISTS photo_url TEXT;
+
+COMMENT ON COLUMN users.photo_url IS 'Public or signed URL of the member profile photo';
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS election_ballot_choices;
+DROP TABLE IF EXISTS election_voters;
+DROP TABLE IF EXISTS election_candidates;
+DROP TABLE IF EXISTS election_posts;
+DROP TABLE IF EXISTS elections;
+DROP TYPE IF EXISTS election_voting_mode;
+DROP TYPE IF EXISTS election_status;
+ALTER TABLE positions DROP COLUMN IF EXISTS max_terms;
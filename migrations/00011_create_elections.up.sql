@@ -0,0 +1,70 @@
+-- Committee Elections and Term Limits
+-- Elections fill a committee's posts for the next term. Results are turned into a
+-- proposed committee that goes through the normal approval flow.
+
+-- 1. Term limits per position (NULL = unlimited)
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS max_terms INTEGER CHECK (max_terms IS NULL OR max_terms > 0);
+
+-- 2. Elections
+CREATE TYPE election_status AS ENUM ('draft', 'nominations', 'voting', 'closed', 'certified', 'cancelled');
+CREATE TYPE election_voting_mode AS ENUM ('in_person', 'online', 'hybrid');
+
+CREATE TABLE IF NOT EXISTS elections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    committee_id UUID REFERENCES committees(id) NOT NULL, -- Electorate: active members of this committee
+    jurisdiction_id UUID REFERENCES jurisdictions(id) NOT NULL,
+    committee_type committee_type NOT NULL DEFAULT 'full', -- Type of the committee being elected
+    title VARCHAR(255) NOT NULL,
+    voting_mode election_voting_mode NOT NULL DEFAULT 'hybrid',
+    status election_status NOT NULL DEFAULT 'draft',
+    voting_starts_at TIMESTAMP,
+    voting_ends_at TIMESTAMP,
+    result_committee_id UUID REFERENCES committees(id),
+    created_by UUID REFERENCES users(id) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_election_committee ON elections(committee_id);
+CREATE INDEX idx_election_jurisdiction ON elections(jurisdiction_id);
+
+-- 3. Posts contested in an election
+CREATE TABLE IF NOT EXISTS election_posts (
+    election_id UUID REFERENCES elections(id) ON DELETE CASCADE NOT NULL,
+    position_id INTEGER REFERENCES positions(id) NOT NULL,
+    seats INTEGER NOT NULL DEFAULT 1 CHECK (seats > 0),
+    PRIMARY KEY (election_id, position_id)
+);
+
+-- 4. Candidates
+CREATE TABLE IF NOT EXISTS election_candidates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    election_id UUID REFERENCES elections(id) ON DELETE CASCADE NOT NULL,
+    position_id INTEGER REFERENCES positions(id) NOT NULL,
+    user_id UUID REFERENCES users(id) NOT NULL,
+    withdrawn_at TIMESTAMP,
+    registered_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (election_id, position_id, user_id)
+);
+
+-- 5. Voter turnout: one row per member enforces one vote per member across both channels.
+-- It is deliberately not linked to ballot choices, so online ballots stay secret.
+CREATE TABLE IF NOT EXISTS election_voters (
+    election_id UUID REFERENCES elections(id) ON DELETE CASCADE NOT NULL,
+    user_id UUID REFERENCES users(id) NOT NULL,
+    channel VARCHAR(20) NOT NULL, -- in_person, online
+    recorded_by UUID REFERENCES users(id), -- Polling officer for in-person ballots
+    voted_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (election_id, user_id)
+);
+
+-- 6. Anonymous ballot choices
+CREATE TABLE IF NOT EXISTS election_ballot_choices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    election_id UUID REFERENCES elections(id) ON DELETE CASCADE NOT NULL,
+    position_id INTEGER REFERENCES positions(id) NOT NULL,
+    candidate_id UUID REFERENCES election_candidates(id) NOT NULL
+);
+
+CREATE INDEX idx_ballot_choice_tally ON election_ballot_choices(election_id, position_id, candidate_id);
@@ -0,0 +1,18 @@
+DELETE FROM committee_members WHERE committee_id IN (SELECT id FROM committees WHERE parent_committee_id IS NOT NULL);
+UPDATE activities SET committee_id = NULL WHERE committee_id IN (SELECT id FROM committees WHERE parent_committee_id IS NOT NULL);
+UPDATE tasks SET committee_id = NULL WHERE committee_id IN (SELECT id FROM committees WHERE parent_committee_id IS NOT NULL);
+DELETE FROM committees WHERE parent_committee_id IS NOT NULL;
+DELETE FROM positions WHERE committee_type = 'SubCommittee'
+    AND id NOT IN (SELECT position_id FROM committee_members);
+
+ALTER TABLE committees DROP CONSTRAINT IF EXISTS one_active_committee_per_jurisdiction;
+ALTER TABLE committees ADD CONSTRAINT one_active_committee_per_jurisdiction
+    EXCLUDE USING gist (jurisdiction_id WITH =, status WITH =) WHERE (status = 'active');
+
+DROP INDEX IF EXISTS idx_committee_parent;
+ALTER TABLE committees DROP COLUMN IF EXISTS created_by;
+ALTER TABLE committees DROP COLUMN IF EXISTS mandate;
+ALTER TABLE committees DROP COLUMN IF EXISTS name;
+ALTER TABLE committees DROP COLUMN IF EXISTS parent_committee_id;
+
+-- PostgreSQL cannot drop enum values; 'sub_committee' remains in committee_type but is unused.
@@ -0,0 +1,25 @@
+-- Sub-committees and Working Groups
+-- Ad-hoc cells (e.g. flood relief, election campaign) that belong to a committee rather than
+-- a jurisdiction. They are stored as committees so they can be task assignees and notification
+-- audiences, and inherit the parent committee's jurisdiction.
+
+ALTER TYPE committee_type ADD VALUE IF NOT EXISTS 'sub_committee';
+
+ALTER TABLE committees ADD COLUMN IF NOT EXISTS parent_committee_id UUID REFERENCES committees(id);
+ALTER TABLE committees ADD COLUMN IF NOT EXISTS name VARCHAR(255);
+ALTER TABLE committees ADD COLUMN IF NOT EXISTS mandate TEXT;
+ALTER TABLE committees ADD COLUMN IF NOT EXISTS created_by UUID REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_committee_parent ON committees(parent_committee_id) WHERE parent_committee_id IS NOT NULL;
+
+-- Only the main committee counts towards the one-active-committee-per-jurisdiction rule
+ALTER TABLE committees DROP CONSTRAINT IF EXISTS one_active_committee_per_jurisdiction;
+ALTER TABLE committees ADD CONSTRAINT one_active_committee_per_jurisdiction
+    EXCLUDE USING gist (jurisdiction_id WITH =, status WITH =) WHERE (status = 'active' AND parent_committee_id IS NULL);
+
+-- Lead positions for sub-committees
+INSERT INTO positions (name, name_bn, rank, committee_type) VALUES
+('Coordinator', 'সমন্বয়ক', 1, 'SubCommittee'),
+('Joint Coordinator', 'যুগ্ম সমন্বয়ক', 2, 'SubCommittee'),
+('Cell Member', 'সেল সদস্য', 100, 'SubCommittee')
+ON CONFLICT (name) DO NOTHING;
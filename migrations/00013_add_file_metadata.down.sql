@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_complaint_evidence_hash;
+DROP INDEX IF EXISTS idx_complaint_evidence_complaint;
+DROP INDEX IF EXISTS idx_activity_proofs_hash;
+DROP INDEX IF EXISTS idx_activity_proofs_activity;
+
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS uploaded_by;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS original_name;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS file_size;
+
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS uploaded_by;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS original_name;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE activity_proofs ALTER COLUMN file_size TYPE INTEGER;
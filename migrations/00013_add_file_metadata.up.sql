@@ -0,0 +1,18 @@
+-- Stored file metadata
+-- file_path / evidence_path now hold object-store keys (content-addressed "sha256/ab/<digest>").
+-- The digest is kept separately so duplicates can be found without parsing keys.
+
+ALTER TABLE activity_proofs ALTER COLUMN file_size TYPE BIGINT;
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS original_name TEXT;
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS uploaded_by UUID REFERENCES users(id);
+
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS file_size BIGINT;
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS original_name TEXT;
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS uploaded_by UUID REFERENCES users(id); -- Null for anonymous uploads
+
+CREATE INDEX IF NOT EXISTS idx_activity_proofs_activity ON activity_proofs(activity_id);
+CREATE INDEX IF NOT EXISTS idx_activity_proofs_hash ON activity_proofs(content_hash);
+CREATE INDEX IF NOT EXISTS idx_complaint_evidence_complaint ON complaint_evidence(complaint_id);
+CREATE INDEX IF NOT EXISTS idx_complaint_evidence_hash ON complaint_evidence(content_hash);
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS idx_activity_proofs_processing;
+DROP INDEX IF EXISTS idx_complaint_evidence_processing;
+
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS thumbnail_path;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS original_path;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS processed_at;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS claimed_at;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS processing_attempts;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS processing_error;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS processing_status;
+
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS thumbnail_path;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS original_path;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS processed_at;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS claimed_at;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS processing_attempts;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS processing_error;
+ALTER TABLE complaint_evidence DROP COLUMN IF EXISTS processing_status;
+
+DROP TYPE IF EXISTS media_status;
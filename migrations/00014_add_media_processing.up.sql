@@ -0,0 +1,31 @@
+-- Media Safety Pipeline
+-- Uploaded images are re-encoded in the background to strip EXIF/XMP metadata (GPS, device
+-- serials) and get a thumbnail. file_path points at the sanitized file once processing is done;
+-- original_path keeps the untouched upload, and only for uploaders who are not anonymous.
+
+CREATE TYPE media_status AS ENUM ('pending', 'processing', 'processed', 'skipped', 'rejected', 'failed');
+
+-- Files uploaded before the pipeline existed are left as they are
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS processing_status media_status NOT NULL DEFAULT 'skipped';
+ALTER TABLE complaint_evidence ALTER COLUMN processing_status SET DEFAULT 'pending';
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS processing_error TEXT;
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS processing_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS processed_at TIMESTAMP;
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS original_path TEXT;
+ALTER TABLE complaint_evidence ADD COLUMN IF NOT EXISTS thumbnail_path TEXT;
+
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS processing_status media_status NOT NULL DEFAULT 'skipped';
+ALTER TABLE activity_proofs ALTER COLUMN processing_status SET DEFAULT 'pending';
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS processing_error TEXT;
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS processing_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS processed_at TIMESTAMP;
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS original_path TEXT;
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS thumbnail_path TEXT;
+
+-- Work queue lookups
+CREATE INDEX IF NOT EXISTS idx_complaint_evidence_processing ON complaint_evidence(created_at)
+    WHERE processing_status IN ('pending', 'processing');
+CREATE INDEX IF NOT EXISTS idx_activity_proofs_processing ON activity_proofs(created_at)
+    WHERE processing_status IN ('pending', 'processing');
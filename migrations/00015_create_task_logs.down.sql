@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_activity_proofs_task;
+ALTER TABLE activity_proofs DROP CONSTRAINT IF EXISTS activity_proofs_owner_check;
+DELETE FROM activity_proofs WHERE activity_id IS NULL;
+ALTER TABLE activity_proofs DROP COLUMN IF EXISTS task_id;
+
+DROP TABLE IF EXISTS task_logs;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS completion_note;
+ALTER TABLE tasks DROP COLUMN IF EXISTS requires_proof;
+ALTER TABLE tasks DROP COLUMN IF EXISTS requires_completion_note;
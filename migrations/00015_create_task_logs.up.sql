@@ -0,0 +1,27 @@
+-- Task Workflow
+-- Status changes follow a fixed transition graph (enforced in the API) and are recorded in
+-- task_logs. Tasks can require a completion note and/or proof files before completion.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS requires_completion_note BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS requires_proof BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completion_note TEXT;
+
+-- Task History / Audit Trail
+CREATE TABLE IF NOT EXISTS task_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID REFERENCES tasks(id) ON DELETE CASCADE NOT NULL,
+    user_id UUID REFERENCES users(id), -- Null for system
+    action VARCHAR(50) NOT NULL, -- 'created', 'status_change', 'proof_added'
+    old_status task_status,
+    new_status task_status,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_logs_task ON task_logs(task_id, created_at);
+
+-- Proof files can belong to a task as well as an activity
+ALTER TABLE activity_proofs ADD COLUMN IF NOT EXISTS task_id UUID REFERENCES tasks(id) ON DELETE CASCADE;
+ALTER TABLE activity_proofs ADD CONSTRAINT activity_proofs_owner_check
+    CHECK (activity_id IS NOT NULL OR task_id IS NOT NULL);
+CREATE INDEX IF NOT EXISTS idx_activity_proofs_task ON activity_proofs(task_id);
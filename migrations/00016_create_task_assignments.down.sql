@@ -0,0 +1,3 @@
+ALTER TABLE task_logs DROP COLUMN IF EXISTS assignment_user_id;
+
+DROP TABLE IF EXISTS task_assignments;
@@ -0,0 +1,21 @@
+-- Committee Task Assignments
+-- A task given to a whole committee is fanned out into one assignment per active member, so
+-- each member's progress is tracked separately. The task completes once every member has.
+
+CREATE TABLE IF NOT EXISTS task_assignments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID REFERENCES tasks(id) ON DELETE CASCADE NOT NULL,
+    user_id UUID REFERENCES users(id) NOT NULL,
+    status task_status NOT NULL DEFAULT 'pending', -- pending, in_progress, completed
+    note TEXT,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (task_id, user_id),
+    CONSTRAINT task_assignments_status_check CHECK (status IN ('pending', 'in_progress', 'completed'))
+);
+
+CREATE INDEX idx_task_assignments_user ON task_assignments(user_id, status);
+
+-- History entries about one member's assignment name that member
+ALTER TABLE task_logs ADD COLUMN IF NOT EXISTS assignment_user_id UUID REFERENCES users(id);
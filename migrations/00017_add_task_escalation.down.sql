@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tasks_open_due;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS escalated_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS escalation_level;
+ALTER TABLE tasks DROP COLUMN IF EXISTS overdue_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS reminded_at;
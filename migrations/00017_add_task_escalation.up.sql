@@ -0,0 +1,13 @@
+-- Overdue Task Escalation
+-- A background job reminds assignees before the due date, marks overdue tasks and escalates them
+-- to the committee leadership (level 1) and then the parent jurisdiction's leadership (level 2).
+-- Each step is recorded in task_logs.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS overdue_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS escalation_level SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
+
+-- Open tasks with a due date, scanned by the escalation job and the overdue report
+CREATE INDEX IF NOT EXISTS idx_tasks_open_due ON tasks(due_date)
+    WHERE status IN ('pending', 'in_progress') AND due_date IS NOT NULL AND deleted_at IS NULL;
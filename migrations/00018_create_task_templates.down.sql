@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tasks_template_occurrence;
+ALTER TABLE tasks DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS task_templates;
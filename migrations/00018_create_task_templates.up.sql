@@ -0,0 +1,37 @@
+-- Recurring Tasks
+-- A template describes a recurring duty (e.g. "every upazila committee submits a monthly report
+-- by the 5th"). Its recurrence rule (RFC 5545 RRULE subset) gives the due dates; lead_days before
+-- each one, a scheduler creates a concrete task for every jurisdiction at the template's level
+-- under its root jurisdiction.
+
+CREATE TABLE IF NOT EXISTS task_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    priority INTEGER NOT NULL DEFAULT 3, -- Same scale as tasks.priority
+    requires_completion_note BOOLEAN NOT NULL DEFAULT FALSE,
+    requires_proof BOOLEAN NOT NULL DEFAULT FALSE,
+    jurisdiction_id UUID REFERENCES jurisdictions(id) NOT NULL, -- Root of the subtree it applies to
+    level_id INTEGER REFERENCES jurisdiction_levels(id) NOT NULL, -- Level that receives the tasks
+    assign_to VARCHAR(20) NOT NULL DEFAULT 'leader', -- 'leader' (committee head) or 'committee' (every member)
+    rrule TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL, -- DTSTART; also sets the time of day of each due date
+    lead_days INTEGER NOT NULL DEFAULT 7, -- Tasks are created this many days before they are due
+    is_paused BOOLEAN NOT NULL DEFAULT FALSE,
+    next_due_at TIMESTAMP, -- Next occurrence not yet generated; NULL when the rule has ended
+    last_generated_at TIMESTAMP,
+    created_by UUID REFERENCES users(id) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+    CONSTRAINT task_templates_assign_to_check CHECK (assign_to IN ('leader', 'committee')),
+    CONSTRAINT task_templates_lead_days_check CHECK (lead_days BETWEEN 0 AND 365)
+);
+
+CREATE INDEX idx_task_templates_next ON task_templates(next_due_at)
+    WHERE is_paused = FALSE AND deleted_at IS NULL AND next_due_at IS NOT NULL;
+
+-- Generated tasks point back at their template; one task per jurisdiction per occurrence
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS template_id UUID REFERENCES task_templates(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_template_occurrence
+    ON tasks(template_id, jurisdiction_id, due_date) WHERE template_id IS NOT NULL;
@@ -0,0 +1,34 @@
+-- Comments
+-- One discussion table shared by tasks, activities, complaints and join requests. Replies point
+-- at their parent comment. Internal comments are only shown to officials handling the record and
+-- are allowed on complaints and join requests, where the other party is not a committee member.
+
+CREATE TABLE IF NOT EXISTS comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type VARCHAR(20) NOT NULL, -- 'task', 'activity', 'complaint', 'join_request'
+    entity_id UUID NOT NULL,
+    parent_id UUID REFERENCES comments(id),
+    author_id UUID REFERENCES users(id) NOT NULL,
+    body TEXT NOT NULL,
+    visibility VARCHAR(10) NOT NULL DEFAULT 'public', -- 'public' or 'internal'
+    edited_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+    CONSTRAINT comments_entity_type_check CHECK (entity_type IN ('task', 'activity', 'complaint', 'join_request')),
+    CONSTRAINT comments_visibility_check CHECK (visibility IN ('public', 'internal')),
+    CONSTRAINT comments_internal_check CHECK (visibility = 'public' OR entity_type IN ('complaint', 'join_request'))
+);
+
+CREATE INDEX idx_comments_entity ON comments(entity_type, entity_id, created_at);
+CREATE INDEX idx_comments_parent ON comments(parent_id) WHERE parent_id IS NOT NULL;
+
+-- Users @mentioned in a comment; kept so an edit only notifies newly mentioned users
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX idx_comment_mentions_user ON comment_mentions(user_id);
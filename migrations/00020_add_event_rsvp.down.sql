@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS event_invitations;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_capacity_check;
+ALTER TABLE events DROP COLUMN IF EXISTS rsvp_deadline;
+ALTER TABLE events DROP COLUMN IF EXISTS capacity;
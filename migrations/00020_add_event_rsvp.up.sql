@@ -0,0 +1,28 @@
+-- Event RSVPs
+-- Organisers invite members by jurisdiction subtree, committee or position. Invitees answer
+-- yes/no/maybe; when an event has a capacity, "yes" answers beyond it join a waitlist and are
+-- promoted in order as seats free up. Turnout is compared against event_attendance.
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS capacity INTEGER; -- NULL = unlimited
+ALTER TABLE events ADD COLUMN IF NOT EXISTS rsvp_deadline TIMESTAMP; -- Defaults to start_time
+ALTER TABLE events ADD CONSTRAINT events_capacity_check CHECK (capacity IS NULL OR capacity > 0);
+
+CREATE TABLE IF NOT EXISTS event_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE NOT NULL,
+    user_id UUID REFERENCES users(id) NOT NULL,
+    rsvp VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'yes', 'no', 'maybe', 'waitlisted'
+    invited_by UUID REFERENCES users(id), -- NULL when the member registered for a public event themselves
+    invited_at TIMESTAMP,
+    responded_at TIMESTAMP,
+    waitlisted_at TIMESTAMP, -- Queue order for promotion
+    reminded_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(event_id, user_id),
+    CONSTRAINT event_invitations_rsvp_check CHECK (rsvp IN ('pending', 'yes', 'no', 'maybe', 'waitlisted'))
+);
+
+CREATE INDEX idx_event_invitations_user ON event_invitations(user_id);
+CREATE INDEX idx_event_invitations_waitlist ON event_invitations(event_id, waitlisted_at) WHERE rsvp = 'waitlisted';
+CREATE INDEX idx_event_invitations_pending ON event_invitations(event_id) WHERE rsvp = 'pending' AND reminded_at IS NULL;
@@ -0,0 +1,13 @@
+ALTER TABLE event_attendance DROP CONSTRAINT IF EXISTS event_attendance_method_check;
+ALTER TABLE event_attendance DROP COLUMN IF EXISTS synced_at;
+ALTER TABLE event_attendance DROP COLUMN IF EXISTS distance_m;
+ALTER TABLE event_attendance DROP COLUMN IF EXISTS longitude;
+ALTER TABLE event_attendance DROP COLUMN IF EXISTS latitude;
+ALTER TABLE event_attendance DROP COLUMN IF EXISTS recorded_by;
+ALTER TABLE event_attendance DROP COLUMN IF EXISTS method;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_geofence_check;
+ALTER TABLE events DROP COLUMN IF EXISTS enforce_time_window;
+ALTER TABLE events DROP COLUMN IF EXISTS geofence_radius_m;
+ALTER TABLE events DROP COLUMN IF EXISTS longitude;
+ALTER TABLE events DROP COLUMN IF EXISTS latitude;
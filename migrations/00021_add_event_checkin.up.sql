@@ -0,0 +1,19 @@
+-- Event Check-in
+-- Members check themselves in by scanning a rotating QR code shown by the organizer. Check-ins
+-- can be limited to a radius around the venue and to the event's time window. Organizers may
+-- also collect check-ins offline and upload them in bulk later.
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS geofence_radius_m INTEGER; -- NULL = no geofence
+ALTER TABLE events ADD COLUMN IF NOT EXISTS enforce_time_window BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE events ADD CONSTRAINT events_geofence_check
+    CHECK (geofence_radius_m IS NULL OR (geofence_radius_m > 0 AND latitude IS NOT NULL AND longitude IS NOT NULL));
+
+ALTER TABLE event_attendance ADD COLUMN IF NOT EXISTS method VARCHAR(20) NOT NULL DEFAULT 'manual'; -- 'manual', 'qr', 'offline'
+ALTER TABLE event_attendance ADD COLUMN IF NOT EXISTS recorded_by UUID REFERENCES users(id); -- Organizer for manual/offline entries
+ALTER TABLE event_attendance ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE event_attendance ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+ALTER TABLE event_attendance ADD COLUMN IF NOT EXISTS distance_m INTEGER; -- From the venue, when a geofence applied
+ALTER TABLE event_attendance ADD COLUMN IF NOT EXISTS synced_at TIMESTAMP; -- Upload time of offline entries
+ALTER TABLE event_attendance ADD CONSTRAINT event_attendance_method_check CHECK (method IN ('manual', 'qr', 'offline'));
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS calendar_feeds;
+
+DROP TRIGGER IF EXISTS trg_tasks_sequence ON tasks;
+DROP FUNCTION IF EXISTS bump_task_sequence();
+DROP TRIGGER IF EXISTS trg_events_sequence ON events;
+DROP FUNCTION IF EXISTS bump_event_sequence();
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS sequence;
+ALTER TABLE events DROP COLUMN IF EXISTS sequence;
@@ -0,0 +1,57 @@
+-- Calendar Feeds
+-- Members subscribe their calendar apps to iCalendar feeds of events and, optionally, task due
+-- dates. A feed is reached through a secret token in its URL; only the token's hash is stored
+-- and a feed can be revoked at any time. Public events are also published per jurisdiction
+-- without a token.
+
+-- Calendar clients apply an update only when its SEQUENCE is higher, so it is bumped whenever
+-- a field shown in the calendar changes, including cancellation
+ALTER TABLE events ADD COLUMN IF NOT EXISTS sequence INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sequence INTEGER NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION bump_event_sequence()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.title, NEW.description, NEW.location, NEW.start_time, NEW.end_time, NEW.deleted_at IS NULL)
+       IS DISTINCT FROM (OLD.title, OLD.description, OLD.location, OLD.start_time, OLD.end_time, OLD.deleted_at IS NULL) THEN
+        NEW.sequence := OLD.sequence + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_events_sequence
+BEFORE UPDATE ON events
+FOR EACH ROW EXECUTE FUNCTION bump_event_sequence();
+
+CREATE OR REPLACE FUNCTION bump_task_sequence()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.title, NEW.description, NEW.due_date, NEW.status = 'cancelled', NEW.deleted_at IS NULL)
+       IS DISTINCT FROM (OLD.title, OLD.description, OLD.due_date, OLD.status = 'cancelled', OLD.deleted_at IS NULL) THEN
+        NEW.sequence := OLD.sequence + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_tasks_sequence
+BEFORE UPDATE ON tasks
+FOR EACH ROW EXECUTE FUNCTION bump_task_sequence();
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) NOT NULL,
+    name VARCHAR(100),
+    scope VARCHAR(20) NOT NULL, -- 'user' (own invitations and tasks) or 'jurisdiction'
+    jurisdiction_id UUID REFERENCES jurisdictions(id),
+    include_tasks BOOLEAN NOT NULL DEFAULT FALSE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA256 of the URL token
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    revoked_at TIMESTAMP,
+    CONSTRAINT calendar_feeds_scope_check CHECK (scope IN ('user', 'jurisdiction')),
+    CONSTRAINT calendar_feeds_jurisdiction_check CHECK (scope = 'user' OR jurisdiction_id IS NOT NULL)
+);
+
+CREATE INDEX idx_calendar_feeds_user ON calendar_feeds(user_id) WHERE revoked_at IS NULL;
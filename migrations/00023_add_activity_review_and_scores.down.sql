@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS jurisdiction_monthly_scores;
+DROP TABLE IF EXISTS member_monthly_scores;
+DROP TABLE IF EXISTS activity_score_weights;
+DROP TABLE IF EXISTS activity_reviews;
+
+DROP INDEX IF EXISTS idx_activity_review_queue;
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_review_status_check;
+ALTER TABLE activities DROP COLUMN IF EXISTS review_note;
+ALTER TABLE activities DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE activities DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE activities DROP COLUMN IF EXISTS review_status;
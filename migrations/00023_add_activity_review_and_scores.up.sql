@@ -0,0 +1,68 @@
+-- Activity Review and Scoring
+-- Self-reported activities are reviewed by a committee above the member's jurisdiction.
+-- Only verified activities earn points: a weight per category plus a bonus per proof file,
+-- capped. Scores are materialised per month for member and jurisdiction leaderboards; a
+-- jurisdiction's total includes everything logged below it.
+
+ALTER TABLE activities ADD COLUMN IF NOT EXISTS review_status VARCHAR(20) NOT NULL DEFAULT 'submitted';
+ALTER TABLE activities ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id);
+ALTER TABLE activities ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+ALTER TABLE activities ADD COLUMN IF NOT EXISTS review_note TEXT;
+ALTER TABLE activities ADD CONSTRAINT activities_review_status_check CHECK (review_status IN ('submitted', 'verified', 'disputed'));
+
+CREATE INDEX idx_activity_review_queue ON activities(jurisdiction_id, created_at) WHERE review_status = 'submitted' AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS activity_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    activity_id UUID REFERENCES activities(id) ON DELETE CASCADE NOT NULL,
+    reviewer_id UUID REFERENCES users(id) NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_activity_reviews_activity ON activity_reviews(activity_id, created_at);
+
+CREATE TABLE IF NOT EXISTS activity_score_weights (
+    category activity_category PRIMARY KEY,
+    points NUMERIC(8,2) NOT NULL,
+    proof_bonus NUMERIC(8,2) NOT NULL DEFAULT 0, -- Per proof file
+    max_proof_bonus NUMERIC(8,2) NOT NULL DEFAULT 0,
+    updated_by UUID REFERENCES users(id),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT activity_score_weights_check CHECK (points >= 0 AND proof_bonus >= 0 AND max_proof_bonus >= 0)
+);
+
+INSERT INTO activity_score_weights (category, points, proof_bonus, max_proof_bonus) VALUES
+('protest', 15, 2, 6),
+('political', 10, 2, 6),
+('social', 8, 2, 6),
+('organizational', 5, 1, 3),
+('other', 2, 1, 2);
+
+CREATE TABLE IF NOT EXISTS member_monthly_scores (
+    month DATE NOT NULL, -- First day of the month
+    user_id UUID REFERENCES users(id) NOT NULL,
+    jurisdiction_id UUID REFERENCES jurisdictions(id), -- Member's jurisdiction when computed
+    activity_count INTEGER NOT NULL,
+    proof_count INTEGER NOT NULL,
+    score NUMERIC(12,2) NOT NULL,
+    computed_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (month, user_id)
+);
+
+CREATE INDEX idx_member_monthly_scores_rank ON member_monthly_scores(month, score DESC);
+
+CREATE TABLE IF NOT EXISTS jurisdiction_monthly_scores (
+    month DATE NOT NULL,
+    jurisdiction_id UUID REFERENCES jurisdictions(id) NOT NULL,
+    own_score NUMERIC(12,2) NOT NULL,   -- Activities logged in the jurisdiction itself
+    total_score NUMERIC(12,2) NOT NULL, -- Including all jurisdictions below it
+    activity_count INTEGER NOT NULL,    -- Including all jurisdictions below it
+    member_count INTEGER NOT NULL,      -- Distinct contributing members, including below
+    computed_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (month, jurisdiction_id)
+);
+
+CREATE INDEX idx_jurisdiction_monthly_scores_rank ON jurisdiction_monthly_scores(month, total_score DESC);
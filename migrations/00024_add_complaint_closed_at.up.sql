@@ -0,0 +1,10 @@
+-- Complaint resolution
+-- closed_at starts the window in which a closed complaint can still be reopened
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+
+UPDATE complaints c
+SET closed_at = COALESCE(
+    (SELECT MAX(l.created_at) FROM complaint_logs l WHERE l.complaint_id = c.id AND l.new_status = 'closed'),
+    c.updated_at
+)
+WHERE c.status = 'closed' AND c.closed_at IS NULL;
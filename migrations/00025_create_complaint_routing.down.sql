@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS complaint_routing_rules;
+
+DROP INDEX IF EXISTS idx_complaint_assignee;
+ALTER TABLE complaints DROP COLUMN IF EXISTS assigned_at;
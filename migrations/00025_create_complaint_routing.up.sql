@@ -0,0 +1,20 @@
+-- Complaint assignment
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP;
+
+UPDATE complaints SET assigned_at = updated_at WHERE assigned_to_id IS NOT NULL AND assigned_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_complaint_assignee ON complaints(assigned_to_id) WHERE assigned_to_id IS NOT NULL AND deleted_at IS NULL;
+
+-- Auto-routing of new complaints, one rule per jurisdiction. Jurisdictions without a rule are
+-- routed manually: their committee leaders are alerted and assign the complaint themselves.
+CREATE TABLE IF NOT EXISTS complaint_routing_rules (
+    jurisdiction_id UUID PRIMARY KEY REFERENCES jurisdictions(id),
+    mode VARCHAR(20) NOT NULL DEFAULT 'manual',
+    officer_id UUID REFERENCES users(id),                -- Designated complaint officer
+    position_ids INTEGER[] NOT NULL DEFAULT '{}',        -- Round-robin pool, and fallback when the officer is unavailable
+    last_assigned_id UUID REFERENCES users(id),          -- Round-robin cursor
+    updated_by UUID REFERENCES users(id),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT complaint_routing_mode_check CHECK (mode IN ('manual', 'officer', 'round_robin')),
+    CONSTRAINT complaint_routing_officer_check CHECK (mode <> 'officer' OR officer_id IS NOT NULL)
+);
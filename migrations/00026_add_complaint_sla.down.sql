@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS complaint_sla_breaches;
+
+DROP INDEX IF EXISTS idx_complaint_open;
+DROP INDEX IF EXISTS idx_complaint_escalated_to;
+ALTER TABLE complaints DROP COLUMN IF EXISTS escalated_at;
+ALTER TABLE complaints DROP COLUMN IF EXISTS escalated_to_id;
+ALTER TABLE complaints DROP COLUMN IF EXISTS escalation_level;
+ALTER TABLE complaints DROP COLUMN IF EXISTS status_changed_at;
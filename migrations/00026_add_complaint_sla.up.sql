@@ -0,0 +1,37 @@
+-- Complaint SLA tracking
+-- status_changed_at starts the clock for the current status's SLA
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP;
+
+UPDATE complaints c
+SET status_changed_at = COALESCE(
+    (SELECT MAX(l.created_at) FROM complaint_logs l WHERE l.complaint_id = c.id AND l.new_status IS DISTINCT FROM l.old_status),
+    c.created_at
+)
+WHERE c.status_changed_at IS NULL;
+
+ALTER TABLE complaints ALTER COLUMN status_changed_at SET DEFAULT NOW();
+ALTER TABLE complaints ALTER COLUMN status_changed_at SET NOT NULL;
+
+-- Escalation moves the complaint into an ancestor's queue; jurisdiction_id stays the original
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS escalation_level INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS escalated_to_id UUID REFERENCES jurisdictions(id);
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_complaint_escalated_to ON complaints(escalated_to_id) WHERE escalated_to_id IS NOT NULL AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_complaint_open ON complaints(status_changed_at) WHERE status IN ('received', 'under_review', 'action_taken') AND deleted_at IS NULL;
+
+-- One row per missed deadline. started_at identifies the clock that ran out, so a reopened
+-- complaint can breach the same SLA again.
+CREATE TABLE IF NOT EXISTS complaint_sla_breaches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    complaint_id UUID NOT NULL REFERENCES complaints(id) ON DELETE CASCADE,
+    jurisdiction_id UUID NOT NULL REFERENCES jurisdictions(id),    -- Queue that missed the deadline
+    sla VARCHAR(20) NOT NULL,                                      -- Status name, or 'resolution'
+    started_at TIMESTAMP NOT NULL,
+    due_at TIMESTAMP NOT NULL,
+    escalated_to_id UUID REFERENCES jurisdictions(id),             -- NULL when there was no parent
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (complaint_id, sla, started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sla_breach_jurisdiction ON complaint_sla_breaches(jurisdiction_id, created_at);
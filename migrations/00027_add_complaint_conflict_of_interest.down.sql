@@ -0,0 +1,3 @@
+ALTER TABLE complaints DROP COLUMN IF EXISTS conflict_of_interest;
+
+DROP TABLE IF EXISTS complaint_accused;
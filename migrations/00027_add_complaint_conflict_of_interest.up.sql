@@ -0,0 +1,16 @@
+-- Conflict of interest: members a complaint is about
+CREATE TABLE IF NOT EXISTS complaint_accused (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    complaint_id UUID NOT NULL REFERENCES complaints(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id),            -- Matched member, if any
+    position_id INTEGER REFERENCES positions(id), -- Position named or held in the target committee
+    name VARCHAR(255),                            -- As given by the complainant
+    in_committee BOOLEAN NOT NULL DEFAULT FALSE,  -- Held a position in the target jurisdiction's committee when filed
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_complaint_accused_complaint ON complaint_accused(complaint_id);
+CREATE INDEX IF NOT EXISTS idx_complaint_accused_user ON complaint_accused(user_id) WHERE user_id IS NOT NULL;
+
+-- Complaints about the target committee itself are handled by the parent jurisdiction
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS conflict_of_interest BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,5 @@
+DELETE FROM comments WHERE author_id IS NULL;
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_author_check;
+ALTER TABLE comments ALTER COLUMN author_id SET NOT NULL;
+
+ALTER TABLE complaints DROP COLUMN IF EXISTS access_secret_hash;
@@ -0,0 +1,9 @@
+-- Anonymous complaint conversation
+-- Submission issues a secret alongside the tracking ID; only its hash is stored. Holding both lets
+-- the complainant read the public replies on the complaint's comment thread, answer them and add
+-- evidence. The tracking ID alone only reveals the status.
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS access_secret_hash VARCHAR(64); -- SHA256 of the secret
+
+-- Messages from the complainant have no author account
+ALTER TABLE comments ALTER COLUMN author_id DROP NOT NULL;
+ALTER TABLE comments ADD CONSTRAINT comments_author_check CHECK (author_id IS NOT NULL OR entity_type = 'complaint');
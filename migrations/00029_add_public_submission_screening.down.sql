@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_join_requests_flagged;
+DROP INDEX IF EXISTS idx_complaints_flagged;
+
+ALTER TABLE join_requests DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE join_requests DROP COLUMN IF EXISTS moderated_by_id;
+ALTER TABLE join_requests DROP COLUMN IF EXISTS moderation_reason;
+ALTER TABLE join_requests DROP COLUMN IF EXISTS moderation_status;
+ALTER TABLE join_requests DROP COLUMN IF EXISTS ip_fingerprint;
+
+ALTER TABLE complaints DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE complaints DROP COLUMN IF EXISTS moderated_by_id;
+ALTER TABLE complaints DROP COLUMN IF EXISTS moderation_reason;
+ALTER TABLE complaints DROP COLUMN IF EXISTS moderation_status;
+
+DROP TABLE IF EXISTS pow_challenges_used;
+DROP TABLE IF EXISTS public_submissions;
@@ -0,0 +1,38 @@
+-- Screening of public complaint and join submissions
+-- Senders are identified by a keyed fingerprint of their IP that changes every rotation period,
+-- so it can neither be reversed nor link submissions across periods. Quotas count submissions
+-- per fingerprint; suspicious ones wait in a moderation queue instead of reaching the committee.
+
+-- Submissions per fingerprint, for quotas; pruned once their period is over
+CREATE TABLE IF NOT EXISTS public_submissions (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL, -- 'complaint', 'join_request'
+    fingerprint VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_public_submissions_fingerprint ON public_submissions(kind, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_public_submissions_created ON public_submissions(created_at);
+
+-- Solved proof-of-work challenges, so a solution is only accepted once
+CREATE TABLE IF NOT EXISTS pow_challenges_used (
+    challenge_hash VARCHAR(64) PRIMARY KEY, -- SHA256 of the challenge
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- The old hashes were unsalted SHA-256 of the address and could be reversed
+UPDATE complaints SET anonymous_ip_hash = NULL WHERE anonymous_ip_hash IS NOT NULL;
+
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(10) NOT NULL DEFAULT 'clear'; -- 'clear', 'flagged', 'spam'
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS moderation_reason VARCHAR(50);
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS moderated_by_id UUID REFERENCES users(id);
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+ALTER TABLE join_requests ADD COLUMN IF NOT EXISTS ip_fingerprint VARCHAR(64);
+ALTER TABLE join_requests ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(10) NOT NULL DEFAULT 'clear';
+ALTER TABLE join_requests ADD COLUMN IF NOT EXISTS moderation_reason VARCHAR(50);
+ALTER TABLE join_requests ADD COLUMN IF NOT EXISTS moderated_by_id UUID REFERENCES users(id);
+ALTER TABLE join_requests ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_complaints_flagged ON complaints(jurisdiction_id) WHERE moderation_status = 'flagged';
+CREATE INDEX IF NOT EXISTS idx_join_requests_flagged ON join_requests(jurisdiction_id) WHERE moderation_status = 'flagged';
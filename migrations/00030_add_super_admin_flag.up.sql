@@ -0,0 +1,8 @@
+-- Super Admin is an account flag rather than a committee position: position IDs are catalog
+-- entries, and a President at any level holds the same one
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_super_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The seeded administrator account
+UPDATE users SET is_super_admin = TRUE WHERE phone = '+8801700000000' AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_super_admin ON users(id) WHERE is_super_admin = TRUE;
@@ -0,0 +1,157 @@
+// Package ical writes iCalendar (RFC 5545) feeds for calendar subscriptions
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	timeFormat  = "20060102T150405Z"
+	maxLineSize = 75 // Octets per line before folding
+)
+
+// Calendar is a published feed
+type Calendar struct {
+	ProdID  string
+	Name    string
+	Refresh time.Duration // Suggested polling interval; zero leaves it to the client
+	Events  []*Event
+}
+
+// Event is a VEVENT. Clients match updates by UID and apply the one with the highest Sequence.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         *time.Time // Nil for a point in time, e.g. a deadline
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	Updated     time.Time
+}
+
+// Encode writes the calendar
+func (c *Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+	now := time.Now()
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + c.ProdID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.Refresh > 0 {
+		lw.line("REFRESH-INTERVAL;VALUE=DURATION:" + formatDuration(c.Refresh))
+		lw.line("X-PUBLISHED-TTL:" + formatDuration(c.Refresh))
+	}
+
+	for _, e := range c.Events {
+		stamp := e.Updated
+		if stamp.IsZero() {
+			stamp = now
+		}
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + e.UID)
+		lw.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+		lw.line("DTSTAMP:" + formatTime(stamp))
+		lw.line("LAST-MODIFIED:" + formatTime(stamp))
+		lw.line("DTSTART:" + formatTime(e.Start))
+		if e.End != nil && e.End.After(e.Start) {
+			lw.line("DTEND:" + formatTime(*e.End))
+		}
+		lw.line("SUMMARY:" + escapeText(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if e.Location != "" {
+			lw.line("LOCATION:" + escapeText(e.Location))
+		}
+		if e.Status != "" {
+			lw.line("STATUS:" + e.Status)
+		}
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+// lineWriter writes content lines with CRLF endings, folding long lines
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	for first := true; ; first = false {
+		limit := maxLineSize
+		if !first {
+			limit-- // Continuation lines start with a space
+			lw.w.WriteByte(' ')
+		}
+		if len(s) <= limit {
+			lw.w.WriteString(s)
+			break
+		}
+		// Never split a UTF-8 sequence
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		lw.w.WriteString(s[:cut])
+		lw.w.WriteString("\r\n")
+		s = s[cut:]
+	}
+	_, lw.err = lw.w.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// formatDuration renders a duration as an RFC 5545 dur-value, e.g. PT1H30M
+func formatDuration(d time.Duration) string {
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := int(d.Hours()); h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= time.Duration(h) * time.Hour
+	}
+	if m := int(d.Minutes()); m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= time.Duration(m) * time.Minute
+	}
+	if s := int(d.Seconds()); s > 0 || b.Len() == 2 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
@@ -0,0 +1,204 @@
+// Package media sanitizes uploaded images before they are shown to anyone: metadata (EXIF GPS,
+// camera serials, XMP) is removed by re-encoding, and a small JPEG thumbnail is produced for
+// dashboards. Only the standard library codecs are used.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// Processing limits
+const (
+	MaxPixels        = 40_000_000 // Rejects decompression bombs before decoding
+	ThumbnailSize    = 320        // Longest edge, in pixels
+	jpegQuality      = 90
+	thumbnailQuality = 80
+)
+
+var (
+	// ErrUnsupported means the type cannot be sanitized here (e.g. PDF, video); the file is kept as uploaded
+	ErrUnsupported = errors.New("media type is not processed")
+	// ErrInvalid means the content is corrupt or is not the type it claims to be
+	ErrInvalid = errors.New("file content does not match its type")
+)
+
+// Result is a sanitized file
+type Result struct {
+	Data        []byte
+	ContentType string
+	Thumbnail   []byte // JPEG; nil when no thumbnail could be produced
+	Width       int
+	Height      int
+}
+
+// formats maps MIME types to the format names registered with the image package
+var formats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// Process strips metadata from an image of the given (sniffed) content type
+func Process(data []byte, contentType string) (*Result, error) {
+	if contentType == "image/webp" {
+		clean, err := StripWebP(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Data: clean, ContentType: contentType}, nil
+	}
+
+	want, ok := formats[contentType]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	// 1. Check the header agrees with the claimed type and the size is sane
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if format != want {
+		return nil, fmt.Errorf("%w: claimed %s, content is %s", ErrInvalid, contentType, format)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: image dimensions %dx%d are not allowed", ErrInvalid, cfg.Width, cfg.Height)
+	}
+
+	// 2. Decode fully and re-encode; the encoders write pixel data only
+	var out bytes.Buffer
+	var img image.Image
+	switch format {
+	case "gif":
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		if err := gif.EncodeAll(&out, g); err != nil {
+			return nil, err
+		}
+		img = g.Image[0]
+	case "png":
+		img, err = png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		if err := png.Encode(&out, img); err != nil {
+			return nil, err
+		}
+	case "jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		// Orientation lives in EXIF, so bake it into the pixels before EXIF is dropped
+		img = applyOrientation(img, jpegOrientation(data))
+		if err := jpeg.Encode(&out, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Thumbnail
+	thumb, err := Thumbnail(img, ThumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Frames of an animated GIF may be smaller than the canvas, so report the canvas size
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if format == "gif" {
+		width, height = cfg.Width, cfg.Height
+	}
+	return &Result{
+		Data:        out.Bytes(),
+		ContentType: contentType,
+		Thumbnail:   thumb,
+		Width:       width,
+		Height:      height,
+	}, nil
+}
+
+// Thumbnail scales img to fit within max×max (never upscaling) and encodes it as JPEG
+// on a white background.
+func Thumbnail(img image.Image, max int) ([]byte, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > max || h > max {
+		if w >= h {
+			tw, th = max, h*max/w
+		} else {
+			tw, th = w*max/h, max
+		}
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), boxScale(src, tw, th), image.Point{}, draw.Over)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// boxScale downsamples by averaging the source pixels covered by each destination pixel
+func boxScale(src *image.NRGBA, tw, th int) *image.NRGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, tw, th))
+
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := ty*h/th, (ty+1)*h/th
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for tx := 0; tx < tw; tx++ {
+			x0, x1 := tx*w/tw, (tx+1)*w/tw
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			// Average premultiplied so transparent pixels do not darken edges
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					pa := uint64(p[3])
+					r += uint64(p[0]) * pa
+					g += uint64(p[1]) * pa
+					bl += uint64(p[2]) * pa
+					a += pa
+					n++
+				}
+			}
+
+			i := ty*dst.Stride + tx*4
+			if a > 0 {
+				dst.Pix[i] = uint8(r / a)
+				dst.Pix[i+1] = uint8(g / a)
+				dst.Pix[i+2] = uint8(bl / a)
+			}
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
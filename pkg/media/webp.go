@@ -0,0 +1,68 @@
+package media
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// VP8X feature flags
+const (
+	webpFlagXMP  = 0x04
+	webpFlagEXIF = 0x08
+)
+
+// StripWebP removes EXIF and XMP chunks from a WebP file. The standard library has no WebP
+// codec, so the container is rewritten instead of re-encoding the image.
+func StripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("%w: not a WebP file", ErrInvalid)
+	}
+	size := int(binary.LittleEndian.Uint32(data[4:]))
+	if size+8 > len(data) || size < 4 {
+		return nil, fmt.Errorf("%w: truncated WebP file", ErrInvalid)
+	}
+	body := data[12 : 8+size]
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	hasImage := false
+
+	for i := 0; i < len(body); {
+		if i+8 > len(body) {
+			return nil, fmt.Errorf("%w: malformed WebP chunk", ErrInvalid)
+		}
+		fourcc := string(body[i : i+4])
+		n := int(binary.LittleEndian.Uint32(body[i+4:]))
+		end := i + 8 + n
+		if end > len(body) {
+			return nil, fmt.Errorf("%w: malformed WebP chunk", ErrInvalid)
+		}
+		padded := end + n%2
+		if padded > len(body) {
+			padded = len(body)
+		}
+
+		switch fourcc {
+		case "EXIF", "XMP ":
+			// Dropped
+		case "VP8X":
+			chunk := append([]byte(nil), body[i:padded]...)
+			if n > 0 {
+				chunk[8] &^= webpFlagEXIF | webpFlagXMP
+			}
+			out = append(out, chunk...)
+		default:
+			if fourcc == "VP8 " || fourcc == "VP8L" || fourcc == "ANIM" {
+				hasImage = true
+			}
+			out = append(out, body[i:padded]...)
+		}
+		i = padded
+	}
+
+	if !hasImage {
+		return nil, fmt.Errorf("%w: WebP file has no image data", ErrInvalid)
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
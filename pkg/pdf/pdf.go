@@ -0,0 +1,77 @@
+// Package pdf renders HTML documents to PDF with a headless Chromium binary.
+// Chromium performs full complex-script shaping, so Bangla text renders correctly
+// as long as a Bengali font (e.g. Noto Sans Bengali) is installed in the container.
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// ErrRendererUnavailable is returned when the configured Chromium binary cannot be found
+var ErrRendererUnavailable = errors.New("pdf renderer is not available on this server")
+
+// Renderer converts HTML to PDF
+type Renderer struct {
+	binary  string
+	timeout time.Duration
+}
+
+// NewRenderer creates a renderer using the given Chromium/Chrome executable
+func NewRenderer(binary string) *Renderer {
+	return &Renderer{binary: binary, timeout: 60 * time.Second}
+}
+
+// Available reports whether the renderer binary can be found
+func (r *Renderer) Available() bool {
+	if r == nil || r.binary == "" {
+		return false
+	}
+	_, err := exec.LookPath(r.binary)
+	return err == nil
+}
+
+// Render converts a complete HTML document to PDF bytes
+func (r *Renderer) Render(ctx context.Context, html []byte) ([]byte, error) {
+	if !r.Available() {
+		return nil, ErrRendererUnavailable
+	}
+
+	dir, err := os.MkdirTemp("", "bjdms-pdf-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "document.html")
+	out := filepath.Join(dir, "document.pdf")
+	if err := os.WriteFile(in, html, 0o600); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, r.binary,
+		"--headless",
+		"--disable-gpu",
+		"--no-sandbox",
+		"--no-pdf-header-footer",
+		"--user-data-dir="+filepath.Join(dir, "profile"),
+		"--print-to-pdf="+out,
+		"file://"+in,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pdf rendering failed: %w: %s", err, stderr.String())
+	}
+
+	return os.ReadFile(out)
+}
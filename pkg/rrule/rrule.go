@@ -0,0 +1,462 @@
+// Package rrule implements the subset of iCalendar (RFC 5545) recurrence rules needed to
+// schedule recurring work: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL,
+// BYMONTH, BYMONTHDAY and BYDAY (with ordinals such as 1MO or -1FR in monthly and yearly rules).
+// Weeks start on Monday. Occurrences keep the time of day of the start.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the base period of a rule
+type Frequency int
+
+// Frequencies
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = map[Frequency]string{Daily: "DAILY", Weekly: "WEEKLY", Monthly: "MONTHLY", Yearly: "YEARLY"}
+
+var weekdayNames = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// maxPeriods bounds the search for rules that never (or no longer) match
+const maxPeriods = 50000
+
+// Weekday is a BYDAY entry. N selects the nth such weekday of the month (negative counts from
+// the end); 0 means every one.
+type Weekday struct {
+	Day time.Weekday
+	N   int
+}
+
+// Rule is a parsed recurrence rule anchored at a start time
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByMonth    []time.Month
+	ByMonthDay []int
+	ByDay      []Weekday
+	Start      time.Time
+}
+
+// Parse reads an RRULE value such as "FREQ=MONTHLY;BYMONTHDAY=5" (an "RRULE:" prefix is allowed)
+func Parse(s string, start time.Time) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "RRULE:"), "rrule:")
+	if s == "" {
+		return nil, fmt.Errorf("recurrence rule is empty")
+	}
+
+	r := &Rule{Interval: 1, Start: start}
+	hasFreq := false
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		key, value = strings.ToUpper(strings.TrimSpace(key)), strings.ToUpper(strings.TrimSpace(value))
+
+		var err error
+		switch key {
+		case "FREQ":
+			hasFreq = true
+			err = r.parseFreq(value)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYMONTH":
+			err = eachInt(value, func(n int) error {
+				if n < 1 || n > 12 {
+					return fmt.Errorf("month %d is out of range", n)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(n))
+				return nil
+			})
+		case "BYMONTHDAY":
+			err = eachInt(value, func(n int) error {
+				if n == 0 || n < -31 || n > 31 {
+					return fmt.Errorf("day %d is out of range", n)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+				return nil
+			})
+		case "BYDAY":
+			err = r.parseByDay(value)
+		case "WKST":
+			if value != "MO" {
+				err = fmt.Errorf("only MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("recurrence rule part %s is not supported", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in recurrence rule: %v", key, err)
+		}
+	}
+
+	if !hasFreq {
+		return nil, fmt.Errorf("recurrence rule needs a FREQ")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, fmt.Errorf("recurrence rule cannot have both COUNT and UNTIL")
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rule) parseFreq(value string) error {
+	for f, name := range frequencyNames {
+		if name == value {
+			r.Freq = f
+			return nil
+		}
+	}
+	return fmt.Errorf("frequency %s is not supported", value)
+}
+
+func (r *Rule) parseUntil(value string) error {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, r.Start.Location()); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			r.Until = &t
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a date", value)
+}
+
+func (r *Rule) parseByDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return fmt.Errorf("%q is not a weekday", item)
+		}
+		day, ok := weekdayNames[item[len(item)-2:]]
+		if !ok {
+			return fmt.Errorf("%q is not a weekday", item)
+		}
+		n := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return fmt.Errorf("%q has an invalid ordinal", item)
+			}
+		}
+		r.ByDay = append(r.ByDay, Weekday{Day: day, N: n})
+	}
+	return nil
+}
+
+// validate rejects combinations RFC 5545 forbids or this package does not expand
+func (r *Rule) validate() error {
+	hasOrdinal := false
+	for _, d := range r.ByDay {
+		if d.N != 0 {
+			hasOrdinal = true
+		}
+	}
+	switch r.Freq {
+	case Daily, Weekly:
+		if hasOrdinal {
+			return fmt.Errorf("BYDAY ordinals are only allowed in monthly and yearly rules")
+		}
+		if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+			return fmt.Errorf("BYMONTHDAY is not allowed in a weekly rule")
+		}
+	case Yearly:
+		if hasOrdinal && len(r.ByMonth) == 0 {
+			return fmt.Errorf("BYDAY ordinals in a yearly rule need BYMONTH")
+		}
+	}
+	return nil
+}
+
+// String returns the rule in canonical RRULE form, without the start
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + frequencyNames[r.Freq]}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = weekdayCode(d.Day)
+			if d.N != 0 {
+				days[i] = strconv.Itoa(d.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// After returns the first occurrence strictly after t
+func (r *Rule) After(t time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.iterate(func(o time.Time) bool {
+		if o.After(t) {
+			next, found = o, true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// Next returns up to n occurrences strictly after t
+func (r *Rule) Next(t time.Time, n int) []time.Time {
+	var list []time.Time
+	if n <= 0 {
+		return list
+	}
+	r.iterate(func(o time.Time) bool {
+		if o.After(t) {
+			list = append(list, o)
+		}
+		return len(list) < n
+	})
+	return list
+}
+
+// iterate calls fn with every occurrence in order until fn returns false or the rule ends
+func (r *Rule) iterate(fn func(time.Time) bool) {
+	emitted := 0
+	for i := 0; i < maxPeriods; i++ {
+		for _, o := range r.expand(i) {
+			if o.Before(r.Start) {
+				continue
+			}
+			if r.Until != nil && o.After(*r.Until) {
+				return
+			}
+			emitted++
+			if !fn(o) || (r.Count > 0 && emitted >= r.Count) {
+				return
+			}
+		}
+	}
+}
+
+// expand returns the sorted occurrences within the i-th period of the rule
+func (r *Rule) expand(i int) []time.Time {
+	s := r.Start
+	step := i * r.Interval
+	var days []time.Time
+
+	switch r.Freq {
+	case Daily:
+		d := r.at(s.Year(), s.Month(), s.Day()+step)
+		if r.matchMonth(d.Month()) && r.matchMonthDay(d) && r.matchWeekday(d) {
+			days = append(days, d)
+		}
+	case Weekly:
+		// Monday of the start week, then every Interval weeks
+		offset := (int(s.Weekday()) + 6) % 7
+		monday := r.at(s.Year(), s.Month(), s.Day()-offset+7*step)
+		for k := 0; k < 7; k++ {
+			d := monday.AddDate(0, 0, k)
+			if !r.matchMonth(d.Month()) {
+				continue
+			}
+			if len(r.ByDay) == 0 && d.Weekday() == s.Weekday() || len(r.ByDay) > 0 && r.matchWeekday(d) {
+				days = append(days, d)
+			}
+		}
+	case Monthly:
+		first := r.at(s.Year(), s.Month()+time.Month(step), 1)
+		if r.matchMonth(first.Month()) {
+			days = r.monthDays(first)
+		}
+	case Yearly:
+		year := s.Year() + step
+		months := r.ByMonth
+		if len(months) == 0 {
+			if len(r.ByMonthDay) > 0 || len(r.ByDay) > 0 {
+				months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			} else {
+				months = []time.Month{s.Month()}
+			}
+		}
+		for _, m := range months {
+			days = append(days, r.monthDays(r.at(year, m, 1))...)
+		}
+	}
+
+	sort.Slice(days, func(a, b int) bool { return days[a].Before(days[b]) })
+	return dedupe(days)
+}
+
+// monthDays returns the matching days of the month starting at first
+func (r *Rule) monthDays(first time.Time) []time.Time {
+	year, month := first.Year(), first.Month()
+	length := daysIn(year, month)
+	var days []time.Time
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if r.Start.Day() <= length {
+			days = append(days, r.at(year, month, r.Start.Day()))
+		}
+		return days
+	}
+
+	for d := 1; d <= length; d++ {
+		t := r.at(year, month, d)
+		if len(r.ByMonthDay) > 0 && !r.matchMonthDay(t) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchWeekdayInMonth(t, length) {
+			continue
+		}
+		days = append(days, t)
+	}
+	return days
+}
+
+func (r *Rule) matchMonth(m time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, bm := range r.ByMonth {
+		if bm == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	length := daysIn(t.Year(), t.Month())
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || d < 0 && length+d+1 == t.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Day == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWeekdayInMonth checks BYDAY entries, counting ordinals within the month
+func (r *Rule) matchWeekdayInMonth(t time.Time, length int) bool {
+	for _, d := range r.ByDay {
+		if d.Day != t.Weekday() {
+			continue
+		}
+		switch {
+		case d.N == 0:
+			return true
+		case d.N > 0 && (t.Day()-1)/7+1 == d.N:
+			return true
+		case d.N < 0 && (length-t.Day())/7+1 == -d.N:
+			return true
+		}
+	}
+	return false
+}
+
+// at builds a date in the start's location with the start's time of day; out-of-range days
+// and months are normalized by time.Date
+func (r *Rule) at(year int, month time.Month, day int) time.Time {
+	s := r.Start
+	return time.Date(year, month, day, s.Hour(), s.Minute(), s.Second(), 0, s.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func dedupe(days []time.Time) []time.Time {
+	out := days[:0]
+	for i, d := range days {
+		if i == 0 || !d.Equal(days[i-1]) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func eachInt(value string, fn func(int) error) error {
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return fmt.Errorf("%q is not a number", item)
+		}
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func weekdayCode(d time.Weekday) string {
+	for code, wd := range weekdayNames {
+		if wd == d {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package rrule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestRuleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		n     int
+		want  []time.Time
+	}{
+		{
+			name:  "fifth of every month skips a start after the 5th",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=5",
+			start: date(2025, 1, 10, 9),
+			n:     3,
+			want:  []time.Time{date(2025, 2, 5, 9), date(2025, 3, 5, 9), date(2025, 4, 5, 9)},
+		},
+		{
+			name:  "last friday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: date(2025, 1, 1, 10),
+			n:     4,
+			want:  []time.Time{date(2025, 1, 31, 10), date(2025, 2, 28, 10), date(2025, 3, 28, 10), date(2025, 4, 25, 10)},
+		},
+		{
+			name:  "first monday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=1MO",
+			start: date(2025, 1, 1, 8),
+			n:     3,
+			want:  []time.Time{date(2025, 1, 6, 8), date(2025, 2, 3, 8), date(2025, 3, 3, 8)},
+		},
+		{
+			name:  "monthly from the 31st skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2025, 1, 31, 12),
+			n:     4,
+			want:  []time.Time{date(2025, 1, 31, 12), date(2025, 3, 31, 12), date(2025, 5, 31, 12), date(2025, 7, 31, 12)},
+		},
+		{
+			name:  "BYMONTHDAY=31 skips short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2025, 1, 1, 12),
+			n:     3,
+			want:  []time.Time{date(2025, 1, 31, 12), date(2025, 3, 31, 12), date(2025, 5, 31, 12)},
+		},
+		{
+			name:  "last day of the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: date(2024, 1, 1, 0),
+			n:     3,
+			want:  []time.Time{date(2024, 1, 31, 0), date(2024, 2, 29, 0), date(2024, 3, 31, 0)},
+		},
+		{
+			name:  "yearly on the 29th of february only in leap years",
+			rule:  "FREQ=YEARLY",
+			start: date(2024, 2, 29, 9),
+			n:     2,
+			want:  []time.Time{date(2024, 2, 29, 9), date(2028, 2, 29, 9)},
+		},
+		{
+			name:  "COUNT stops the rule",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: date(2025, 3, 1, 7),
+			n:     5,
+			want:  []time.Time{date(2025, 3, 1, 7), date(2025, 3, 2, 7), date(2025, 3, 3, 7)},
+		},
+		{
+			name:  "date-only UNTIL includes that day",
+			rule:  "FREQ=WEEKLY;UNTIL=20250315",
+			start: date(2025, 3, 1, 18),
+			n:     5,
+			want:  []time.Time{date(2025, 3, 1, 18), date(2025, 3, 8, 18), date(2025, 3, 15, 18)},
+		},
+		{
+			name:  "UNTIL with a time excludes later occurrences that day",
+			rule:  "FREQ=DAILY;UNTIL=20250303T120000Z",
+			start: date(2025, 3, 1, 18),
+			n:     5,
+			want:  []time.Time{date(2025, 3, 1, 18), date(2025, 3, 2, 18)},
+		},
+		{
+			name:  "every other week on monday and thursday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: date(2025, 3, 3, 9),
+			n:     4,
+			want:  []time.Time{date(2025, 3, 3, 9), date(2025, 3, 6, 9), date(2025, 3, 17, 9), date(2025, 3, 20, 9)},
+		},
+		{
+			name:  "quarterly",
+			rule:  "FREQ=MONTHLY;INTERVAL=3",
+			start: date(2025, 1, 15, 10),
+			n:     3,
+			want:  []time.Time{date(2025, 1, 15, 10), date(2025, 4, 15, 10), date(2025, 7, 15, 10)},
+		},
+		{
+			name:  "every third day",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: date(2025, 2, 27, 6),
+			n:     3,
+			want:  []time.Time{date(2025, 2, 27, 6), date(2025, 3, 2, 6), date(2025, 3, 5, 6)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule, tt.start)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			got := r.Next(tt.start.Add(-time.Second), tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Next() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRuleAfter(t *testing.T) {
+	r, err := Parse("FREQ=MONTHLY;BYMONTHDAY=5;COUNT=2", date(2025, 1, 1, 9))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, ok := r.After(date(2025, 1, 5, 9))
+	if !ok || !next.Equal(date(2025, 2, 5, 9)) {
+		t.Errorf("After(first occurrence) = %v, %v, want %v", next, ok, date(2025, 2, 5, 9))
+	}
+	if next, ok := r.After(date(2025, 2, 5, 9)); ok {
+		t.Errorf("After(last occurrence) = %v, want none", next)
+	}
+}
+
+func TestParse(t *testing.T) {
+	start := date(2025, 1, 1, 9)
+	tests := []struct {
+		name    string
+		rule    string
+		want    string // Canonical form
+		wantErr string
+	}{
+		{name: "prefix and lower case", rule: "rrule:freq=monthly;bymonthday=-1", want: "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{name: "interval of one is dropped", rule: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,FR", want: "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{name: "yearly ordinal with month", rule: "FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU", want: "FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU"},
+		{name: "empty", rule: " ", wantErr: "empty"},
+		{name: "no frequency", rule: "BYMONTHDAY=5", wantErr: "needs a FREQ"},
+		{name: "unsupported frequency", rule: "FREQ=HOURLY", wantErr: "not supported"},
+		{name: "unsupported part", rule: "FREQ=DAILY;BYHOUR=9", wantErr: "BYHOUR is not supported"},
+		{name: "zero interval", rule: "FREQ=DAILY;INTERVAL=0", wantErr: "invalid INTERVAL"},
+		{name: "day zero", rule: "FREQ=MONTHLY;BYMONTHDAY=0", wantErr: "out of range"},
+		{name: "COUNT and UNTIL", rule: "FREQ=DAILY;COUNT=2;UNTIL=20250301", wantErr: "both COUNT and UNTIL"},
+		{name: "ordinal in weekly rule", rule: "FREQ=WEEKLY;BYDAY=1MO", wantErr: "only allowed in monthly and yearly"},
+		{name: "month day in weekly rule", rule: "FREQ=WEEKLY;BYMONTHDAY=5", wantErr: "not allowed in a weekly rule"},
+		{name: "yearly ordinal without month", rule: "FREQ=YEARLY;BYDAY=1MO", wantErr: "need BYMONTH"},
+		{name: "bad weekday", rule: "FREQ=MONTHLY;BYDAY=XX", wantErr: "not a weekday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule, start)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStore keeps objects on the local filesystem. Downloads go through the API's
+// signed file route (see DownloadHandler).
+type LocalStore struct {
+	root      string
+	publicURL string
+	key       []byte
+}
+
+// NewLocalStore creates a filesystem store rooted at path
+func NewLocalStore(path, publicURL, signingKey string) (*LocalStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("local storage path is required")
+	}
+	if signingKey == "" {
+		return nil, fmt.Errorf("local storage signing key is required")
+	}
+	if err := os.MkdirAll(path, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStore{
+		root:      path,
+		publicURL: strings.TrimRight(publicURL, "/"),
+		key:       []byte(signingKey),
+	}, nil
+}
+
+func (s *LocalStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+// Put writes the object atomically via a temporary file
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, info ObjectInfo) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	dst := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size > 0 && n != info.Size {
+		return fmt.Errorf("short write: expected %d bytes, got %d", info.Size, n)
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// Get opens an object for reading
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	if err := validKey(key); err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	// Content type is sniffed rather than stored alongside the file
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	return f, &ObjectInfo{Key: key, Size: st.Size(), ContentType: http.DetectContentType(head[:n])}, nil
+}
+
+// Exists reports whether an object is present
+func (s *LocalStore) Exists(ctx context.Context, key string) (bool, error) {
+	if err := validKey(key); err != nil {
+		return false, err
+	}
+	_, err := os.Stat(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete removes an object
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// SignedURL returns a download URL valid until now+expires
+func (s *LocalStore) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	exp := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", exp)
+	q.Set("sig", s.sign(key, exp))
+	return fmt.Sprintf("%s/%s?%s", s.publicURL, key, q.Encode()), nil
+}
+
+func (s *LocalStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signed URL's expiry and signature
+func (s *LocalStore) Verify(key, expires, sig string) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(s.sign(key, expires)), []byte(sig))
+}
+
+// DownloadHandler serves signed local-storage URLs. Mount it under the PublicURL path with a
+// wildcard route (e.g. r.Get("/files/*", ...)); the object key is taken from the wildcard.
+func (s *LocalStore) DownloadHandler(keyFromRequest func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := keyFromRequest(r)
+		q := r.URL.Query()
+		if validKey(key) != nil || !s.Verify(key, q.Get("expires"), q.Get("sig")) {
+			http.Error(w, "invalid or expired link", http.StatusForbidden)
+			return
+		}
+
+		rc, info, err := s.Get(r.Context(), key)
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to read file", http.StatusInternalServerError)
+			return
+		}
+		defer rc.Close()
+
+		w.Header().Set("Content-Type", info.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		if name := q.Get("name"); name != "" {
+			w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+		}
+		io.Copy(w, rc)
+	}
+}
@@ -0,0 +1,112 @@
+// Package storage provides a pluggable object store for uploaded files (activity proofs,
+// complaint evidence, finance receipts). Objects are content-addressed by SHA-256, so
+// identical uploads are stored once. Backends: local filesystem and S3-compatible (AWS S3, MinIO).
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Backend names
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	SHA256      string // Hex digest; optional on Put, lets backends verify the payload
+}
+
+// Store is implemented by every storage backend
+type Store interface {
+	// Put stores the object. info.Size must be set; r must yield exactly that many bytes.
+	Put(ctx context.Context, key string, r io.Reader, info ObjectInfo) error
+	// Get opens an object for reading. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Exists reports whether an object is present
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a time-limited download URL for the object
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// Config selects and configures a backend
+type Config struct {
+	Backend string
+
+	// Local backend
+	LocalPath  string
+	PublicURL  string // Base URL of the signed download route, e.g. https://api.example.org/api/v1/files
+	SigningKey string // HMAC key for local signed URLs
+
+	// S3 backend
+	S3Endpoint       string // e.g. https://s3.ap-south-1.amazonaws.com or http://minio:9000
+	S3PublicEndpoint string // Endpoint used in signed URLs when clients reach the store on another address
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3PathStyle      bool // Required for MinIO
+}
+
+// New creates the configured backend
+func New(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocalStore(cfg.LocalPath, cfg.PublicURL, cfg.SigningKey)
+	case BackendS3:
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// ContentKey returns the content-addressed key for a SHA-256 hex digest
+func ContentKey(sha256Hex string) string {
+	return fmt.Sprintf("sha256/%s/%s", sha256Hex[:2], sha256Hex)
+}
+
+// validKey rejects keys that could escape the store root or confuse URL signing
+func validKey(key string) error {
+	if key == "" || key[0] == '/' {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		ok := c == '/' || c == '-' || c == '_' || c == '.' ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !ok {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	for _, seg := range splitPath(key) {
+		if seg == "" || seg == "." || seg == ".." {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return nil
+}
+
+func splitPath(key string) []string {
+	var segs []string
+	start := 0
+	for i := 0; i <= len(key); i++ {
+		if i == len(key) || key[i] == '/' {
+			segs = append(segs, key[start:i])
+			start = i + 1
+		}
+	}
+	return segs
+}
@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Upload limits
+const (
+	DefaultMaxUploadBytes = 20 << 20 // 20 MiB per file
+	DefaultMaxFiles       = 10
+	DefaultURLExpiry      = 15 * time.Minute
+	uploadReadTimeout     = 5 * time.Minute
+	sniffLen              = 512
+)
+
+// DefaultAllowedTypes are the sniffed MIME types accepted for evidence and receipts
+var DefaultAllowedTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"image/gif",
+	"application/pdf",
+	"video/mp4",
+}
+
+// Upload errors
+var (
+	ErrTooLarge       = errors.New("file exceeds the maximum upload size")
+	ErrTypeNotAllowed = errors.New("file type is not allowed")
+	ErrNoFiles        = errors.New("no files were uploaded")
+	ErrTooManyFiles   = errors.New("too many files in one request")
+	ErrNotMultipart   = errors.New("request must be multipart/form-data")
+	ErrEmptyFile      = errors.New("file is empty")
+	ErrTypeMismatch   = errors.New("file content does not match its declared type")
+)
+
+// File is the result of storing one upload
+type File struct {
+	Key          string `json:"key"`
+	SHA256       string `json:"sha256"`
+	Size         int64  `json:"size"`
+	ContentType  string `json:"content_type"` // Sniffed from content, not taken from the client
+	DeclaredType string `json:"declared_type,omitempty"`
+	OriginalName string `json:"original_name,omitempty"`
+	Deduplicated bool   `json:"deduplicated"` // Identical content was already stored
+}
+
+// Uploader streams uploads into a Store with size and type limits, and signs download links
+type Uploader struct {
+	store     Store
+	maxBytes  int64
+	maxFiles  int
+	urlExpiry time.Duration
+	allowed   map[string]bool
+}
+
+// NewUploader creates an uploader. Zero values use the defaults.
+func NewUploader(store Store, maxBytes int64, urlExpiry time.Duration) *Uploader {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxUploadBytes
+	}
+	if urlExpiry <= 0 {
+		urlExpiry = DefaultURLExpiry
+	}
+	u := &Uploader{store: store, maxBytes: maxBytes, maxFiles: DefaultMaxFiles, urlExpiry: urlExpiry}
+	return u.WithTypes(DefaultAllowedTypes...)
+}
+
+// WithTypes returns a copy of the uploader that only accepts the given sniffed MIME types
+func (u *Uploader) WithTypes(types ...string) *Uploader {
+	c := *u
+	c.allowed = make(map[string]bool, len(types))
+	for _, t := range types {
+		c.allowed[t] = true
+	}
+	return &c
+}
+
+// Store returns the underlying store
+func (u *Uploader) Store() Store {
+	return u.store
+}
+
+// SignedURL returns a download link for key valid for the configured expiry
+func (u *Uploader) SignedURL(ctx context.Context, key string) (string, error) {
+	return u.store.SignedURL(ctx, key, u.urlExpiry)
+}
+
+// FromRequest streams every file part named field from a multipart request into the store.
+// Parts are processed one at a time without buffering the whole request in memory.
+func (u *Uploader) FromRequest(w http.ResponseWriter, r *http.Request, field string) ([]*File, error) {
+	// Bound the whole body: every file at its limit plus some room for headers and small fields
+	r.Body = http.MaxBytesReader(w, r.Body, int64(u.maxFiles)*u.maxBytes+1<<20)
+
+	// Slow mobile connections need longer than the server's default read timeout
+	_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(uploadReadTimeout))
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, ErrNotMultipart
+	}
+
+	var files []*File
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				return files, ErrTooLarge
+			}
+			return files, fmt.Errorf("%w: %v", ErrNotMultipart, err)
+		}
+		if part.FormName() != field || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+		if len(files) >= u.maxFiles {
+			part.Close()
+			return files, ErrTooManyFiles
+		}
+
+		f, err := u.Accept(r.Context(), part, part.FileName(), part.Header.Get("Content-Type"))
+		part.Close()
+		if err != nil {
+			return files, err
+		}
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		return nil, ErrNoFiles
+	}
+	return files, nil
+}
+
+// Accept stores a single upload. The content is spooled to a temporary file while hashing,
+// its type is sniffed from the first bytes, and identical content already in the store is reused.
+func (u *Uploader) Accept(ctx context.Context, r io.Reader, filename, declaredType string) (*File, error) {
+	tmp, err := os.CreateTemp("", "bjdms-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	// 1. Spool with a hard size limit, hashing as we go
+	hasher := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hasher), io.LimitReader(r, u.maxBytes+1))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, ErrTooLarge
+		}
+		return nil, err
+	}
+	if n > u.maxBytes {
+		return nil, ErrTooLarge
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyFile, filename)
+	}
+
+	// 2. Sniff the real content type
+	head := make([]byte, sniffLen)
+	hn, err := tmp.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	contentType := SniffContentType(head[:hn])
+	if !u.allowed[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrTypeNotAllowed, contentType)
+	}
+	if claimed := claimedType(filename, declaredType); claimed != "" && claimed != contentType {
+		return nil, fmt.Errorf("%w: %s is %s, not %s", ErrTypeMismatch, filename, contentType, claimed)
+	}
+
+	f := &File{
+		SHA256:       hex.EncodeToString(hasher.Sum(nil)),
+		Size:         n,
+		ContentType:  contentType,
+		DeclaredType: declaredType,
+		OriginalName: sanitizeFilename(filename),
+	}
+	f.Key = ContentKey(f.SHA256)
+
+	// 3. Deduplicate by content
+	exists, err := u.store.Exists(ctx, f.Key)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		f.Deduplicated = true
+		return f, nil
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	info := ObjectInfo{Key: f.Key, Size: n, ContentType: contentType, SHA256: f.SHA256}
+	if err := u.store.Put(ctx, f.Key, tmp, info); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+	return f, nil
+}
+
+// SniffContentType detects a MIME type from leading bytes, without parameters
+func SniffContentType(head []byte) string {
+	ct := http.DetectContentType(head)
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	return strings.TrimSpace(ct)
+}
+
+// claimedType returns the type a client claims for a file, from its Content-Type header or,
+// failing that, its extension. Generic or unknown claims return "".
+func claimedType(filename, declared string) string {
+	ct := declared
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	ct = strings.ToLower(strings.TrimSpace(ct))
+	if ct == "" || ct == "application/octet-stream" {
+		ct = mime.TypeByExtension(strings.ToLower(filepath.Ext(filename)))
+		if i := strings.IndexByte(ct, ';'); i >= 0 {
+			ct = ct[:i]
+		}
+	}
+	switch ct {
+	case "image/jpg", "image/pjpeg":
+		return "image/jpeg"
+	case "application/octet-stream":
+		return ""
+	}
+	return ct
+}
+
+// sanitizeFilename keeps only the base name and strips control characters
+func sanitizeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 200 {
+		name = string(runes[:200])
+	}
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+// UploadError maps upload errors to an HTTP status code
+func UploadError(err error) int {
+	switch {
+	case errors.Is(err, ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrTypeNotAllowed), errors.Is(err, ErrTypeMismatch):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrNotMultipart), errors.Is(err, ErrNoFiles), errors.Is(err, ErrTooManyFiles),
+		errors.Is(err, ErrEmptyFile):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}